@@ -3,6 +3,7 @@ package chef
 // TODO: Add a call to retrive all ACLs.
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -97,8 +98,13 @@ func ACLAdminAccess(acl *ACL) (err error) {
 // Older versions of the Chef server only include ACLs for "groups" and "actors."
 // If you're using a more recent version then the contents of "actors" is split up in "users" and "clients."
 func (a *ACLService) Get(subkind string, name string) (acl ACL, err error) {
+	return a.GetWithContext(context.Background(), subkind, name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (a *ACLService) GetWithContext(ctx context.Context, subkind string, name string) (acl ACL, err error) {
 	url := fmt.Sprintf("%s/%s/_acl?detail=granular", subkind, name)
-	err = a.client.magicRequestDecoder("GET", url, nil, &acl)
+	err = a.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &acl)
 	return
 }
 
@@ -115,6 +121,11 @@ func (a *ACLService) Get(subkind string, name string) (acl ACL, err error) {
 // On newer versions of the Chef server you may need to replace "actors" with an empty list. Looks like the
 // actors list is only included for backwards compatibility but can't be in the PUT request.
 func (a *ACLService) Put(subkind, name string, perm string, item *ACL) (err error) {
+	return a.PutWithContext(context.Background(), subkind, name, perm, item)
+}
+
+// PutWithContext is Put with a context for cancellation and deadlines.
+func (a *ACLService) PutWithContext(ctx context.Context, subkind, name string, perm string, item *ACL) (err error) {
 	url := fmt.Sprintf("%s/%s/_acl/%s", subkind, name, perm)
 	err = ACLAdminAccess(item)
 	if err != nil {
@@ -125,6 +136,6 @@ func (a *ACLService) Put(subkind, name string, perm string, item *ACL) (err erro
 		return
 	}
 
-	err = a.client.magicRequestDecoder("PUT", url, body, nil)
+	err = a.client.magicRequestDecoderWithContext(ctx, "PUT", url, body, nil)
 	return
 }
@@ -0,0 +1,468 @@
+// Code generated by go run ./internal/genapi. DO NOT EDIT.
+
+package chef
+
+import (
+	"context"
+)
+
+// ACLsAPI is the method set of ACLService, the type of Client.ACLs.
+// Assign another implementation such as a FakeACLs to Client.ACLs to replace it.
+type ACLsAPI interface {
+	Get(subkind string, name string) (ACL, error)
+	GetWithContext(ctx context.Context, subkind string, name string) (ACL, error)
+	Put(subkind string, name string, perm string, item *ACL) error
+	PutWithContext(ctx context.Context, subkind string, name string, perm string, item *ACL) error
+}
+
+// AssociationsAPI is the method set of AssociationService, the type of Client.Associations.
+// Assign another implementation such as a FakeAssociations to Client.Associations to replace it.
+type AssociationsAPI interface {
+	AcceptInvite(id string) (string, error)
+	AcceptInviteWithContext(ctx context.Context, id string) (string, error)
+	Add(addme AddNow) error
+	AddWithContext(ctx context.Context, addme AddNow) error
+	Delete(name string) (OrgUser, error)
+	DeleteInvite(id string) (RescindInvite, error)
+	DeleteInviteWithContext(ctx context.Context, id string) (RescindInvite, error)
+	DeleteWithContext(ctx context.Context, name string) (OrgUser, error)
+	Get(name string) (OrgUser, error)
+	GetWithContext(ctx context.Context, name string) (OrgUser, error)
+	Invite(invite Request) (Association, error)
+	InviteId(user string) (string, error)
+	InviteIdWithContext(ctx context.Context, user string) (string, error)
+	InviteWithContext(ctx context.Context, invite Request) (Association, error)
+	List() ([]OrgUserListEntry, error)
+	ListInvites() ([]Invite, error)
+	ListInvitesWithContext(ctx context.Context) ([]Invite, error)
+	ListWithContext(ctx context.Context) ([]OrgUserListEntry, error)
+}
+
+// AuthenticateUserAPI is the method set of AuthenticateUserService, the type of Client.AuthenticateUser.
+// Assign another implementation such as a FakeAuthenticateUser to Client.AuthenticateUser to replace it.
+type AuthenticateUserAPI interface {
+	Authenticate(authenticate_request Authenticate) error
+	AuthenticateWithContext(ctx context.Context, authenticate_request Authenticate) error
+}
+
+// ClientsAPI is the method set of ApiClientService, the type of Client.Clients.
+// Assign another implementation such as a FakeClients to Client.Clients to replace it.
+type ClientsAPI interface {
+	AddKey(name string, keyadd AccessKey) (KeyItem, error)
+	AddKeyWithContext(ctx context.Context, name string, keyadd AccessKey) (KeyItem, error)
+	Create(client ApiNewClient) (*ApiClientCreateResult, error)
+	CreateWithContext(ctx context.Context, client ApiNewClient) (*ApiClientCreateResult, error)
+	Delete(name string) error
+	DeleteKey(name string, keyname string) (AccessKey, error)
+	DeleteKeyWithContext(ctx context.Context, name string, keyname string) (AccessKey, error)
+	DeleteWithContext(ctx context.Context, name string) error
+	Get(name string) (ApiClient, error)
+	GetKey(name string, keyname string) (AccessKey, error)
+	GetKeyWithContext(ctx context.Context, name string, keyname string) (AccessKey, error)
+	GetWithContext(ctx context.Context, name string) (ApiClient, error)
+	List() (ApiClientListResult, error)
+	ListKeys(name string) ([]KeyItem, error)
+	ListKeysWithContext(ctx context.Context, name string) ([]KeyItem, error)
+	ListWithContext(ctx context.Context) (ApiClientListResult, error)
+	Update(name string, client ApiNewClient) (*ApiClient, error)
+	UpdateKey(name string, keyname string, keyupd AccessKey) (AccessKey, error)
+	UpdateKeyWithContext(ctx context.Context, name string, keyname string, keyupd AccessKey) (AccessKey, error)
+	UpdateWithContext(ctx context.Context, name string, client ApiNewClient) (*ApiClient, error)
+}
+
+// ContainersAPI is the method set of ContainerService, the type of Client.Containers.
+// Assign another implementation such as a FakeContainers to Client.Containers to replace it.
+type ContainersAPI interface {
+	Create(container Container) (*ContainerCreateResult, error)
+	CreateWithContext(ctx context.Context, container Container) (*ContainerCreateResult, error)
+	Delete(name string) error
+	DeleteWithContext(ctx context.Context, name string) error
+	Get(name string) (Container, error)
+	GetWithContext(ctx context.Context, name string) (Container, error)
+	List() (ContainerListResult, error)
+	ListWithContext(ctx context.Context) (ContainerListResult, error)
+}
+
+// CookbookArtifactsAPI is the method set of CBAService, the type of Client.CookbookArtifacts.
+// Assign another implementation such as a FakeCookbookArtifacts to Client.CookbookArtifacts to replace it.
+type CookbookArtifactsAPI interface {
+	DownloadTo(name string, id string, localDir string) error
+	DownloadToWithContext(ctx context.Context, name string, id string, localDir string) error
+	Get(name string) (CBAGetResponse, error)
+	GetVersion(name string, id string) (CBADetail, error)
+	GetVersionWithContext(ctx context.Context, name string, id string) (CBADetail, error)
+	GetWithContext(ctx context.Context, name string) (CBAGetResponse, error)
+	List() (CBAGetResponse, error)
+	ListWithContext(ctx context.Context) (CBAGetResponse, error)
+}
+
+// CookbooksAPI is the method set of CookbookService, the type of Client.Cookbooks.
+// Assign another implementation such as a FakeCookbooks to Client.Cookbooks to replace it.
+type CookbooksAPI interface {
+	Delete(name string, version string) error
+	DeleteWithContext(ctx context.Context, name string, version string) error
+	Download(name string, version string) error
+	DownloadAt(name string, version string, localDir string) error
+	DownloadTo(name string, version string, localDir string) error
+	DownloadToWithContext(ctx context.Context, name string, version string, localDir string) error
+	DownloadWithContext(ctx context.Context, name string, version string) error
+	Get(name string) (CookbookVersion, error)
+	GetAvailableVersions(name string, numVersions string) (CookbookListResult, error)
+	GetAvailableVersionsWithContext(ctx context.Context, name string, numVersions string) (CookbookListResult, error)
+	GetVersion(name string, version string) (Cookbook, error)
+	GetVersionWithContext(ctx context.Context, name string, version string) (Cookbook, error)
+	GetWithContext(ctx context.Context, name string) (CookbookVersion, error)
+	List() (CookbookListResult, error)
+	ListAllRecipes() (CookbookRecipesResult, error)
+	ListAllRecipesWithContext(ctx context.Context) (CookbookRecipesResult, error)
+	ListAvailableVersions(numVersions string) (CookbookListResult, error)
+	ListAvailableVersionsWithContext(ctx context.Context, numVersions string) (CookbookListResult, error)
+	ListWithContext(ctx context.Context) (CookbookListResult, error)
+}
+
+// DataBagsAPI is the method set of DataBagService, the type of Client.DataBags.
+// Assign another implementation such as a FakeDataBags to Client.DataBags to replace it.
+type DataBagsAPI interface {
+	Create(databag *DataBag) (*DataBagCreateResult, error)
+	CreateItem(databagName string, databagItem DataBagItem) error
+	CreateItemWithContext(ctx context.Context, databagName string, databagItem DataBagItem) error
+	CreateWithContext(ctx context.Context, databag *DataBag) (*DataBagCreateResult, error)
+	Delete(name string) (*DataBag, error)
+	DeleteItem(databagName string, databagItem string) error
+	DeleteItemWithContext(ctx context.Context, databagName string, databagItem string) error
+	DeleteWithContext(ctx context.Context, name string) (*DataBag, error)
+	GetItem(databagName string, databagItem string) (DataBagItem, error)
+	GetItemWithContext(ctx context.Context, databagName string, databagItem string) (DataBagItem, error)
+	List() (*DataBagListResult, error)
+	ListItems(name string) (*DataBagListResult, error)
+	ListItemsWithContext(ctx context.Context, name string) (*DataBagListResult, error)
+	ListWithContext(ctx context.Context) (*DataBagListResult, error)
+	UpdateItem(databagName string, databagItemId string, databagItem DataBagItem) error
+	UpdateItemWithContext(ctx context.Context, databagName string, databagItemId string, databagItem DataBagItem) error
+}
+
+// EnvironmentsAPI is the method set of EnvironmentService, the type of Client.Environments.
+// Assign another implementation such as a FakeEnvironments to Client.Environments to replace it.
+type EnvironmentsAPI interface {
+	Create(environment *Environment) (*EnvironmentResult, error)
+	CreateWithContext(ctx context.Context, environment *Environment) (*EnvironmentResult, error)
+	Delete(name string) (*Environment, error)
+	DeleteWithContext(ctx context.Context, name string) (*Environment, error)
+	Get(name string) (*Environment, error)
+	GetWithContext(ctx context.Context, name string) (*Environment, error)
+	List() (*EnvironmentResult, error)
+	ListCookbooks(name string, numVersions string) (EnvironmentCookbookResult, error)
+	ListCookbooksWithContext(ctx context.Context, name string, numVersions string) (EnvironmentCookbookResult, error)
+	ListRecipes(name string) (EnvironmentRecipesResult, error)
+	ListRecipesWithContext(ctx context.Context, name string) (EnvironmentRecipesResult, error)
+	ListWithContext(ctx context.Context) (*EnvironmentResult, error)
+	Put(environment *Environment) (*Environment, error)
+	PutWithContext(ctx context.Context, environment *Environment) (*Environment, error)
+}
+
+// GroupsAPI is the method set of GroupService, the type of Client.Groups.
+// Assign another implementation such as a FakeGroups to Client.Groups to replace it.
+type GroupsAPI interface {
+	Create(group Group) (*GroupResult, error)
+	CreateWithContext(ctx context.Context, group Group) (*GroupResult, error)
+	Delete(name string) error
+	DeleteWithContext(ctx context.Context, name string) error
+	Get(name string) (Group, error)
+	GetWithContext(ctx context.Context, name string) (Group, error)
+	List() (map[string]string, error)
+	ListWithContext(ctx context.Context) (map[string]string, error)
+	Update(g GroupUpdate) (GroupUpdate, error)
+	UpdateWithContext(ctx context.Context, g GroupUpdate) (GroupUpdate, error)
+}
+
+// LicenseAPI is the method set of LicenseService, the type of Client.License.
+// Assign another implementation such as a FakeLicense to Client.License to replace it.
+type LicenseAPI interface {
+	Get() (License, error)
+	GetWithContext(ctx context.Context) (License, error)
+}
+
+// NodesAPI is the method set of NodeService, the type of Client.Nodes.
+// Assign another implementation such as a FakeNodes to Client.Nodes to replace it.
+type NodesAPI interface {
+	Delete(name string) error
+	DeleteWithContext(ctx context.Context, name string) error
+	Get(name string) (Node, error)
+	GetWithContext(ctx context.Context, name string) (Node, error)
+	Head(name string) error
+	HeadWithContext(ctx context.Context, name string) error
+	List() (map[string]string, error)
+	ListWithContext(ctx context.Context) (map[string]string, error)
+	Post(node Node) (*NodeResult, error)
+	PostWithContext(ctx context.Context, node Node) (*NodeResult, error)
+	Put(n Node) (Node, error)
+	PutWithContext(ctx context.Context, n Node) (Node, error)
+}
+
+// OrganizationsAPI is the method set of OrganizationService, the type of Client.Organizations.
+// Assign another implementation such as a FakeOrganizations to Client.Organizations to replace it.
+type OrganizationsAPI interface {
+	Create(organization Organization) (OrganizationResult, error)
+	CreateWithContext(ctx context.Context, organization Organization) (OrganizationResult, error)
+	Delete(name string) error
+	DeleteWithContext(ctx context.Context, name string) error
+	Get(name string) (Organization, error)
+	GetWithContext(ctx context.Context, name string) (Organization, error)
+	List() (map[string]string, error)
+	ListWithContext(ctx context.Context) (map[string]string, error)
+	Update(g Organization) (Organization, error)
+	UpdateWithContext(ctx context.Context, g Organization) (Organization, error)
+}
+
+// PoliciesAPI is the method set of PolicyService, the type of Client.Policies.
+// Assign another implementation such as a FakePolicies to Client.Policies to replace it.
+type PoliciesAPI interface {
+	Delete(policyName string) (PolicyGetResponse, error)
+	DeleteRevision(policyName string, revisionID string) (RevisionDetailsResponse, error)
+	DeleteRevisionWithContext(ctx context.Context, policyName string, revisionID string) (RevisionDetailsResponse, error)
+	DeleteWithContext(ctx context.Context, policyName string) (PolicyGetResponse, error)
+	Get(name string) (PolicyGetResponse, error)
+	GetRevisionDetails(policyName string, revisionID string) (RevisionDetailsResponse, error)
+	GetRevisionDetailsWithContext(ctx context.Context, policyName string, revisionID string) (RevisionDetailsResponse, error)
+	GetWithContext(ctx context.Context, name string) (PolicyGetResponse, error)
+	List() (PoliciesGetResponse, error)
+	ListWithContext(ctx context.Context) (PoliciesGetResponse, error)
+}
+
+// PolicyGroupsAPI is the method set of PolicyGroupService, the type of Client.PolicyGroups.
+// Assign another implementation such as a FakePolicyGroups to Client.PolicyGroups to replace it.
+type PolicyGroupsAPI interface {
+	Delete(policyGroupName string) (PolicyGroup, error)
+	DeletePolicy(policyGroupName string, policyName string) (RevisionDetailsResponse, error)
+	DeletePolicyWithContext(ctx context.Context, policyGroupName string, policyName string) (RevisionDetailsResponse, error)
+	DeleteWithContext(ctx context.Context, policyGroupName string) (PolicyGroup, error)
+	Get(policyGroupName string) (PolicyGroup, error)
+	GetPolicy(policyGroupName string, policyName string) (RevisionDetailsResponse, error)
+	GetPolicyWithContext(ctx context.Context, policyGroupName string, policyName string) (RevisionDetailsResponse, error)
+	GetWithContext(ctx context.Context, policyGroupName string) (PolicyGroup, error)
+	List() (PolicyGroupGetResponse, error)
+	ListWithContext(ctx context.Context) (PolicyGroupGetResponse, error)
+}
+
+// PrincipalsAPI is the method set of PrincipalService, the type of Client.Principals.
+// Assign another implementation such as a FakePrincipals to Client.Principals to replace it.
+type PrincipalsAPI interface {
+	Get(name string) (Principal, error)
+	GetWithContext(ctx context.Context, name string) (Principal, error)
+}
+
+// RequiredRecipeAPI is the method set of RequiredRecipeService, the type of Client.RequiredRecipe.
+// Assign another implementation such as a FakeRequiredRecipe to Client.RequiredRecipe to replace it.
+type RequiredRecipeAPI interface {
+	Get() (RequiredRecipe, error)
+	GetWithContext(ctx context.Context) (RequiredRecipe, error)
+}
+
+// RolesAPI is the method set of RoleService, the type of Client.Roles.
+// Assign another implementation such as a FakeRoles to Client.Roles to replace it.
+type RolesAPI interface {
+	Create(role *Role) (*RoleCreateResult, error)
+	CreateWithContext(ctx context.Context, role *Role) (*RoleCreateResult, error)
+	Delete(name string) error
+	DeleteWithContext(ctx context.Context, name string) error
+	Get(name string) (*Role, error)
+	GetEnvironmentRunlist(role string, environment string) (EnvRunList, error)
+	GetEnvironmentRunlistWithContext(ctx context.Context, role string, environment string) (EnvRunList, error)
+	GetEnvironments(role string) (RoleEnvironmentsResult, error)
+	GetEnvironmentsWithContext(ctx context.Context, role string) (RoleEnvironmentsResult, error)
+	GetWithContext(ctx context.Context, name string) (*Role, error)
+	List() (*RoleListResult, error)
+	ListWithContext(ctx context.Context) (*RoleListResult, error)
+	Put(role *Role) (*Role, error)
+	PutWithContext(ctx context.Context, role *Role) (*Role, error)
+}
+
+// SandboxesAPI is the method set of SandboxService, the type of Client.Sandboxes.
+// Assign another implementation such as a FakeSandboxes to Client.Sandboxes to replace it.
+type SandboxesAPI interface {
+	Post(sums []string) (SandboxPostResponse, error)
+	PostWithContext(ctx context.Context, sums []string) (SandboxPostResponse, error)
+	Put(id string) (Sandbox, error)
+	PutWithContext(ctx context.Context, id string) (Sandbox, error)
+}
+
+// SearchAPI is the method set of SearchService, the type of Client.Search.
+// Assign another implementation such as a FakeSearch to Client.Search to replace it.
+type SearchAPI interface {
+	Exec(idx string, statement string) (SearchResult, error)
+	ExecJSON(idx string, statement string) (JSearchResult, error)
+	ExecJSONWithContext(ctx context.Context, idx string, statement string) (JSearchResult, error)
+	ExecWithContext(ctx context.Context, idx string, statement string) (SearchResult, error)
+	Indexes() (map[string]string, error)
+	IndexesWithContext(ctx context.Context) (map[string]string, error)
+	Iterator(idx string, statement string, opts *SearchOptions) *SearchIterator
+	IteratorWithContext(ctx context.Context, idx string, statement string, opts *SearchOptions) *SearchIterator
+	NewQuery(idx string, statement string) (SearchQuery, error)
+	PageSize(setting int)
+	PartialExec(idx string, statement string, params map[string]interface{}) (SearchResult, error)
+	PartialExecJSON(idx string, statement string, params map[string]interface{}) (JSearchResult, error)
+	PartialExecJSONWithContext(ctx context.Context, idx string, statement string, params map[string]interface{}) (JSearchResult, error)
+	PartialExecWithContext(ctx context.Context, idx string, statement string, params map[string]interface{}) (SearchResult, error)
+}
+
+// StatsAPI is the method set of StatsService, the type of Client.Stats.
+// Assign another implementation such as a FakeStats to Client.Stats to replace it.
+type StatsAPI interface {
+	Get(user string, password string) (Stats, error)
+	GetWithContext(ctx context.Context, user string, password string) (Stats, error)
+}
+
+// StatusAPI is the method set of StatusService, the type of Client.Status.
+// Assign another implementation such as a FakeStatus to Client.Status to replace it.
+type StatusAPI interface {
+	Get() (Status, error)
+	GetWithContext(ctx context.Context) (Status, error)
+}
+
+// UniverseAPI is the method set of UniverseService, the type of Client.Universe.
+// Assign another implementation such as a FakeUniverse to Client.Universe to replace it.
+type UniverseAPI interface {
+	Get() (Universe, error)
+	GetWithContext(ctx context.Context) (Universe, error)
+}
+
+// UpdatedSinceAPI is the method set of UpdatedSinceService, the type of Client.UpdatedSince.
+// Assign another implementation such as a FakeUpdatedSince to Client.UpdatedSince to replace it.
+type UpdatedSinceAPI interface {
+	Get(sequenceId int64) ([]UpdatedSince, error)
+	GetWithContext(ctx context.Context, sequenceId int64) ([]UpdatedSince, error)
+}
+
+// UsersAPI is the method set of UserService, the type of Client.Users.
+// Assign another implementation such as a FakeUsers to Client.Users to replace it.
+type UsersAPI interface {
+	AddKey(name string, keyadd AccessKey) (KeyItem, error)
+	AddKeyWithContext(ctx context.Context, name string, keyadd AccessKey) (KeyItem, error)
+	Create(user User) (UserResult, error)
+	CreateWithContext(ctx context.Context, user User) (UserResult, error)
+	Delete(name string) error
+	DeleteKey(name string, keyname string) (AccessKey, error)
+	DeleteKeyWithContext(ctx context.Context, name string, keyname string) (AccessKey, error)
+	DeleteWithContext(ctx context.Context, name string) error
+	Get(name string) (User, error)
+	GetKey(name string, keyname string) (AccessKey, error)
+	GetKeyWithContext(ctx context.Context, name string, keyname string) (AccessKey, error)
+	GetWithContext(ctx context.Context, name string) (User, error)
+	List(filters ...string) (map[string]string, error)
+	ListKeys(name string) ([]KeyItem, error)
+	ListKeysWithContext(ctx context.Context, name string) ([]KeyItem, error)
+	ListWithContext(ctx context.Context, filters ...string) (map[string]string, error)
+	Update(name string, user User) (UserResult, error)
+	UpdateKey(username string, keyname string, keyUp AccessKey) (AccessKey, error)
+	UpdateKeyWithContext(ctx context.Context, username string, keyname string, keyUp AccessKey) (AccessKey, error)
+	UpdateWithContext(ctx context.Context, name string, user User) (UserResult, error)
+	VerboseList(filters ...string) (map[string]UserVerboseResult, error)
+	VerboseListWithContext(ctx context.Context, filters ...string) (map[string]UserVerboseResult, error)
+}
+
+var (
+	_ ACLsAPI              = (*ACLService)(nil)
+	_ AssociationsAPI      = (*AssociationService)(nil)
+	_ AuthenticateUserAPI  = (*AuthenticateUserService)(nil)
+	_ ClientsAPI           = (*ApiClientService)(nil)
+	_ ContainersAPI        = (*ContainerService)(nil)
+	_ CookbookArtifactsAPI = (*CBAService)(nil)
+	_ CookbooksAPI         = (*CookbookService)(nil)
+	_ DataBagsAPI          = (*DataBagService)(nil)
+	_ EnvironmentsAPI      = (*EnvironmentService)(nil)
+	_ GroupsAPI            = (*GroupService)(nil)
+	_ LicenseAPI           = (*LicenseService)(nil)
+	_ NodesAPI             = (*NodeService)(nil)
+	_ OrganizationsAPI     = (*OrganizationService)(nil)
+	_ PoliciesAPI          = (*PolicyService)(nil)
+	_ PolicyGroupsAPI      = (*PolicyGroupService)(nil)
+	_ PrincipalsAPI        = (*PrincipalService)(nil)
+	_ RequiredRecipeAPI    = (*RequiredRecipeService)(nil)
+	_ RolesAPI             = (*RoleService)(nil)
+	_ SandboxesAPI         = (*SandboxService)(nil)
+	_ SearchAPI            = (*SearchService)(nil)
+	_ StatsAPI             = (*StatsService)(nil)
+	_ StatusAPI            = (*StatusService)(nil)
+	_ UniverseAPI          = (*UniverseService)(nil)
+	_ UpdatedSinceAPI      = (*UpdatedSinceService)(nil)
+	_ UsersAPI             = (*UserService)(nil)
+)
+
+// copyServices sets the services replaced in from, the ones that aren't its own, on c
+func (c *Client) copyServices(from *Client) {
+	if s, ok := from.ACLs.(*ACLService); !ok || s.client != from {
+		c.ACLs = from.ACLs
+	}
+	if s, ok := from.Associations.(*AssociationService); !ok || s.client != from {
+		c.Associations = from.Associations
+	}
+	if s, ok := from.AuthenticateUser.(*AuthenticateUserService); !ok || s.client != from {
+		c.AuthenticateUser = from.AuthenticateUser
+	}
+	if s, ok := from.Clients.(*ApiClientService); !ok || s.client != from {
+		c.Clients = from.Clients
+	}
+	if s, ok := from.Containers.(*ContainerService); !ok || s.client != from {
+		c.Containers = from.Containers
+	}
+	if s, ok := from.CookbookArtifacts.(*CBAService); !ok || s.client != from {
+		c.CookbookArtifacts = from.CookbookArtifacts
+	}
+	if s, ok := from.Cookbooks.(*CookbookService); !ok || s.client != from {
+		c.Cookbooks = from.Cookbooks
+	}
+	if s, ok := from.DataBags.(*DataBagService); !ok || s.client != from {
+		c.DataBags = from.DataBags
+	}
+	if s, ok := from.Environments.(*EnvironmentService); !ok || s.client != from {
+		c.Environments = from.Environments
+	}
+	if s, ok := from.Groups.(*GroupService); !ok || s.client != from {
+		c.Groups = from.Groups
+	}
+	if s, ok := from.License.(*LicenseService); !ok || s.client != from {
+		c.License = from.License
+	}
+	if s, ok := from.Nodes.(*NodeService); !ok || s.client != from {
+		c.Nodes = from.Nodes
+	}
+	if s, ok := from.Organizations.(*OrganizationService); !ok || s.client != from {
+		c.Organizations = from.Organizations
+	}
+	if s, ok := from.Policies.(*PolicyService); !ok || s.client != from {
+		c.Policies = from.Policies
+	}
+	if s, ok := from.PolicyGroups.(*PolicyGroupService); !ok || s.client != from {
+		c.PolicyGroups = from.PolicyGroups
+	}
+	if s, ok := from.Principals.(*PrincipalService); !ok || s.client != from {
+		c.Principals = from.Principals
+	}
+	if s, ok := from.RequiredRecipe.(*RequiredRecipeService); !ok || s.client != from {
+		c.RequiredRecipe = from.RequiredRecipe
+	}
+	if s, ok := from.Roles.(*RoleService); !ok || s.client != from {
+		c.Roles = from.Roles
+	}
+	if s, ok := from.Sandboxes.(*SandboxService); !ok || s.client != from {
+		c.Sandboxes = from.Sandboxes
+	}
+	if s, ok := from.Search.(*SearchService); !ok || s.client != from {
+		c.Search = from.Search
+	}
+	if s, ok := from.Stats.(*StatsService); !ok || s.client != from {
+		c.Stats = from.Stats
+	}
+	if s, ok := from.Status.(*StatusService); !ok || s.client != from {
+		c.Status = from.Status
+	}
+	if s, ok := from.Universe.(*UniverseService); !ok || s.client != from {
+		c.Universe = from.Universe
+	}
+	if s, ok := from.UpdatedSince.(*UpdatedSinceService); !ok || s.client != from {
+		c.UpdatedSince = from.UpdatedSince
+	}
+	if s, ok := from.Users.(*UserService); !ok || s.client != from {
+		c.Users = from.Users
+	}
+}
@@ -0,0 +1,145 @@
+package chef
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+// Chef Infra Server API versions understood by this package
+const (
+	MinAPIVersion = 0
+	MaxAPIVersion = 2
+)
+
+// APIVersionRange is the range of Chef Server API versions a Client is willing to use.
+// The Client starts with Max and moves to the highest version the server also supports,
+// as advertised by the X-Ops-Server-API-Version response header or a 406 response.
+type APIVersionRange struct {
+	Min int
+	Max int
+}
+
+// validate checks the range is within the versions this package understands
+func (r *APIVersionRange) validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.Min < MinAPIVersion || r.Max > MaxAPIVersion {
+		return fmt.Errorf("API version range %d-%d outside of the supported %d-%d", r.Min, r.Max, MinAPIVersion, MaxAPIVersion)
+	}
+	if r.Min > r.Max {
+		return fmt.Errorf("API version range %d-%d has Min above Max", r.Min, r.Max)
+	}
+	return nil
+}
+
+// serverAPIVersions is the range in the X-Ops-Server-API-Version header the Chef server
+// returns with every response, and in the body of a 406 response
+type serverAPIVersions struct {
+	MinVersion *apiVersionNumber `json:"min_version"`
+	MaxVersion *apiVersionNumber `json:"max_version"`
+}
+
+// valid reports if both ends of the range are known
+func (s serverAPIVersions) valid() bool {
+	return s.MinVersion != nil && s.MaxVersion != nil
+}
+
+// apiVersionNumber decodes versions sent as either json numbers or strings
+type apiVersionNumber int
+
+func (n *apiVersionNumber) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := strconv.Atoi(s)
+		*n = apiVersionNumber(v)
+		return err
+	}
+	var v int
+	err := json.Unmarshal(data, &v)
+	*n = apiVersionNumber(v)
+	return err
+}
+
+// apiVersions is the negotiation state of a Client
+type apiVersions struct {
+	want    APIVersionRange
+	current atomic.Int32
+}
+
+func newAPIVersions(r *APIVersionRange) *apiVersions {
+	// earlier releases always sent version 1
+	want := APIVersionRange{Min: 1, Max: 1}
+	if r != nil {
+		want = *r
+	}
+	v := &apiVersions{want: want}
+	v.current.Store(int32(want.Max))
+	return v
+}
+
+// APIVersion returns the Chef Server API version sent with requests. It changes as the
+// client learns the versions supported by the server.
+func (c *Client) APIVersion() int {
+	if c.apiVersions == nil {
+		return 1
+	}
+	return int(c.apiVersions.current.Load())
+}
+
+// negotiate picks the highest version in both the wanted and the server range. It
+// reports whether the version changed. Ranges that don't overlap leave it unchanged.
+func (v *apiVersions) negotiate(server serverAPIVersions) bool {
+	version := v.want.Max
+	if int(*server.MaxVersion) < version {
+		version = int(*server.MaxVersion)
+	}
+	if version < v.want.Min || version < int(*server.MinVersion) {
+		return false
+	}
+	return int(v.current.Swap(int32(version))) != version
+}
+
+// serverAPIVersion parses the X-Ops-Server-API-Version response header
+func serverAPIVersion(res *http.Response) (serverAPIVersions, bool) {
+	var server serverAPIVersions
+	header := res.Header.Get("X-Ops-Server-API-Version")
+	if header == "" {
+		return server, false
+	}
+	return server, json.Unmarshal([]byte(header), &server) == nil && server.valid()
+}
+
+// learnAPIVersion updates the version in use from a response. It reports whether
+// the request should be sent again with the new version, after a 406 response.
+func (c *Client) learnAPIVersion(res *http.Response) bool {
+	if c.apiVersions == nil || res == nil {
+		return false
+	}
+	server, ok := serverAPIVersion(res)
+	if res.StatusCode == http.StatusNotAcceptable {
+		// the body of the negotiation error holds the supported range
+		data, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(data))
+		var body serverAPIVersions
+		if err == nil && json.Unmarshal(data, &body) == nil && body.valid() {
+			server, ok = body, true
+		}
+		if !ok || res.Request == nil {
+			return false
+		}
+		sent := res.Request.Header.Get("X-Ops-Server-API-Version")
+		c.apiVersions.negotiate(server)
+		return sent != "" && sent != strconv.Itoa(c.APIVersion())
+	}
+	if ok {
+		c.apiVersions.negotiate(server)
+	}
+	return false
+}
@@ -1,7 +1,10 @@
 package chef
 
 // import "fmt"
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 type AssociationService struct {
 	client *Client
@@ -76,30 +79,50 @@ type OrgUser struct {
 
 // ListInvites gets a list of the pending invitations for an organization.
 func (e *AssociationService) ListInvites() (invitelist []Invite, err error) {
-	err = e.client.magicRequestDecoder("GET", "association_requests", nil, &invitelist)
+	return e.ListInvitesWithContext(context.Background())
+}
+
+// ListInvitesWithContext is ListInvites with a context for cancellation and deadlines.
+func (e *AssociationService) ListInvitesWithContext(ctx context.Context) (invitelist []Invite, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "association_requests", nil, &invitelist)
 	return
 }
 
 // Invite creates an invitation for a user to join an organization on the chef server
 func (e *AssociationService) Invite(invite Request) (data Association, err error) {
+	return e.InviteWithContext(context.Background(), invite)
+}
+
+// InviteWithContext is Invite with a context for cancellation and deadlines.
+func (e *AssociationService) InviteWithContext(ctx context.Context, invite Request) (data Association, err error) {
 	body, err := JSONReader(invite)
 	if err != nil {
 		return
 	}
-	err = e.client.magicRequestDecoder("POST", "association_requests/", body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "POST", "association_requests/", body, &data)
 	return
 }
 
 // DeleteInvite removes a pending invitation to an organization
 func (e *AssociationService) DeleteInvite(id string) (rescind RescindInvite, err error) {
-	err = e.client.magicRequestDecoder("DELETE", "association_requests/"+id, nil, &rescind)
+	return e.DeleteInviteWithContext(context.Background(), id)
+}
+
+// DeleteInviteWithContext is DeleteInvite with a context for cancellation and deadlines.
+func (e *AssociationService) DeleteInviteWithContext(ctx context.Context, id string) (rescind RescindInvite, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", "association_requests/"+id, nil, &rescind)
 	return
 }
 
 // InviteID Finds an invitation id for a user
 func (e *AssociationService) InviteId(user string) (id string, err error) {
+	return e.InviteIdWithContext(context.Background(), user)
+}
+
+// InviteIdWithContext is InviteId with a context for cancellation and deadlines.
+func (e *AssociationService) InviteIdWithContext(ctx context.Context, user string) (id string, err error) {
 	var invitelist []Invite
-	err = e.client.magicRequestDecoder("GET", "association_requests", nil, &invitelist)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "association_requests", nil, &invitelist)
 	if err != nil {
 		return
 	}
@@ -118,38 +141,63 @@ func (e *AssociationService) InviteId(user string) (id string, err error) {
 // AcceptInvite Accepts an invitation
 // TODO: Gets a 405, code is in knife is it part of erchef?
 func (e *AssociationService) AcceptInvite(id string) (data string, err error) {
+	return e.AcceptInviteWithContext(context.Background(), id)
+}
+
+// AcceptInviteWithContext is AcceptInvite with a context for cancellation and deadlines.
+func (e *AssociationService) AcceptInviteWithContext(ctx context.Context, id string) (data string, err error) {
 	body, err := JSONReader("{ \"accept\" }")
 	if err != nil {
 		return
 	}
-	err = e.client.magicRequestDecoder("PUT", "association_requests/"+id, body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "PUT", "association_requests/"+id, body, &data)
 	return
 }
 
 // List gets a list of the users in an organization
 func (e *AssociationService) List() (data []OrgUserListEntry, err error) {
-	err = e.client.magicRequestDecoder("GET", "users", nil, &data)
+	return e.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (e *AssociationService) ListWithContext(ctx context.Context) (data []OrgUserListEntry, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "users", nil, &data)
 	return
 }
 
 // Add a user immediately
 func (e *AssociationService) Add(addme AddNow) (err error) {
+	return e.AddWithContext(context.Background(), addme)
+}
+
+// AddWithContext is Add with a context for cancellation and deadlines.
+func (e *AssociationService) AddWithContext(ctx context.Context, addme AddNow) (err error) {
 	body, err := JSONReader(addme)
 	if err != nil {
 		return
 	}
-	err = e.client.magicRequestDecoder("POST", "users", body, nil)
+	err = e.client.magicRequestDecoderWithContext(ctx, "POST", "users", body, nil)
 	return
 }
 
 // Get the details of a user in an organization
 func (e *AssociationService) Get(name string) (data OrgUser, err error) {
-	err = e.client.magicRequestDecoder("GET", "users/"+name, nil, &data)
+	return e.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *AssociationService) GetWithContext(ctx context.Context, name string) (data OrgUser, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "users/"+name, nil, &data)
 	return
 }
 
 // Delete removes a user from an organization
 func (e *AssociationService) Delete(name string) (data OrgUser, err error) {
-	err = e.client.magicRequestDecoder("DELETE", "users/"+name, nil, &data)
+	return e.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (e *AssociationService) DeleteWithContext(ctx context.Context, name string) (data OrgUser, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", "users/"+name, nil, &data)
 	return
 }
@@ -1,5 +1,7 @@
 package chef
 
+import "context"
+
 type AuthenticateUserService struct {
 	client *Client
 }
@@ -14,7 +16,12 @@ type Authenticate struct {
 //
 // https://docs.chef.io/api_chef_server.html#authenticate-user
 func (e *AuthenticateUserService) Authenticate(authenticate_request Authenticate) (err error) {
+	return e.AuthenticateWithContext(context.Background(), authenticate_request)
+}
+
+// AuthenticateWithContext is Authenticate with a context for cancellation and deadlines.
+func (e *AuthenticateUserService) AuthenticateWithContext(ctx context.Context, authenticate_request Authenticate) (err error) {
 	body, err := JSONReader(authenticate_request)
-	err = e.client.magicRequestDecoder("POST", "authenticate_user", body, nil)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "POST", "authenticate_user", body, nil)
 	return
 }
@@ -90,7 +90,8 @@ func privateEncrypt(key *rsa.PrivateKey, data []byte) (enc []byte, err error) {
 		m.Mul(m, ir)
 		m.Mod(m, key.N)
 	}
-	enc = m.Bytes()
+	// like RSA_private_encrypt the result is always k bytes, keeping any leading zeros
+	enc = m.FillBytes(make([]byte, k))
 	return
 }
 
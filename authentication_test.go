@@ -1,6 +1,7 @@
 package chef
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -65,6 +66,20 @@ func TestPrivateEncrypt(t *testing.T) {
 	}
 }
 
+func TestPrivateEncryptLength(t *testing.T) {
+	pk, _ := PrivateKeyFromString([]byte(privateKeyG))
+	// about one content in 256 encrypts to a value with a leading zero byte
+	for i := 0; i < 1000; i++ {
+		enc, err := privateEncrypt(pk, []byte(fmt.Sprintf("%s %d", teststr, i)))
+		if err != nil {
+			t.Fatal("Error encrypting", err)
+		}
+		if len(enc) != pk.Size() {
+			t.Fatalf("Encrypted length is %d, expected %d", len(enc), pk.Size())
+		}
+	}
+}
+
 func TestBasicHashStr(t *testing.T) {
 	hashOut := HashStr(teststr)
 	if hashOut != testsha1 {
@@ -0,0 +1,237 @@
+package chef
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrBodyNotSeekable is returned when a request body must be read to compute its
+// content hash but can't be rewound to be sent afterwards. Use a RequestBody with
+// the hash precomputed to send such a body.
+var ErrBodyNotSeekable = errors.New("chef: request body is not seekable, supply its hash in a RequestBody")
+
+// Body wraps io.Reader and adds methods for calculating hashes and detecting content
+type Body struct {
+	io.Reader
+}
+
+// BodyDigest describes the content of a request body
+type BodyDigest struct {
+	// Hash is the base64 encoded SHA1 of the content, used by protocols 1.0 and 1.1
+	Hash string
+	// Hash256 is the base64 encoded SHA256 of the content, used by protocol 1.3
+	Hash256 string
+	// ContentType is "application/json" for a json object, otherwise as detected by http.DetectContentType
+	ContentType string
+	// Length of the content in bytes
+	Length int64
+}
+
+// RequestBody is a request body with its content type and hashes supplied up front,
+// so it is streamed to the server without being read in advance. This is the way to
+// send readers that can't seek, such as a pipe or a download, with NewRequest.
+//
+// Only the hash used by the client's authentication protocol is required. When it
+// is missing the Reader must be an io.Seeker so the hash can be computed.
+// ContentType defaults to "application/octet-stream" when the body isn't read.
+type RequestBody struct {
+	io.Reader
+
+	// ContentType of the body
+	ContentType string
+
+	// ContentLength of the body. Zero or less sends the body with chunked encoding.
+	ContentLength int64
+
+	// Hash is the base64 encoded SHA1 of the content, for protocols 1.0 and 1.1
+	Hash string
+
+	// Hash256 is the base64 encoded SHA256 of the content, for protocol 1.3
+	Hash256 string
+}
+
+// Digest reads the body once to compute its hashes and content type, then rewinds it.
+// ErrBodyNotSeekable is returned when the reader can't be rewound.
+func (body *Body) Digest() (BodyDigest, error) {
+	if body.Reader == nil {
+		return digest(bytes.NewReader(nil))
+	}
+	seeker, ok := body.Reader.(io.Seeker)
+	if !ok {
+		return BodyDigest{}, ErrBodyNotSeekable
+	}
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return BodyDigest{}, err
+	}
+	d, err := digest(body.Reader)
+	if err != nil {
+		return d, err
+	}
+	_, err = seeker.Seek(start, io.SeekStart)
+	return d, err
+}
+
+// Buffer creates a bytes.Buffer copy of the body. The body is rewound when it is an
+// io.Seeker, otherwise it is replaced with an in memory copy so it can still be read.
+func (body *Body) Buffer() *bytes.Buffer {
+	var b bytes.Buffer
+	if body.Reader == nil {
+		return &b
+	}
+
+	_, _ = b.ReadFrom(body.Reader)
+	if seeker, ok := body.Reader.(io.Seeker); ok {
+		if _, err := seeker.Seek(0, io.SeekStart); err == nil {
+			return &b
+		}
+	}
+	body.Reader = bytes.NewReader(b.Bytes())
+	return &b
+}
+
+// Hash calculates the body content hash
+func (body *Body) Hash() string {
+	return body.digest().Hash
+}
+
+// Hash256 calculates the body content hash
+func (body *Body) Hash256() string {
+	return body.digest().Hash256
+}
+
+// ContentType returns the content-type string of Body as detected by http.DetectContentType()
+func (body *Body) ContentType() string {
+	return body.digest().ContentType
+}
+
+// digest returns the Digest of the body, buffering it in memory when it can't seek
+func (body *Body) digest() BodyDigest {
+	d, err := body.Digest()
+	if err != nil {
+		d, _ = digest(bytes.NewReader(body.Buffer().Bytes()))
+	}
+	return d
+}
+
+// digest hashes r and detects its content type in a single pass
+func digest(r io.Reader) (BodyDigest, error) {
+	sha1Hash, sha256Hash := sha1.New(), sha256.New()
+	sniff := &sniffWriter{}
+	counter := &countWriter{}
+	tee := io.TeeReader(r, io.MultiWriter(sha1Hash, sha256Hash, sniff, counter))
+
+	isJSON := jsonObject(tee)
+	// hash what the json decoder didn't consume
+	if _, err := io.Copy(io.Discard, tee); err != nil {
+		return BodyDigest{}, err
+	}
+
+	d := BodyDigest{
+		Hash:    base64.StdEncoding.EncodeToString(sha1Hash.Sum(nil)),
+		Hash256: base64.StdEncoding.EncodeToString(sha256Hash.Sum(nil)),
+		Length:  counter.n,
+	}
+	if isJSON {
+		d.ContentType = "application/json"
+	} else {
+		d.ContentType = http.DetectContentType(sniff.buf)
+	}
+	return d, nil
+}
+
+// jsonObject reports if r holds a single json object, or null, without keeping it in memory
+func jsonObject(r io.Reader) bool {
+	dec := json.NewDecoder(r)
+	first, err := dec.Token()
+	if err != nil {
+		return false
+	}
+	if first != nil && first != json.Delim('{') {
+		return false
+	}
+	depth := 0
+	if first == json.Delim('{') {
+		depth = 1
+	}
+	for depth > 0 {
+		tok, err := dec.Token()
+		if err != nil {
+			return false
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	_, err = dec.Token()
+	return err == io.EOF
+}
+
+// sniffWriter keeps the bytes http.DetectContentType looks at
+type sniffWriter struct {
+	buf []byte
+}
+
+func (s *sniffWriter) Write(p []byte) (int, error) {
+	if room := 512 - len(s.buf); room > 0 {
+		if len(p) < room {
+			room = len(p)
+		}
+		s.buf = append(s.buf, p[:room]...)
+	}
+	return len(p), nil
+}
+
+// countWriter counts the bytes written to it
+type countWriter struct {
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// prepareBody returns the reader to send and its digest. Only the hash needed by
+// version is guaranteed to be set. An empty version is for unsigned requests, which
+// need no hash.
+func prepareBody(body io.Reader, version AuthVersion) (io.Reader, BodyDigest, error) {
+	rb, ok := body.(*RequestBody)
+	if !ok {
+		d, err := (&Body{body}).Digest()
+		return body, d, err
+	}
+
+	d := BodyDigest{Hash: rb.Hash, Hash256: rb.Hash256, ContentType: rb.ContentType, Length: rb.ContentLength}
+	missing := false
+	switch version {
+	case "":
+	case AuthVersion13:
+		missing = d.Hash256 == ""
+	default:
+		missing = d.Hash == ""
+	}
+	if missing {
+		computed, err := (&Body{rb.Reader}).Digest()
+		if err != nil {
+			return nil, d, err
+		}
+		if d.ContentType == "" {
+			d.ContentType = computed.ContentType
+		}
+		d.Hash, d.Hash256, d.Length = computed.Hash, computed.Hash256, computed.Length
+	}
+	if d.ContentType == "" {
+		d.ContentType = "application/octet-stream"
+	}
+	return rb.Reader, d, nil
+}
@@ -0,0 +1,119 @@
+package chef
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// onlyReader hides every method but Read
+type onlyReader struct {
+	io.Reader
+}
+
+func TestBodyDigest(t *testing.T) {
+	cases := []struct {
+		content     string
+		contentType string
+	}{
+		{`{"name":"node1","run_list":["recipe[a]"]}`, "application/json"},
+		{`null`, "application/json"},
+		{`["a","b"]`, "text/plain; charset=utf-8"},
+		{`{"name":"node1"} trailing`, "text/plain; charset=utf-8"},
+		{`somecoolbodytext`, "text/plain; charset=utf-8"},
+		{``, "text/plain; charset=utf-8"},
+		{"\x89PNG\r\n\x1a\n", "image/png"},
+	}
+	for _, tc := range cases {
+		d, err := (&Body{strings.NewReader(tc.content)}).Digest()
+		assert.Nil(t, err)
+		assert.Equal(t, HashStr(tc.content), d.Hash, tc.content)
+		assert.Equal(t, HashStr256(tc.content), d.Hash256, tc.content)
+		assert.Equal(t, tc.contentType, d.ContentType, tc.content)
+		assert.Equal(t, int64(len(tc.content)), d.Length, tc.content)
+	}
+}
+
+func TestBodyDigest_Rewinds(t *testing.T) {
+	r := strings.NewReader("skip:content")
+	_, _ = r.Seek(5, io.SeekStart)
+	body := &Body{r}
+	d, err := body.Digest()
+	assert.Nil(t, err)
+	assert.Equal(t, HashStr("content"), d.Hash, "hashes from the current offset")
+	rest, _ := io.ReadAll(r)
+	assert.Equal(t, "content", string(rest), "reader is back where it was")
+}
+
+func TestBodyDigest_NotSeekable(t *testing.T) {
+	body := &Body{onlyReader{strings.NewReader("text")}}
+	_, err := body.Digest()
+	assert.ErrorIs(t, err, ErrBodyNotSeekable)
+
+	// the older helpers buffer the body instead of exiting
+	assert.Equal(t, HashStr("text"), body.Hash())
+	assert.Equal(t, HashStr256("text"), body.Hash256())
+	rest, _ := io.ReadAll(body)
+	assert.Equal(t, "text", string(rest), "body can still be read")
+}
+
+func TestNewRequest_NotSeekable(t *testing.T) {
+	setup()
+	defer teardown()
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	_, err := client.NewRequest("PUT", "nodes/node1", pr)
+	assert.ErrorIs(t, err, ErrBodyNotSeekable)
+}
+
+func TestNewRequest_RequestBody(t *testing.T) {
+	setup()
+	defer teardown()
+
+	content := strings.Repeat("cookbook file content\n", 1000)
+	var received []byte
+	var contentType string
+	var contentLength int64
+	mux.HandleFunc("/bookshelf/checksum", func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		contentType = r.Header.Get("Content-Type")
+		contentLength = r.ContentLength
+		assert.Equal(t, HashStr(content), r.Header.Get("X-Ops-Content-Hash"))
+	})
+
+	// a pipe can only be read once, so the hash is supplied
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = io.Copy(pw, strings.NewReader(content))
+		_ = pw.Close()
+	}()
+	req, err := client.NewRequest("PUT", "bookshelf/checksum", &RequestBody{
+		Reader:        pr,
+		ContentType:   "application/x-binary",
+		ContentLength: int64(len(content)),
+		Hash:          HashStr(content),
+	})
+	if assert.Nil(t, err) {
+		_, err = client.Do(req, nil)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, content, string(received))
+	assert.Equal(t, "application/x-binary", contentType)
+	assert.Equal(t, int64(len(content)), contentLength)
+
+	// the missing hash is computed from a seekable reader
+	req, err = client.NewRequest("PUT", "bookshelf/checksum", &RequestBody{Reader: bytes.NewReader([]byte(content))})
+	if assert.Nil(t, err) {
+		assert.Equal(t, HashStr(content), req.Header.Get("X-Ops-Content-Hash"))
+		assert.Equal(t, "text/plain; charset=utf-8", req.Header.Get("Content-Type"))
+	}
+
+	// and can't be computed from a pipe
+	_, err = client.NewRequest("PUT", "bookshelf/checksum", &RequestBody{Reader: onlyReader{strings.NewReader(content)}})
+	assert.ErrorIs(t, err, ErrBodyNotSeekable)
+}
@@ -0,0 +1,93 @@
+// Package chefprom records Chef API client metrics with the Prometheus client library.
+//
+//	obs := chefprom.New(prometheus.DefaultRegisterer)
+//	client, err := chef.NewClient(&chef.Config{
+//		...
+//		Observers: []chef.Observer{obs},
+//	})
+package chefprom
+
+import (
+	"context"
+	"strconv"
+
+	chef "github.com/go-chef/chef"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Observer implements chef.Observer by recording Prometheus histograms and counters
+type Observer struct {
+	duration     *prometheus.HistogramVec
+	requestSize  *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+	requests     *prometheus.CounterVec
+	retries      *prometheus.CounterVec
+}
+
+// sizeBuckets range from 256 bytes to 64MB, sized for json documents and cookbook files
+var sizeBuckets = prometheus.ExponentialBuckets(256, 4, 10)
+
+// New creates an Observer and registers its metrics with reg
+func New(reg prometheus.Registerer) *Observer {
+	labels := []string{"operation", "path", "method", "code"}
+	o := &Observer{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "chef",
+			Subsystem: "client",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of Chef API request attempts.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		requestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "chef",
+			Subsystem: "client",
+			Name:      "request_size_bytes",
+			Help:      "Size of Chef API request bodies.",
+			Buckets:   sizeBuckets,
+		}, labels),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "chef",
+			Subsystem: "client",
+			Name:      "response_size_bytes",
+			Help:      "Size of Chef API response bodies.",
+			Buckets:   sizeBuckets,
+		}, labels),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "chef",
+			Subsystem: "client",
+			Name:      "requests_total",
+			Help:      "Chef API request attempts by status code. The code is \"error\" when no response was received.",
+		}, labels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "chef",
+			Subsystem: "client",
+			Name:      "retries_total",
+			Help:      "Chef API request attempts after the first.",
+		}, []string{"operation", "path", "method"}),
+	}
+	reg.MustRegister(o.duration, o.requestSize, o.responseSize, o.requests, o.retries)
+	return o
+}
+
+// Start implements chef.Observer
+func (o *Observer) Start(ctx context.Context, info chef.RequestInfo) context.Context {
+	if info.Attempt > 1 {
+		o.retries.WithLabelValues(info.Operation, info.Path, info.Method).Inc()
+	}
+	return ctx
+}
+
+// Done implements chef.Observer
+func (o *Observer) Done(ctx context.Context, info chef.RequestInfo) {
+	code := "error"
+	if info.StatusCode != 0 {
+		code = strconv.Itoa(info.StatusCode)
+	}
+	labels := prometheus.Labels{"operation": info.Operation, "path": info.Path, "method": info.Method, "code": code}
+	o.duration.With(labels).Observe(info.Duration.Seconds())
+	o.requests.With(labels).Inc()
+	if info.RequestBytes >= 0 {
+		o.requestSize.With(labels).Observe(float64(info.RequestBytes))
+	}
+	o.responseSize.With(labels).Observe(float64(info.ResponseBytes))
+}
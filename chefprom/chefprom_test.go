@@ -0,0 +1,55 @@
+package chefprom
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	chef "github.com/go-chef/chef"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserver(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	obs := New(reg)
+
+	info := chef.RequestInfo{Operation: "nodes.get", Path: "nodes/{name}", Method: "GET", Attempt: 1}
+	ctx := obs.Start(context.Background(), info)
+	info.Duration = 20 * time.Millisecond
+	info.StatusCode = 503
+	info.ResponseBytes = 10
+	obs.Done(ctx, info)
+
+	info.Attempt = 2
+	obs.Start(ctx, info)
+	info.StatusCode = 0
+	info.Err = errors.New("connection reset")
+	obs.Done(ctx, info)
+
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+	found := map[string]float64{}
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			assert.Equal(t, "nodes.get", labels["operation"])
+			assert.Equal(t, "nodes/{name}", labels["path"])
+			switch {
+			case m.GetHistogram() != nil:
+				found[mf.GetName()+"/"+labels["code"]] = float64(m.GetHistogram().GetSampleCount())
+			case m.GetCounter() != nil:
+				found[mf.GetName()+"/"+labels["code"]] = m.GetCounter().GetValue()
+			}
+		}
+	}
+	assert.Equal(t, float64(1), found["chef_client_request_duration_seconds/503"])
+	assert.Equal(t, float64(1), found["chef_client_request_duration_seconds/error"])
+	assert.Equal(t, float64(1), found["chef_client_requests_total/503"])
+	assert.Equal(t, float64(1), found["chef_client_response_size_bytes/503"])
+	assert.Equal(t, float64(1), found["chef_client_retries_total/"])
+}
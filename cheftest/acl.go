@@ -0,0 +1,102 @@
+package cheftest
+
+import (
+	"net/http"
+
+	chef "github.com/go-chef/chef"
+)
+
+// aclPerms are the permissions of an ACL
+var aclPerms = []string{"create", "read", "update", "delete", "grant"}
+
+// containers are the containers of an organization
+var containers = []string{"clients", "containers", "cookbooks", "data", "environments", "groups", "nodes", "policies", "policy_groups", "roles", "sandboxes"}
+
+// exists reports whether the organization has the named object of a kind
+func (o *org) exists(kind, name string) bool {
+	var ok bool
+	switch kind {
+	case "nodes", "roles", "environments":
+		_, ok = o.objects[kind][name]
+	case "clients":
+		_, ok = o.clients[name]
+	case "groups":
+		_, ok = o.groups[name]
+	case "data":
+		_, ok = o.dataBags[name]
+	case "cookbooks":
+		_, ok = o.cookbooks[name]
+	case "policies":
+		_, ok = o.policies[name]
+	case "policy_groups":
+		_, ok = o.policyGroups[name]
+	case "containers":
+		for _, c := range containers {
+			ok = ok || c == name
+		}
+	}
+	return ok
+}
+
+// defaultACL grants every permission to AdminUser and the admins group, and read to the users group too
+func defaultACL() chef.ACL {
+	acl := chef.ACL{}
+	for _, perm := range aclPerms {
+		groups := chef.ACLitem{"admins"}
+		if perm == "read" {
+			groups = append(groups, "users")
+		}
+		acl[perm] = chef.ACLitems{Actors: chef.ACLitem{AdminUser}, Clients: chef.ACLitem{}, Groups: groups, Users: chef.ACLitem{AdminUser}}
+	}
+	return acl
+}
+
+// acl serves /{kind}/{name}/_acl and the updates of a single permission at /{kind}/{name}/_acl/{perm}
+func (s *Server) acl(r *request) (int, interface{}) {
+	kind, name := r.path[0], r.path[1]
+	if !r.org.exists(kind, name) {
+		return errorf(http.StatusNotFound, "Cannot load %s %s", kind, name)
+	}
+	key := kind + "/" + name
+	acl, ok := r.org.acls[key]
+	if !ok {
+		acl = defaultACL()
+		r.org.acls[key] = acl
+	}
+
+	switch {
+	case len(r.path) == 3 && r.Method == http.MethodGet:
+		return http.StatusOK, acl
+	case len(r.path) == 4 && r.Method == http.MethodPut:
+		perm := r.path[3]
+		if _, ok := acl[perm]; !ok {
+			return errorf(http.StatusNotFound, "no such permission %s", perm)
+		}
+		var update chef.ACL
+		if err := r.decode(&update); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		items, ok := update[perm]
+		if !ok {
+			return errorf(http.StatusBadRequest, "Field '%s' missing", perm)
+		}
+		// the server keeps actors as the users and clients together
+		if len(items.Users) > 0 || len(items.Clients) > 0 {
+			items.Actors = append(append(chef.ACLitem{}, items.Users...), items.Clients...)
+		}
+		items.Actors = nonNilItem(items.Actors)
+		items.Clients = nonNilItem(items.Clients)
+		items.Groups = nonNilItem(items.Groups)
+		items.Users = nonNilItem(items.Users)
+		acl[perm] = items
+		return http.StatusOK, object{}
+	}
+	return methodNotAllowed(r)
+}
+
+func nonNilItem(item chef.ACLitem) chef.ACLitem {
+	if item == nil {
+		return chef.ACLitem{}
+	}
+	return item
+}
@@ -0,0 +1,386 @@
+package cheftest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	chef "github.com/go-chef/chef"
+)
+
+// Mode selects whether a Cassette records or replays
+type Mode int
+
+const (
+	// Replay answers requests from the cassette file without contacting a server
+	Replay Mode = iota
+	// Record sends requests to the server and writes them with their responses to the cassette file
+	Record
+)
+
+// Cassette is a transport recording Chef API requests and responses to a file, and
+// replaying them later so tests run without a server. Plug it into a client with
+// Config.RoundTripper:
+//
+//	cassette, err := cheftest.NewCassette("testdata/nodes.json", cheftest.Replay)
+//	client, err := chef.NewClient(&chef.Config{
+//		...
+//		RoundTripper: cassette.RoundTripper,
+//	})
+//
+// Recordings are sanitized: the signature headers are dropped and the private and
+// public keys found in json bodies are replaced by a stand-in key. Other secrets, the
+// json keys chef.SensitiveKey reports such as password, are redacted. Replayed requests
+// are matched on the method, path, query and body, with json bodies compared after
+// normalizing them and redacting the same secrets, so a password doesn't have to
+// match the recorded one. Identical requests replay their recorded responses in order.
+type Cassette struct {
+	path string
+	mode Mode
+
+	mu   sync.Mutex
+	tape tape
+	used []bool
+}
+
+// tape is the content of a cassette file
+type tape struct {
+	// Key stands in for the keys removed from the recorded bodies
+	Key          string        `json:"key"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a sanitized request. Path and Query exclude the server address.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	// Base64 is set when Body holds base64 encoded binary content
+	Base64 bool `json:"base64,omitempty"`
+}
+
+// RecordedResponse is a sanitized response
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	Base64 bool        `json:"base64,omitempty"`
+}
+
+// ErrNoInteraction is returned in Replay mode for requests the cassette has no response for
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+// NewCassette opens the cassette file at path. In Replay mode the file is read, in
+// Record mode it is replaced and rewritten after every interaction.
+func NewCassette(path string, mode Mode) (*Cassette, error) {
+	c := &Cassette{path: path, mode: mode}
+	switch mode {
+	case Replay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &c.tape); err != nil {
+			return nil, fmt.Errorf("cassette %s: %w", path, err)
+		}
+		c.used = make([]bool, len(c.tape.Interactions))
+	case Record:
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		c.tape.Key = encodeKey(key)
+		if err := c.save(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %d", mode)
+	}
+	return c, nil
+}
+
+// Mode returns whether the cassette records or replays
+func (c *Cassette) Mode() Mode {
+	return c.mode
+}
+
+// Interactions returns the interactions recorded or loaded so far
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.tape.Interactions...)
+}
+
+// RoundTripper wraps next with the cassette, it has the signature of Config.RoundTripper.
+// next is only used in Record mode.
+func (c *Cassette) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return cassetteTransport{cassette: c, next: next}
+}
+
+type cassetteTransport struct {
+	cassette *Cassette
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.cassette.mode == Replay {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return t.cassette.replay(req, body)
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+	if err := t.cassette.record(req, body, res, resBody); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// readRequestBody returns the request body and leaves the request readable
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func (c *Cassette) record(req *http.Request, body []byte, res *http.Response, resBody []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	recReq := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  normalizeQuery(req.URL.RawQuery),
+		Header: sanitizeHeader(req.Header),
+	}
+	recReq.Body, recReq.Base64 = c.sanitizeBody(body)
+	recRes := RecordedResponse{Status: res.StatusCode, Header: sanitizeHeader(res.Header)}
+	recRes.Body, recRes.Base64 = c.sanitizeBody(resBody)
+
+	c.tape.Interactions = append(c.tape.Interactions, Interaction{Request: recReq, Response: recRes})
+	return c.save()
+}
+
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	query := normalizeQuery(req.URL.RawQuery)
+	sanitized, isBase64 := c.sanitizeBody(body)
+	want := normalizeBody(sanitized, isBase64)
+	for i, in := range c.tape.Interactions {
+		rec := in.Request
+		if c.used[i] || rec.Method != req.Method || rec.Path != req.URL.Path || rec.Query != query ||
+			normalizeBody(rec.Body, rec.Base64) != want {
+			continue
+		}
+		c.used[i] = true
+
+		resBody := []byte(in.Response.Body)
+		if in.Response.Base64 {
+			var err error
+			if resBody, err = base64.StdEncoding.DecodeString(in.Response.Body); err != nil {
+				return nil, fmt.Errorf("cassette %s: %w", c.path, err)
+			}
+		}
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(resBody)),
+			ContentLength: int64(len(resBody)),
+			Request:       req,
+		}, nil
+	}
+	target := req.URL.Path
+	if query != "" {
+		target += "?" + query
+	}
+	return nil, fmt.Errorf("%w for %s %s in %s", ErrNoInteraction, req.Method, target, c.path)
+}
+
+// save writes the cassette file. The caller holds c.mu.
+func (c *Cassette) save() error {
+	data, err := json.MarshalIndent(c.tape, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0600)
+}
+
+// sanitizedHeaders are the headers left out of recordings, they hold signatures,
+// credentials or values that change on every request
+var sanitizedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Date", "Content-Length", "X-Ops-Timestamp", "X-Ops-Content-Hash"}
+
+func sanitizeHeader(h http.Header) http.Header {
+	out := http.Header{}
+	for name, values := range h {
+		canonical := http.CanonicalHeaderKey(name)
+		skip := strings.HasPrefix(canonical, "X-Ops-Authorization-")
+		for _, s := range sanitizedHeaders {
+			skip = skip || canonical == s
+		}
+		if !skip {
+			out[canonical] = append([]string(nil), values...)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// redactedValue replaces the secrets of recorded bodies
+const redactedValue = "[REDACTED]"
+
+// sanitizeBody returns the body to record. The private_key and public_key fields of
+// json bodies are replaced by the cassette key and other secrets are redacted, binary
+// bodies are base64 encoded.
+func (c *Cassette) sanitizeBody(body []byte) (string, bool) {
+	if len(body) == 0 {
+		return "", false
+	}
+	var doc interface{}
+	if json.Unmarshal(body, &doc) == nil {
+		if c.replaceKeys(doc) {
+			if data, err := json.Marshal(doc); err == nil {
+				return string(data), false
+			}
+		}
+		return string(body), false
+	}
+	if !utf8.Valid(body) {
+		return base64.StdEncoding.EncodeToString(body), true
+	}
+	return string(body), false
+}
+
+// replaceKeys replaces the keys and redacts the secrets found in a json document,
+// reporting whether there were any
+func (c *Cassette) replaceKeys(doc interface{}) bool {
+	replaced := false
+	switch t := doc.(type) {
+	case map[string]interface{}:
+		for k, v := range t {
+			if s, ok := v.(string); ok && s != "" {
+				switch k {
+				case "private_key":
+					t[k] = c.tape.Key
+					replaced = true
+					continue
+				case "public_key":
+					t[k] = c.publicKey()
+					replaced = true
+					continue
+				}
+			}
+			if chef.SensitiveKey(k) {
+				if s, ok := v.(string); !ok || s != "" {
+					t[k] = redactedValue
+					replaced = true
+				}
+				continue
+			}
+			replaced = c.replaceKeys(v) || replaced
+		}
+	case []interface{}:
+		for _, v := range t {
+			replaced = c.replaceKeys(v) || replaced
+		}
+	}
+	return replaced
+}
+
+// publicKey returns the public half of the cassette key
+func (c *Cassette) publicKey() string {
+	key, err := chef.PrivateKeyFromString([]byte(c.tape.Key))
+	if err != nil {
+		return ""
+	}
+	return encodePublicKey(&key.PublicKey)
+}
+
+// normalizeQuery sorts the query parameters
+func normalizeQuery(raw string) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return raw
+	}
+	return values.Encode()
+}
+
+// normalizeBody returns a json body re-encoded with sorted keys and no white space,
+// other bodies as they are
+func normalizeBody(body string, isBase64 bool) string {
+	if isBase64 {
+		return body
+	}
+	var doc interface{}
+	if json.Unmarshal([]byte(body), &doc) != nil {
+		return body
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
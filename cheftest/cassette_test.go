@@ -0,0 +1,113 @@
+package cheftest
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chef "github.com/go-chef/chef"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cassetteWorkflow runs the requests recorded and replayed by TestCassette
+func cassetteWorkflow(t *testing.T, client *chef.Client) (chef.Node, chef.SearchResult, *chef.ApiClientCreateResult) {
+	node := chef.NewNode("web1")
+	node.NormalAttributes = map[string]interface{}{"tier": "frontend", "ports": []int{80, 443}}
+	_, err := client.Nodes.Post(node)
+	require.Nil(t, err)
+	got, err := client.Nodes.Get("web1")
+	require.Nil(t, err)
+	res, err := client.Search.Exec("node", "tier:frontend")
+	require.Nil(t, err)
+	created, err := client.Clients.Create(chef.ApiNewClient{Name: "node1", CreateKey: true})
+	require.Nil(t, err)
+	return got, res, created
+}
+
+func TestCassette(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	recorder, err := NewCassette(path, Record)
+	require.Nil(t, err)
+	live, err := srv.Client(ts.URL, "test")
+	require.Nil(t, err)
+	adminKey := encodeKey(srv.adminKey)
+	recording, err := chef.NewClient(&chef.Config{Name: AdminUser, Key: adminKey, BaseURL: live.BaseURL.String(), RoundTripper: recorder.RoundTripper})
+	require.Nil(t, err)
+	recNode, recSearch, recClient := cassetteWorkflow(t, recording)
+	assert.Len(t, recorder.Interactions(), 4)
+
+	data, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.NotContains(t, string(data), "X-Ops-Authorization")
+	assert.NotContains(t, string(data), strings.Split(recClient.ChefKey.PrivateKey, "\n")[1], "keys are replaced")
+	ts.Close()
+
+	// the replaying client has another key and another host, the server is gone
+	_, otherKey, err := newKey()
+	require.Nil(t, err)
+	player, err := NewCassette(path, Replay)
+	require.Nil(t, err)
+	replaying, err := chef.NewClient(&chef.Config{Name: AdminUser, Key: otherKey, BaseURL: "https://chef.example.com/organizations/test/", RoundTripper: player.RoundTripper})
+	require.Nil(t, err)
+	node, search, created := cassetteWorkflow(t, replaying)
+	assert.Equal(t, recNode, node)
+	assert.Equal(t, recSearch.Total, search.Total)
+	_, err = chef.PrivateKeyFromString([]byte(created.ChefKey.PrivateKey))
+	assert.Nil(t, err, "the replacement key is usable")
+
+	// every interaction is used once
+	_, err = replaying.Nodes.Get("web1")
+	assert.True(t, errors.Is(err, ErrNoInteraction), "got %v", err)
+
+	// a request with another body doesn't match
+	player, _ = NewCassette(path, Replay)
+	replaying, _ = chef.NewClient(&chef.Config{Name: AdminUser, Key: otherKey, BaseURL: "https://chef.example.com/organizations/test/", RoundTripper: player.RoundTripper})
+	_, err = replaying.Nodes.Post(chef.NewNode("web2"))
+	assert.True(t, errors.Is(err, ErrNoInteraction), "got %v", err)
+}
+
+func TestNormalizeBody(t *testing.T) {
+	assert.Equal(t, normalizeBody(`{"b": 1, "a": [1, 2]}`, false), normalizeBody("{\"a\":[1,2],\n\"b\":1}", false))
+	assert.Equal(t, "not json", normalizeBody("not json", false))
+	assert.Equal(t, "a=1&b=2", normalizeQuery("b=2&a=1"))
+}
+
+func TestCassetteRedactsSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	recorder, err := NewCassette(path, Record)
+	require.Nil(t, err)
+	live, err := srv.Client(ts.URL, "test")
+	require.Nil(t, err)
+	recording, err := chef.NewClient(&chef.Config{Name: AdminUser, Key: encodeKey(srv.adminKey), BaseURL: live.BaseURL.String(), RoundTripper: recorder.RoundTripper})
+	require.Nil(t, err)
+	user := chef.User{UserName: "alice", Email: "alice@example.com", FirstName: "Alice", LastName: "Doe", DisplayName: "Alice", Password: "s3cret-passw0rd"}
+	_, err = recording.Users.Create(user)
+	require.Nil(t, err)
+
+	data, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.NotContains(t, string(data), "s3cret-passw0rd")
+	assert.Contains(t, string(data), `\"password\":\"[REDACTED]\"`)
+	ts.Close()
+
+	// the password of the replayed request isn't compared
+	player, err := NewCassette(path, Replay)
+	require.Nil(t, err)
+	replaying, err := chef.NewClient(&chef.Config{Name: AdminUser, Key: encodeKey(srv.adminKey), BaseURL: "https://chef.example.com/organizations/test/", RoundTripper: player.RoundTripper})
+	require.Nil(t, err)
+	user.Password = "another-password"
+	_, err = replaying.Users.Create(user)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,336 @@
+package cheftest
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	chef "github.com/go-chef/chef"
+)
+
+// sandbox is an upload of cookbook files in progress
+type sandbox struct {
+	id        string
+	checksums []string
+	created   time.Time
+	completed bool
+}
+
+// fileURL is where the content of a cookbook file is uploaded and downloaded
+func (r *request) fileURL(checksum string) string {
+	return r.root + "/bookshelf/organization-" + r.org.name + "/checksum-" + checksum
+}
+
+// sandboxes serves the creation and completion of sandboxes
+func (s *Server) sandboxes(r *request) (int, interface{}) {
+	switch {
+	case len(r.path) == 1 && r.Method == http.MethodPost:
+		var req chef.SandboxRequest
+		if err := r.decode(&req); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		if len(req.Checksums) == 0 {
+			return errorf(http.StatusBadRequest, "Field 'checksums' missing")
+		}
+		id := randomID()
+		box := &sandbox{id: id, created: time.Now().UTC()}
+		items := map[string]chef.SandboxItem{}
+		for checksum := range req.Checksums {
+			_, uploaded := r.org.files[checksum]
+			items[checksum] = chef.SandboxItem{Url: r.fileURL(checksum), Upload: !uploaded}
+			box.checksums = append(box.checksums, checksum)
+		}
+		sort.Strings(box.checksums)
+		r.org.sandboxes[id] = box
+		return http.StatusCreated, map[string]interface{}{
+			"sandbox_id": id,
+			"uri":        r.url("sandboxes", id),
+			"checksums":  items,
+		}
+
+	case len(r.path) == 2 && r.Method == http.MethodPut:
+		box, ok := r.org.sandboxes[r.path[1]]
+		if !ok {
+			return errorf(http.StatusNotFound, "No such sandbox '%s'", r.path[1])
+		}
+		var req struct {
+			Completed bool `json:"is_completed"`
+		}
+		if err := r.decode(&req); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		if req.Completed {
+			for _, checksum := range box.checksums {
+				if _, ok := r.org.files[checksum]; !ok {
+					return errorf(http.StatusServiceUnavailable, "Cannot update sandbox %s: checksum %s was not uploaded", box.id, checksum)
+				}
+			}
+			box.completed = true
+			delete(r.org.sandboxes, box.id)
+		}
+		return http.StatusOK, chef.Sandbox{ID: box.id, Name: box.id, CreationTime: box.created, Completed: box.completed, Checksums: box.checksums}
+	}
+	return methodNotAllowed(r)
+}
+
+// bookshelf stores and returns file contents by checksum. Like the urls of the real
+// server the paths need no signature. The caller holds s.mu.
+func (s *Server) bookshelf(r *request) (int, interface{}) {
+	if len(r.path) != 3 || !strings.HasPrefix(r.path[1], "organization-") || !strings.HasPrefix(r.path[2], "checksum-") {
+		return errorf(http.StatusNotFound, "no such file")
+	}
+	o, ok := s.orgs[strings.TrimPrefix(r.path[1], "organization-")]
+	if !ok {
+		return errorf(http.StatusNotFound, "no such file")
+	}
+	checksum := strings.TrimPrefix(r.path[2], "checksum-")
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		data, ok := o.files[checksum]
+		if !ok {
+			return errorf(http.StatusNotFound, "no such file")
+		}
+		return http.StatusOK, data
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return errorf(http.StatusBadRequest, "%v", err)
+		}
+		sum := md5.Sum(data)
+		if hex.EncodeToString(sum[:]) != checksum {
+			return errorf(http.StatusBadRequest, "content does not match checksum %s", checksum)
+		}
+		o.files[checksum] = data
+		return http.StatusNoContent, nil
+	}
+	return methodNotAllowed(r)
+}
+
+// cookbooks serves the cookbook versions of the organization
+func (s *Server) cookbooks(r *request) (int, interface{}) {
+	books := r.org.cookbooks
+	if len(r.path) == 1 || len(r.path) == 2 {
+		if r.Method != http.MethodGet {
+			return methodNotAllowed(r)
+		}
+		if len(r.path) == 1 {
+			return http.StatusOK, r.cookbookList(sortedKeys(books))
+		}
+		switch name := r.path[1]; name {
+		case "_latest":
+			latest := map[string]string{}
+			for name, versions := range books {
+				latest[name] = r.url("cookbooks", name, sortedVersions(versions)[0])
+			}
+			return http.StatusOK, latest
+		case "_recipes":
+			recipes := chef.CookbookRecipesResult{}
+			for _, name := range sortedKeys(books) {
+				recipes = append(recipes, recipeNames(name, books[name][sortedVersions(books[name])[0]])...)
+			}
+			return http.StatusOK, recipes
+		default:
+			if _, ok := books[name]; !ok {
+				return notFound("cookbooks", name)
+			}
+			return http.StatusOK, r.cookbookList([]string{name})
+		}
+	}
+	if len(r.path) != 3 {
+		return errorf(http.StatusNotFound, "no such endpoint /%s", strings.Join(r.path, "/"))
+	}
+
+	name, version := r.path[1], r.path[2]
+	if r.Method == http.MethodPut {
+		var manifest object
+		if err := r.decode(&manifest); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		for _, file := range manifestFiles(manifest) {
+			checksum, _ := file["checksum"].(string)
+			if _, ok := r.org.files[checksum]; !ok {
+				return errorf(http.StatusBadRequest, "Manifest has a checksum that hasn't been uploaded: %s", checksum)
+			}
+			delete(file, "url")
+		}
+		manifest["cookbook_name"] = name
+		manifest["version"] = version
+		manifest["name"] = name + "-" + version
+		if _, ok := books[name]; !ok {
+			books[name] = map[string]object{}
+		}
+		status := http.StatusOK
+		if _, ok := books[name][version]; !ok {
+			status = http.StatusCreated
+		}
+		books[name][version] = manifest
+		return status, manifest
+	}
+
+	versions, ok := books[name]
+	if !ok {
+		return notFound("cookbooks", name)
+	}
+	if version == "_latest" || version == "latest" {
+		version = sortedVersions(versions)[0]
+	}
+	manifest, ok := versions[version]
+	if !ok {
+		return errorf(http.StatusNotFound, "Cannot find a cookbook named %s with version %s", name, version)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		out := clone(manifest)
+		for _, file := range manifestFiles(out) {
+			checksum, _ := file["checksum"].(string)
+			file["url"] = r.fileURL(checksum)
+		}
+		return http.StatusOK, out
+	case http.MethodDelete:
+		delete(versions, version)
+		if len(versions) == 0 {
+			delete(books, name)
+		}
+		return http.StatusOK, manifest
+	}
+	return methodNotAllowed(r)
+}
+
+// cookbookList lists the versions of the named cookbooks, newest first. The
+// num_versions parameter limits the versions of each cookbook, it defaults to one.
+func (r *request) cookbookList(names []string) chef.CookbookListResult {
+	limit := 1
+	switch n := r.URL.Query().Get("num_versions"); n {
+	case "":
+	case "all":
+		limit = -1
+	default:
+		if i, err := strconv.Atoi(n); err == nil {
+			limit = i
+		}
+	}
+	list := chef.CookbookListResult{}
+	for _, name := range names {
+		versions := sortedVersions(r.org.cookbooks[name])
+		if limit >= 0 && len(versions) > limit {
+			versions = versions[:limit]
+		}
+		entry := chef.CookbookVersions{Url: r.url("cookbooks", name), Versions: []chef.CookbookVersion{}}
+		for _, v := range versions {
+			entry.Versions = append(entry.Versions, chef.CookbookVersion{Url: r.url("cookbooks", name, v), Version: v})
+		}
+		list[name] = entry
+	}
+	return list
+}
+
+// sortedVersions returns the versions of a cookbook, newest first
+func sortedVersions(versions map[string]object) []string {
+	list := sortedKeys(versions)
+	sort.SliceStable(list, func(i, j int) bool { return compareVersions(list[i], list[j]) > 0 })
+	return list
+}
+
+// compareVersions compares dotted version numbers such as 1.10.0 and 1.9.2
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// manifestFiles returns the file entries of a cookbook manifest, from the segments of
+// API versions 0 and 1 as well as from all_files
+func manifestFiles(manifest object) []object {
+	var files []object
+	for _, v := range manifest {
+		list, ok := v.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range list {
+			if file, ok := item.(object); ok {
+				if _, ok := file["checksum"]; ok {
+					files = append(files, file)
+				}
+			}
+		}
+	}
+	return files
+}
+
+// recipeNames lists the recipes of a cookbook the way /cookbooks/_recipes names them
+func recipeNames(cookbook string, manifest object) []string {
+	seen := map[string]bool{}
+	var names []string
+	add := func(file string) {
+		recipe := strings.TrimSuffix(file, ".rb")
+		if recipe == file || strings.Contains(recipe, "/") {
+			return
+		}
+		name := cookbook + "::" + recipe
+		if recipe == "default" {
+			name = cookbook
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if recipes, ok := manifest["recipes"].([]interface{}); ok {
+		for _, item := range recipes {
+			if file, ok := item.(object); ok {
+				name, _ := file["name"].(string)
+				add(name)
+			}
+		}
+	}
+	if all, ok := manifest["all_files"].([]interface{}); ok {
+		for _, item := range all {
+			if file, ok := item.(object); ok {
+				name, _ := file["name"].(string)
+				if strings.HasPrefix(name, "recipes/") {
+					add(strings.TrimPrefix(name, "recipes/"))
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// clone returns a deep copy of a json document
+func clone(obj object) object {
+	data, _ := json.Marshal(obj)
+	var out object
+	_ = json.Unmarshal(data, &out)
+	return out
+}
+
+// randomID returns a random 32 character hex id
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,644 @@
+package cheftest
+
+import (
+	"crypto/rsa"
+	"net/http"
+	"sort"
+	"strings"
+
+	chef "github.com/go-chef/chef"
+)
+
+// object is a json document stored by the server
+type object = map[string]interface{}
+
+// org holds the objects of an organization
+type org struct {
+	name     string
+	fullName string
+
+	// objects holds the nodes, roles and environments by kind and name
+	objects   map[string]map[string]object
+	clients   map[string]*principal
+	groups    map[string]*chef.Group
+	users     map[string]bool
+	dataBags  map[string]map[string]object
+	acls      map[string]chef.ACL
+	files     map[string][]byte
+	sandboxes map[string]*sandbox
+	// cookbooks holds the manifests by cookbook name and version
+	cookbooks map[string]map[string]object
+	// policies holds the revisions by policy name and revision id
+	policies map[string]map[string]object
+	// policyGroups holds the revision id of each policy by group
+	policyGroups map[string]map[string]string
+}
+
+// defaultGroups are the groups of a new organization
+var defaultGroups = []string{"admins", "billing-admins", "clients", "users"}
+
+func newOrg(name, fullName string) *org {
+	o := &org{
+		name:         name,
+		fullName:     fullName,
+		objects:      map[string]map[string]object{"nodes": {}, "roles": {}, "environments": {}},
+		clients:      map[string]*principal{},
+		groups:       map[string]*chef.Group{},
+		users:        map[string]bool{},
+		dataBags:     map[string]map[string]object{},
+		acls:         map[string]chef.ACL{},
+		files:        map[string][]byte{},
+		sandboxes:    map[string]*sandbox{},
+		cookbooks:    map[string]map[string]object{},
+		policies:     map[string]map[string]object{},
+		policyGroups: map[string]map[string]string{},
+	}
+	for _, group := range defaultGroups {
+		o.groups[group] = newGroup(name, group)
+	}
+	o.objects["environments"]["_default"] = withDefaults("environments", object{
+		"name":        "_default",
+		"description": "The default Chef environment",
+	})
+	return o
+}
+
+func newGroup(orgName, name string) *chef.Group {
+	return &chef.Group{Name: name, GroupName: name, OrgName: orgName, Actors: []string{}, Clients: []string{}, Groups: []string{}, Users: []string{}}
+}
+
+// addClient registers a client and adds it to the clients group
+func (o *org) addClient(name string, validator bool, key *rsa.PublicKey) {
+	o.clients[name] = &principal{
+		object: object{
+			"name":       name,
+			"clientname": name,
+			"orgname":    o.name,
+			"validator":  validator,
+			"json_class": "Chef::ApiClient",
+			"chef_type":  "client",
+		},
+		key: key,
+	}
+	o.addMember("clients", "clients", name)
+}
+
+// addMember adds a user, client or group to a group. field is "users", "clients" or "groups".
+func (o *org) addMember(group, field, name string) {
+	g, ok := o.groups[group]
+	if !ok {
+		return
+	}
+	if field == "users" {
+		o.users[name] = true
+	}
+	list := map[string]*[]string{"users": &g.Users, "clients": &g.Clients, "groups": &g.Groups}[field]
+	for _, member := range *list {
+		if member == name {
+			return
+		}
+	}
+	*list = append(*list, name)
+}
+
+// objectDefaults returns the fields the Chef server fills in for each kind of object
+var objectDefaults = map[string]func() object{
+	"nodes": func() object {
+		return object{
+			"json_class":       "Chef::Node",
+			"chef_type":        "node",
+			"chef_environment": "_default",
+			"run_list":         []interface{}{},
+			"normal":           object{},
+			"default":          object{},
+			"override":         object{},
+			"automatic":        object{},
+		}
+	},
+	"roles": func() object {
+		return object{
+			"json_class":          "Chef::Role",
+			"chef_type":           "role",
+			"description":         "",
+			"run_list":            []interface{}{},
+			"env_run_lists":       object{},
+			"default_attributes":  object{},
+			"override_attributes": object{},
+		}
+	},
+	"environments": func() object {
+		return object{
+			"json_class":          "Chef::Environment",
+			"chef_type":           "environment",
+			"description":         "",
+			"cookbook_versions":   object{},
+			"default_attributes":  object{},
+			"override_attributes": object{},
+		}
+	},
+}
+
+// withDefaults fills the fields of obj left out by the request
+func withDefaults(kind string, obj object) object {
+	if defaults, ok := objectDefaults[kind]; ok {
+		for k, v := range defaults() {
+			if _, ok := obj[k]; !ok {
+				obj[k] = v
+			}
+		}
+	}
+	return obj
+}
+
+// singular names the kinds of object in error messages
+var singular = map[string]string{
+	"nodes":         "node",
+	"roles":         "role",
+	"environments":  "environment",
+	"clients":       "client",
+	"groups":        "group",
+	"users":         "user",
+	"data":          "data bag",
+	"cookbooks":     "cookbook",
+	"policies":      "policy",
+	"policy_groups": "policy group",
+}
+
+// listURLs maps each name of m to its url, the response to listing a kind of object
+func listURLs[T any](r *request, kind string, m map[string]T) map[string]string {
+	list := make(map[string]string, len(m))
+	for name := range m {
+		list[name] = r.url(kind, name)
+	}
+	return list
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func methodNotAllowed(r *request) (int, interface{}) {
+	return errorf(http.StatusMethodNotAllowed, "method %s not allowed on /%s", r.Method, strings.Join(r.path, "/"))
+}
+
+func notFound(kind, name string) (int, interface{}) {
+	return errorf(http.StatusNotFound, "Cannot load %s %s", singular[kind], name)
+}
+
+// objects serves nodes, roles and environments
+func (s *Server) objects(r *request) (int, interface{}) {
+	kind := r.path[0]
+	store := r.org.objects[kind]
+	if len(r.path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return http.StatusOK, listURLs(r, kind, store)
+		case http.MethodPost:
+			var obj object
+			if err := r.decode(&obj); err != nil {
+				return errorf(http.StatusBadRequest, "invalid json: %v", err)
+			}
+			name, _ := obj["name"].(string)
+			if name == "" {
+				return errorf(http.StatusBadRequest, "Field 'name' missing")
+			}
+			if _, ok := store[name]; ok {
+				return errorf(http.StatusConflict, "%s already exists", singular[kind])
+			}
+			store[name] = withDefaults(kind, obj)
+			return http.StatusCreated, map[string]string{"uri": r.url(kind, name)}
+		}
+		return methodNotAllowed(r)
+	}
+
+	name := r.path[1]
+	obj, ok := store[name]
+	if !ok {
+		return notFound(kind, name)
+	}
+	if len(r.path) == 3 && kind == "environments" && r.path[2] == "nodes" && r.Method == http.MethodGet {
+		nodes := map[string]object{}
+		for nodeName, node := range r.org.objects["nodes"] {
+			if node["chef_environment"] == name {
+				nodes[nodeName] = node
+			}
+		}
+		return http.StatusOK, listURLs(r, "nodes", nodes)
+	}
+	if len(r.path) != 2 {
+		return errorf(http.StatusNotFound, "no such endpoint /%s", strings.Join(r.path, "/"))
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return http.StatusOK, obj
+	case http.MethodPut, http.MethodDelete:
+		if kind == "environments" && name == "_default" {
+			return errorf(http.StatusMethodNotAllowed, "The '_default' environment cannot be modified.")
+		}
+		if r.Method == http.MethodDelete {
+			delete(store, name)
+			return http.StatusOK, obj
+		}
+		var update object
+		if err := r.decode(&update); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		update["name"] = name
+		store[name] = withDefaults(kind, update)
+		return http.StatusOK, update
+	}
+	return methodNotAllowed(r)
+}
+
+// clients serves the API clients of the organization
+func (s *Server) clients(r *request) (int, interface{}) {
+	if len(r.path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return http.StatusOK, listURLs(r, "clients", r.org.clients)
+		case http.MethodPost:
+			var req struct {
+				Name       string `json:"name"`
+				ClientName string `json:"clientname"`
+				Validator  bool   `json:"validator"`
+				PublicKey  string `json:"public_key"`
+			}
+			if err := r.decode(&req); err != nil {
+				return errorf(http.StatusBadRequest, "invalid json: %v", err)
+			}
+			name := req.Name
+			if name == "" {
+				name = req.ClientName
+			}
+			if name == "" {
+				return errorf(http.StatusBadRequest, "Field 'name' missing")
+			}
+			if _, ok := r.org.clients[name]; ok {
+				return errorf(http.StatusConflict, "Client already exists")
+			}
+			key, private, status, body := publicKey(req.PublicKey)
+			if key == nil {
+				return status, body
+			}
+			r.org.addClient(name, req.Validator, key)
+			uri := r.url("clients", name)
+			return http.StatusCreated, chef.ApiClientCreateResult{Uri: uri, ChefKey: chefKey(uri, key, private)}
+		}
+		return methodNotAllowed(r)
+	}
+
+	name := r.path[1]
+	client, ok := r.org.clients[name]
+	if !ok || len(r.path) != 2 {
+		return notFound("clients", name)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, client.object
+	case http.MethodPut:
+		var update struct {
+			Validator *bool `json:"validator"`
+		}
+		if err := r.decode(&update); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		if update.Validator != nil {
+			client.object["validator"] = *update.Validator
+		}
+		return http.StatusOK, client.object
+	case http.MethodDelete:
+		delete(r.org.clients, name)
+		for _, g := range r.org.groups {
+			g.Clients = without(g.Clients, name)
+		}
+		return http.StatusOK, client.object
+	}
+	return methodNotAllowed(r)
+}
+
+// publicKey parses the public key sent to create a client or user, or generates a key
+// pair when none was sent. On failure the key is nil and the error response is returned.
+func publicKey(pemKey string) (key *rsa.PublicKey, private string, status int, body interface{}) {
+	var err error
+	if pemKey != "" {
+		key, err = parsePublicKey(pemKey)
+		if err != nil {
+			status, body = errorf(http.StatusBadRequest, "invalid public_key: %v", err)
+		}
+		return key, "", status, body
+	}
+	key, private, err = newKey()
+	if err != nil {
+		status, body = errorf(http.StatusInternalServerError, "%v", err)
+	}
+	return key, private, status, body
+}
+
+func without(list []string, name string) []string {
+	out := []string{}
+	for _, item := range list {
+		if item != name {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// groups serves the groups of the organization
+func (s *Server) groups(r *request) (int, interface{}) {
+	if len(r.path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return http.StatusOK, listURLs(r, "groups", r.org.groups)
+		case http.MethodPost:
+			var req chef.Group
+			if err := r.decode(&req); err != nil {
+				return errorf(http.StatusBadRequest, "invalid json: %v", err)
+			}
+			name := req.Name
+			if name == "" {
+				name = req.GroupName
+			}
+			if name == "" {
+				return errorf(http.StatusBadRequest, "Field 'name' missing")
+			}
+			if _, ok := r.org.groups[name]; ok {
+				return errorf(http.StatusConflict, "Group already exists")
+			}
+			r.org.groups[name] = newGroup(r.org.name, name)
+			return http.StatusCreated, chef.GroupResult{Uri: r.url("groups", name)}
+		}
+		return methodNotAllowed(r)
+	}
+
+	name := r.path[1]
+	group, ok := r.org.groups[name]
+	if !ok || len(r.path) != 2 {
+		return notFound("groups", name)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		g := *group
+		g.Actors = append(append([]string{}, group.Users...), group.Clients...)
+		return http.StatusOK, g
+	case http.MethodPut:
+		var update chef.GroupUpdate
+		if err := r.decode(&update); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		group.Users = nonNil(update.Actors.Users)
+		group.Clients = nonNil(update.Actors.Clients)
+		group.Groups = nonNil(update.Actors.Groups)
+		for _, user := range group.Users {
+			r.org.users[user] = true
+		}
+		return http.StatusOK, update
+	case http.MethodDelete:
+		delete(r.org.groups, name)
+		return http.StatusOK, group
+	}
+	return methodNotAllowed(r)
+}
+
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// orgUsers serves the users associated with the organization
+func (s *Server) orgUsers(r *request) (int, interface{}) {
+	if len(r.path) == 1 && r.Method == http.MethodGet {
+		list := []map[string]map[string]string{}
+		for _, name := range sortedKeys(r.org.users) {
+			list = append(list, map[string]map[string]string{"user": {"username": name}})
+		}
+		return http.StatusOK, list
+	}
+	if len(r.path) != 2 {
+		return methodNotAllowed(r)
+	}
+	name := r.path[1]
+	user, ok := s.users[name]
+	if !ok || !r.org.users[name] {
+		return notFound("users", name)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, user.object
+	case http.MethodDelete:
+		delete(r.org.users, name)
+		for _, g := range r.org.groups {
+			g.Users = without(g.Users, name)
+		}
+		return http.StatusOK, user.object
+	}
+	return methodNotAllowed(r)
+}
+
+// userObject returns the stored form of a user, without its password and keys
+func userObject(name string, fields object) object {
+	user := object{}
+	for k, v := range fields {
+		switch k {
+		case "password", "public_key", "create_key":
+		default:
+			user[k] = v
+		}
+	}
+	user["username"] = name
+	return user
+}
+
+// usersEndpoint serves the users of the server
+func (s *Server) usersEndpoint(r *request) (int, interface{}) {
+	if len(r.path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return http.StatusOK, listURLs(r, "users", s.users)
+		case http.MethodPost:
+			var req object
+			if err := r.decode(&req); err != nil {
+				return errorf(http.StatusBadRequest, "invalid json: %v", err)
+			}
+			name, _ := req["username"].(string)
+			if name == "" {
+				return errorf(http.StatusBadRequest, "Field 'username' missing")
+			}
+			if _, ok := s.users[name]; ok {
+				return errorf(http.StatusConflict, "User '%s' already exists", name)
+			}
+			pemKey, _ := req["public_key"].(string)
+			key, private, status, body := publicKey(pemKey)
+			if key == nil {
+				return status, body
+			}
+			s.users[name] = &principal{object: userObject(name, req), key: key}
+			uri := r.url("users", name)
+			return http.StatusCreated, chef.UserResult{Uri: uri, ChefKey: chefKey(uri, key, private)}
+		}
+		return methodNotAllowed(r)
+	}
+
+	name := r.path[1]
+	user, ok := s.users[name]
+	if !ok || len(r.path) != 2 {
+		return notFound("users", name)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, user.object
+	case http.MethodPut:
+		var update object
+		if err := r.decode(&update); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		for k, v := range userObject(name, update) {
+			user.object[k] = v
+		}
+		return http.StatusOK, chef.UserResult{Uri: r.url("users", name)}
+	case http.MethodDelete:
+		delete(s.users, name)
+		for _, o := range s.orgs {
+			delete(o.users, name)
+			for _, g := range o.groups {
+				g.Users = without(g.Users, name)
+			}
+		}
+		return http.StatusOK, user.object
+	}
+	return methodNotAllowed(r)
+}
+
+// organizations serves the organizations of the server
+func (s *Server) organizations(r *request) (int, interface{}) {
+	if len(r.path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return http.StatusOK, listURLs(r, "organizations", s.orgs)
+		case http.MethodPost:
+			var req chef.Organization
+			if err := r.decode(&req); err != nil {
+				return errorf(http.StatusBadRequest, "invalid json: %v", err)
+			}
+			if req.Name == "" {
+				return errorf(http.StatusBadRequest, "Field 'name' missing")
+			}
+			if _, ok := s.orgs[req.Name]; ok {
+				return errorf(http.StatusConflict, "Organization already exists")
+			}
+			key, private, err := newKey()
+			if err != nil {
+				return errorf(http.StatusInternalServerError, "%v", err)
+			}
+			o := s.createOrg(req.Name, req.FullName)
+			validator := req.Name + "-validator"
+			o.addClient(validator, true, key)
+			if r.user != "" {
+				o.addMember("admins", "users", r.user)
+				o.addMember("users", "users", r.user)
+			}
+			return http.StatusCreated, chef.OrganizationResult{ClientName: validator, PrivateKey: private, Uri: r.url("organizations", req.Name)}
+		}
+		return methodNotAllowed(r)
+	}
+
+	name := r.path[1]
+	o, ok := s.orgs[name]
+	if !ok {
+		return errorf(http.StatusNotFound, "organization '%s' does not exist", name)
+	}
+	org := chef.Organization{Name: o.name, FullName: o.fullName, Guid: o.name}
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, org
+	case http.MethodDelete:
+		delete(s.orgs, name)
+		return http.StatusOK, org
+	}
+	return methodNotAllowed(r)
+}
+
+// dataBags serves data bags and their items
+func (s *Server) dataBags(r *request) (int, interface{}) {
+	bags := r.org.dataBags
+	if len(r.path) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return http.StatusOK, listURLs(r, "data", bags)
+		case http.MethodPost:
+			var req chef.DataBag
+			if err := r.decode(&req); err != nil {
+				return errorf(http.StatusBadRequest, "invalid json: %v", err)
+			}
+			if req.Name == "" {
+				return errorf(http.StatusBadRequest, "Field 'name' missing")
+			}
+			if _, ok := bags[req.Name]; ok {
+				return errorf(http.StatusConflict, "Data bag already exists")
+			}
+			bags[req.Name] = map[string]object{}
+			return http.StatusCreated, chef.DataBagCreateResult{URI: r.url("data", req.Name)}
+		}
+		return methodNotAllowed(r)
+	}
+
+	bagName := r.path[1]
+	bag, ok := bags[bagName]
+	if !ok {
+		return notFound("data", bagName)
+	}
+	if len(r.path) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			return http.StatusOK, listURLs(r, "data/"+bagName, bag)
+		case http.MethodPost:
+			var item object
+			if err := r.decode(&item); err != nil {
+				return errorf(http.StatusBadRequest, "invalid json: %v", err)
+			}
+			id, _ := item["id"].(string)
+			if id == "" {
+				return errorf(http.StatusBadRequest, "Field 'id' missing")
+			}
+			if _, ok := bag[id]; ok {
+				return errorf(http.StatusConflict, "Data bag item '%s' already exists in data bag '%s'", id, bagName)
+			}
+			bag[id] = item
+			return http.StatusCreated, item
+		case http.MethodDelete:
+			delete(bags, bagName)
+			return http.StatusOK, object{"name": bagName, "json_class": "Chef::DataBag", "chef_type": "data_bag"}
+		}
+		return methodNotAllowed(r)
+	}
+
+	id := r.path[2]
+	item, ok := bag[id]
+	if !ok || len(r.path) != 3 {
+		return errorf(http.StatusNotFound, "Cannot load data bag item %s for data bag %s", id, bagName)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, item
+	case http.MethodPut:
+		var update object
+		if err := r.decode(&update); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		update["id"] = id
+		bag[id] = update
+		return http.StatusOK, update
+	case http.MethodDelete:
+		delete(bag, id)
+		return http.StatusOK, item
+	}
+	return methodNotAllowed(r)
+}
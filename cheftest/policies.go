@@ -0,0 +1,159 @@
+package cheftest
+
+import (
+	"net/http"
+
+	chef "github.com/go-chef/chef"
+)
+
+// policies serves the policy revisions of the organization
+func (s *Server) policies(r *request) (int, interface{}) {
+	policies := r.org.policies
+	if len(r.path) == 1 {
+		if r.Method != http.MethodGet {
+			return methodNotAllowed(r)
+		}
+		list := chef.PoliciesGetResponse{}
+		for name, revisions := range policies {
+			list[name] = chef.Policy{Uri: r.url("policies", name), Revisions: revisionList(revisions)}
+		}
+		return http.StatusOK, list
+	}
+
+	name := r.path[1]
+	revisions, ok := policies[name]
+	if !ok {
+		return notFound("policies", name)
+	}
+	if len(r.path) == 2 {
+		response := map[string]interface{}{"revisions": revisionList(revisions)}
+		switch r.Method {
+		case http.MethodGet:
+			return http.StatusOK, response
+		case http.MethodDelete:
+			delete(policies, name)
+			for _, group := range r.org.policyGroups {
+				delete(group, name)
+			}
+			return http.StatusOK, response
+		}
+		return methodNotAllowed(r)
+	}
+
+	if len(r.path) != 4 || r.path[2] != "revisions" {
+		return errorf(http.StatusNotFound, "no such endpoint")
+	}
+	id := r.path[3]
+	revision, ok := revisions[id]
+	if !ok {
+		return errorf(http.StatusNotFound, "No revision %s of policy %s", id, name)
+	}
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, revision
+	case http.MethodDelete:
+		delete(revisions, id)
+		if len(revisions) == 0 {
+			delete(policies, name)
+		}
+		for _, group := range r.org.policyGroups {
+			if group[name] == id {
+				delete(group, name)
+			}
+		}
+		return http.StatusOK, revision
+	}
+	return methodNotAllowed(r)
+}
+
+// revisionList is the revisions field of the policy listings
+func revisionList(revisions map[string]object) map[string]interface{} {
+	list := map[string]interface{}{}
+	for id := range revisions {
+		list[id] = object{}
+	}
+	return list
+}
+
+// policyGroups serves the policy groups of the organization and the revisions assigned in them
+func (s *Server) policyGroups(r *request) (int, interface{}) {
+	groups := r.org.policyGroups
+	if len(r.path) == 1 {
+		if r.Method != http.MethodGet {
+			return methodNotAllowed(r)
+		}
+		list := chef.PolicyGroupGetResponse{}
+		for name := range groups {
+			list[name] = r.policyGroup(name)
+		}
+		return http.StatusOK, list
+	}
+
+	name := r.path[1]
+	if len(r.path) == 2 {
+		if _, ok := groups[name]; !ok {
+			return notFound("policy_groups", name)
+		}
+		group := r.policyGroup(name)
+		switch r.Method {
+		case http.MethodGet:
+			return http.StatusOK, group
+		case http.MethodDelete:
+			delete(groups, name)
+			return http.StatusOK, group
+		}
+		return methodNotAllowed(r)
+	}
+
+	if len(r.path) != 4 || r.path[2] != "policies" {
+		return errorf(http.StatusNotFound, "no such endpoint")
+	}
+	policy := r.path[3]
+	if r.Method == http.MethodPut {
+		var revision object
+		if err := r.decode(&revision); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		id, _ := revision["revision_id"].(string)
+		if id == "" {
+			return errorf(http.StatusBadRequest, "Field 'revision_id' missing")
+		}
+		revision["name"] = policy
+		if _, ok := r.org.policies[policy]; !ok {
+			r.org.policies[policy] = map[string]object{}
+		}
+		r.org.policies[policy][id] = revision
+		if _, ok := groups[name]; !ok {
+			groups[name] = map[string]string{}
+		}
+		status := http.StatusOK
+		if _, ok := groups[name][policy]; !ok {
+			status = http.StatusCreated
+		}
+		groups[name][policy] = id
+		return status, revision
+	}
+
+	id, ok := groups[name][policy]
+	if !ok {
+		return errorf(http.StatusNotFound, "No policy %s in policy group %s", policy, name)
+	}
+	revision := r.org.policies[policy][id]
+	switch r.Method {
+	case http.MethodGet:
+		return http.StatusOK, revision
+	case http.MethodDelete:
+		delete(groups[name], policy)
+		return http.StatusOK, revision
+	}
+	return methodNotAllowed(r)
+}
+
+// policyGroup is the listing of a policy group
+func (r *request) policyGroup(name string) chef.PolicyGroup {
+	group := chef.PolicyGroup{Uri: r.url("policy_groups", name), Policies: map[string]chef.Revision{}}
+	for policy, id := range r.org.policyGroups[name] {
+		group.Policies[policy] = chef.Revision{"revision_id": id}
+	}
+	return group
+}
@@ -0,0 +1,139 @@
+package cheftest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	chef "github.com/go-chef/chef"
+)
+
+// searchDoc is an object in a search index
+type searchDoc struct {
+	url string
+	// row is the object returned by a full search
+	row object
+	// source is the object partial search paths are resolved against
+	source object
+	fields map[string][]string
+}
+
+// search serves /search, the list of indexes, and queries of an index at /search/{index}.
+// A POST runs a partial search returning the paths named in the body.
+func (s *Server) search(r *request) (int, interface{}) {
+	if len(r.path) == 1 {
+		if r.Method != http.MethodGet {
+			return methodNotAllowed(r)
+		}
+		indexes := map[string]string{}
+		for _, index := range append([]string{"client", "environment", "node", "role"}, sortedKeys(r.org.dataBags)...) {
+			indexes[index] = r.url("search", index)
+		}
+		return http.StatusOK, indexes
+	}
+	if len(r.path) != 2 || (r.Method != http.MethodGet && r.Method != http.MethodPost) {
+		return methodNotAllowed(r)
+	}
+
+	docs, ok := r.org.index(r, r.path[1])
+	if !ok {
+		return errorf(http.StatusNotFound, "I don't know how to search for %s data objects.", r.path[1])
+	}
+	params := r.URL.Query()
+	q := params.Get("q")
+	if q == "" {
+		q = "*:*"
+	}
+	query, err := chef.ParseQuery(q)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "invalid search query: '%s': %v", q, err)
+	}
+	start, _ := strconv.Atoi(params.Get("start"))
+	rows, err := strconv.Atoi(params.Get("rows"))
+	if err != nil || rows <= 0 {
+		rows = 1000
+	}
+
+	var matched []searchDoc
+	for _, doc := range docs {
+		if query.MatchFields(doc.fields) {
+			matched = append(matched, doc)
+		}
+	}
+	page := []interface{}{}
+	for i := start; i >= 0 && i < len(matched) && i < start+rows; i++ {
+		page = append(page, matched[i].row)
+	}
+
+	if r.Method == http.MethodPost {
+		var paths map[string][]string
+		if err := r.decode(&paths); err != nil {
+			return errorf(http.StatusBadRequest, "invalid json: %v", err)
+		}
+		for i := range page {
+			doc := matched[start+i]
+			data := object{}
+			for alias, path := range paths {
+				data[alias] = lookup(doc.source, path)
+			}
+			page[i] = object{"url": doc.url, "data": data}
+		}
+	}
+	return http.StatusOK, object{"total": len(matched), "start": start, "rows": page}
+}
+
+// newSearchDoc indexes obj the way the Chef server does, with chef.SearchDocument and chef.SearchFields
+func newSearchDoc(url string, obj object) searchDoc {
+	source, _ := chef.SearchDocument(obj)
+	fields, _ := chef.SearchFields(obj)
+	return searchDoc{url: url, row: obj, source: source, fields: fields}
+}
+
+// index returns the documents of a search index sorted by url
+func (o *org) index(r *request, name string) ([]searchDoc, bool) {
+	var docs []searchDoc
+	switch name {
+	case "node", "role", "environment":
+		kind := name + "s"
+		for objName, obj := range o.objects[kind] {
+			docs = append(docs, newSearchDoc(r.url(kind, objName), obj))
+		}
+	case "client":
+		for objName, c := range o.clients {
+			docs = append(docs, newSearchDoc(r.url("clients", objName), c.object))
+		}
+	default:
+		bag, ok := o.dataBags[name]
+		if !ok {
+			return nil, false
+		}
+		for id, item := range bag {
+			row := object{
+				"name":       "data_bag_item_" + name + "_" + id,
+				"data_bag":   name,
+				"json_class": "Chef::DataBagItem",
+				"chef_type":  "data_bag_item",
+				"raw_data":   item,
+			}
+			doc := newSearchDoc(r.url("data", name, id), row)
+			// partial search paths resolve against the item itself
+			doc.source = item
+			docs = append(docs, doc)
+		}
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].url < docs[j].url })
+	return docs, true
+}
+
+// lookup resolves a partial search path, nil when it doesn't exist
+func lookup(obj object, path []string) interface{} {
+	var v interface{} = obj
+	for _, key := range path {
+		m, ok := v.(object)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
@@ -0,0 +1,349 @@
+// Package cheftest provides an in-memory Chef Infra Server for tests. A Server is an
+// http.Handler keeping nodes, roles, environments, clients, users, data bags, cookbooks,
+// sandboxes, policies, policy groups, groups and ACLs in memory. It answers a basic
+// search and checks the request signatures like the real server does.
+//
+//	srv := cheftest.NewServer()
+//	ts := httptest.NewServer(srv)
+//	defer ts.Close()
+//
+//	client, err := srv.Client(ts.URL, "test")
+//	node, err := client.Nodes.Get("web1")
+//
+// Permissions are not enforced: every authenticated request may do anything. ACLs are
+// kept so they can be read back, but they don't restrict access.
+package cheftest
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	chef "github.com/go-chef/chef"
+	"github.com/go-chef/chef/verify"
+)
+
+// AdminUser is the user created by Client and added to the admins group of every organization it is used with
+const AdminUser = "pivotal"
+
+// Server is an in-memory Chef server. Create it with NewServer and serve it with
+// net/http/httptest or any http.Server.
+type Server struct {
+	// SkipSignatures accepts requests without checking their signature.
+	// The X-Ops-UserId header is still used as the requesting user.
+	SkipSignatures bool
+
+	mu    sync.Mutex
+	users map[string]*principal
+	orgs  map[string]*org
+	// adminKey is the private key of AdminUser, generated on first use
+	adminKey *rsa.PrivateKey
+}
+
+// principal is a user or a client with its public key
+type principal struct {
+	object map[string]interface{}
+	key    *rsa.PublicKey
+}
+
+// NewServer returns an empty Server. Organizations are created with CreateOrganization,
+// by Client or through the API.
+func NewServer() *Server {
+	return &Server{
+		users: map[string]*principal{},
+		orgs:  map[string]*org{},
+	}
+}
+
+// CreateOrganization creates the named organization with its default groups and the
+// _default environment. Creating an organization that exists does nothing.
+func (s *Server) CreateOrganization(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.createOrg(name, name)
+}
+
+// AddUser registers a user with its public key, replacing any user of the same name
+func (s *Server) AddUser(name string, key *rsa.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[name] = &principal{object: userObject(name, nil), key: key}
+}
+
+// AddClient registers a client of the organization with its public key, replacing any
+// client of the same name. The organization is created if needed.
+func (s *Server) AddClient(orgName, name string, key *rsa.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.createOrg(orgName, orgName)
+	o.addClient(name, false, key)
+}
+
+// Client returns a client for the organization signing as AdminUser. baseURL is the
+// root of the server, for example the URL of an httptest.Server. The organization and
+// the user are created as needed, the user's key is shared by all clients of s.
+func (s *Server) Client(baseURL, orgName string) (*chef.Client, error) {
+	s.mu.Lock()
+	if s.adminKey == nil {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			s.mu.Unlock()
+			return nil, err
+		}
+		s.adminKey = key
+		s.users[AdminUser] = &principal{object: userObject(AdminUser, nil), key: &key.PublicKey}
+	}
+	key := s.adminKey
+	o := s.createOrg(orgName, orgName)
+	o.addMember("admins", "users", AdminUser)
+	o.addMember("users", "users", AdminUser)
+	s.mu.Unlock()
+
+	return chef.NewClient(&chef.Config{
+		Name:    AdminUser,
+		Key:     encodeKey(key),
+		BaseURL: strings.TrimSuffix(baseURL, "/") + "/organizations/" + orgName + "/",
+	})
+}
+
+// createOrg returns the named organization, creating it if needed. The caller holds s.mu.
+func (s *Server) createOrg(name, fullName string) *org {
+	if o, ok := s.orgs[name]; ok {
+		return o
+	}
+	o := newOrg(name, fullName)
+	s.orgs[name] = o
+	return o
+}
+
+// request is an API request being served
+type request struct {
+	*http.Request
+	// org is the organization named in the path, nil for the global endpoints
+	org *org
+	// path are the segments of the path after the organization
+	path []string
+	// root is the URL of the server
+	root string
+	// user is the authenticated user or client
+	user string
+}
+
+// url returns the URL of an object in the organization of the request, or of a global
+// object when there is none
+func (r *request) url(parts ...string) string {
+	u := r.root
+	if r.org != nil {
+		u += "/organizations/" + r.org.name
+	}
+	return u + "/" + strings.Join(parts, "/")
+}
+
+// decode reads the json request body into v
+func (r *request) decode(v interface{}) error {
+	if r.Body == nil {
+		return errors.New("missing request body")
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// ServeHTTP serves the Chef server API
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := &request{Request: r, path: splitPath(r.URL.Path), root: "http://" + r.Host}
+	if r.TLS != nil {
+		req.root = "https://" + r.Host
+	}
+	status, body := s.serve(req)
+	reply(w, r, status, body)
+}
+
+func (s *Server) serve(r *request) (int, interface{}) {
+	if len(r.path) == 0 {
+		return errorf(http.StatusNotFound, "no such endpoint")
+	}
+
+	// file contents are fetched through the urls handed out by the server,
+	// the status page is public
+	switch r.path[0] {
+	case "bookshelf":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.bookshelf(r)
+	case "_status":
+		return http.StatusOK, map[string]interface{}{"status": "pong", "upstreams": map[string]string{}, "keygen": map[string]int{}}
+	}
+
+	if r.path[0] == "organizations" && len(r.path) > 2 {
+		s.mu.Lock()
+		r.org = s.orgs[r.path[1]]
+		s.mu.Unlock()
+		if r.org == nil {
+			return errorf(http.StatusNotFound, "organization '%s' does not exist", r.path[1])
+		}
+		r.path = r.path[2:]
+	}
+
+	user, err := s.authenticate(r)
+	if err != nil {
+		return errorf(http.StatusUnauthorized, "%v", err)
+	}
+	r.user = user
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.route(r)
+}
+
+// authenticate verifies the request signature and returns the requesting user or client
+func (s *Server) authenticate(r *request) (string, error) {
+	if s.SkipSignatures {
+		return r.Header.Get("X-Ops-UserId"), nil
+	}
+	v := verify.New(func(_ context.Context, name string) (*rsa.PublicKey, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if r.org != nil {
+			if c, ok := r.org.clients[name]; ok {
+				return c.key, nil
+			}
+		}
+		if u, ok := s.users[name]; ok {
+			return u.key, nil
+		}
+		return nil, fmt.Errorf("'%s' not found", name)
+	})
+	return v.Verify(r.Request)
+}
+
+// route dispatches an authenticated request. The caller holds s.mu.
+func (s *Server) route(r *request) (int, interface{}) {
+	if r.org == nil {
+		switch r.path[0] {
+		case "users":
+			return s.usersEndpoint(r)
+		case "organizations":
+			return s.organizations(r)
+		}
+		return errorf(http.StatusNotFound, "no such endpoint /%s", strings.Join(r.path, "/"))
+	}
+
+	if len(r.path) >= 3 && r.path[2] == "_acl" {
+		return s.acl(r)
+	}
+	switch r.path[0] {
+	case "nodes", "roles", "environments":
+		return s.objects(r)
+	case "clients":
+		return s.clients(r)
+	case "groups":
+		return s.groups(r)
+	case "users":
+		return s.orgUsers(r)
+	case "data":
+		return s.dataBags(r)
+	case "sandboxes":
+		return s.sandboxes(r)
+	case "cookbooks":
+		return s.cookbooks(r)
+	case "policies":
+		return s.policies(r)
+	case "policy_groups":
+		return s.policyGroups(r)
+	case "search":
+		return s.search(r)
+	}
+	return errorf(http.StatusNotFound, "no such endpoint /%s", strings.Join(r.path, "/"))
+}
+
+// splitPath splits an url path into its non empty segments
+func splitPath(p string) []string {
+	var parts []string
+	for _, part := range strings.Split(p, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// errorf returns a Chef style error response
+func errorf(status int, format string, args ...interface{}) (int, interface{}) {
+	return status, map[string][]string{"error": {fmt.Sprintf(format, args...)}}
+}
+
+// reply writes a response. A []byte body is written as is, anything else as json.
+func reply(w http.ResponseWriter, r *http.Request, status int, body interface{}) {
+	if raw, ok := body.([]byte); ok {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(status)
+		if r.Method != http.MethodHead {
+			_, _ = w.Write(raw)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead && body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// newKey generates a key pair for a client or user and returns the private key in PEM format
+func newKey() (*rsa.PublicKey, string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, "", err
+	}
+	return &key.PublicKey, encodeKey(key), nil
+}
+
+func encodeKey(key *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func encodePublicKey(key *rsa.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return ""
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// parsePublicKey reads a PKIX or PKCS1 public key in PEM format
+func parsePublicKey(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("public key is not in PEM format")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// chefKey returns the chef_key of a create response
+func chefKey(uri string, key *rsa.PublicKey, private string) chef.ChefKey {
+	return chef.ChefKey{
+		Name:           "default",
+		PublicKey:      encodePublicKey(key),
+		ExpirationDate: "infinity",
+		Uri:            uri + "/keys/default",
+		PrivateKey:     private,
+	}
+}
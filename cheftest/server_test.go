@@ -0,0 +1,316 @@
+package cheftest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chef "github.com/go-chef/chef"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*Server, *httptest.Server, *chef.Client) {
+	srv := NewServer()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	client, err := srv.Client(ts.URL, "test")
+	require.Nil(t, err)
+	return srv, ts, client
+}
+
+func statusCode(err error) int {
+	var errResp *chef.ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.StatusCode()
+	}
+	return 0
+}
+
+func TestNodes(t *testing.T) {
+	_, _, client := newTestServer(t)
+
+	node := chef.NewNode("web1")
+	node.RunList = []string{"recipe[nginx]"}
+	_, err := client.Nodes.Post(node)
+	require.Nil(t, err)
+
+	_, err = client.Nodes.Post(node)
+	assert.Equal(t, http.StatusConflict, statusCode(err))
+
+	got, err := client.Nodes.Get("web1")
+	assert.Nil(t, err)
+	assert.Equal(t, "Chef::Node", got.JsonClass)
+	assert.Equal(t, []string{"recipe[nginx]"}, got.RunList)
+
+	got.NormalAttributes = map[string]interface{}{"tier": "frontend"}
+	_, err = client.Nodes.Put(got)
+	assert.Nil(t, err)
+	got, _ = client.Nodes.Get("web1")
+	assert.Equal(t, "frontend", got.NormalAttributes["tier"])
+
+	list, err := client.Nodes.List()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"web1": client.BaseURL.String() + "nodes/web1"}, list)
+
+	assert.Nil(t, client.Nodes.Delete("web1"))
+	_, err = client.Nodes.Get("web1")
+	assert.Equal(t, http.StatusNotFound, statusCode(err))
+}
+
+func TestRolesAndEnvironments(t *testing.T) {
+	_, _, client := newTestServer(t)
+
+	_, err := client.Roles.Create(&chef.Role{Name: "web", RunList: chef.RunList{"recipe[nginx]"}})
+	assert.Nil(t, err)
+	role, err := client.Roles.Get("web")
+	assert.Nil(t, err)
+	assert.Equal(t, chef.RunList{"recipe[nginx]"}, role.RunList)
+
+	_, err = client.Environments.Create(&chef.Environment{Name: "prod"})
+	assert.Nil(t, err)
+	envs, err := client.Environments.List()
+	assert.Nil(t, err)
+	assert.Len(t, *envs, 2, "prod and _default")
+
+	_, err = client.Environments.Delete("_default")
+	assert.Equal(t, http.StatusMethodNotAllowed, statusCode(err))
+}
+
+func TestDataBagsAndSearch(t *testing.T) {
+	_, _, client := newTestServer(t)
+
+	_, err := client.DataBags.Create(&chef.DataBag{Name: "users"})
+	require.Nil(t, err)
+	for _, item := range []map[string]interface{}{
+		{"id": "alice", "shell": "/bin/zsh", "groups": []string{"admin", "dev"}},
+		{"id": "bob", "shell": "/bin/bash", "groups": []string{"dev"}},
+	} {
+		assert.Nil(t, client.DataBags.CreateItem("users", item))
+	}
+	item, err := client.DataBags.GetItem("users", "alice")
+	assert.Nil(t, err)
+	assert.Equal(t, "/bin/zsh", item.(map[string]interface{})["shell"])
+
+	for name, env := range map[string]string{"web1": "prod", "web2": "prod", "db1": "dev"} {
+		node := chef.NewNode(name)
+		node.Environment = env
+		node.AutomaticAttributes = map[string]interface{}{"os": "linux", "kernel": map[string]interface{}{"release": "6.1." + name}}
+		_, err := client.Nodes.Post(node)
+		require.Nil(t, err)
+	}
+
+	cases := []struct {
+		index, query string
+		total        int
+	}{
+		{"node", "*:*", 3},
+		{"node", "chef_environment:prod", 2},
+		{"node", "name:web*", 2},
+		{"node", "name:web? AND NOT chef_environment:prod", 0},
+		{"node", "kernel_release:6.1.db1 OR name:web1", 2},
+		{"node", "release:6.1.*", 3},
+		{"node", "os:windows", 0},
+		{"users", "groups:admin", 1},
+		{"users", "groups:dev", 2},
+		{"users", `shell:"/bin/bash"`, 1},
+	}
+	for _, c := range cases {
+		res, err := client.Search.Exec(c.index, c.query)
+		if assert.Nil(t, err, c.query) {
+			assert.Equal(t, c.total, res.Total, c.query)
+		}
+	}
+
+	res, err := client.Search.PartialExec("node", "name:db1", map[string]interface{}{"kernel": []string{"kernel", "release"}})
+	assert.Nil(t, err)
+	if assert.Len(t, res.Rows, 1) {
+		row := res.Rows[0].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"kernel": "6.1.db1"}, row["data"])
+	}
+
+	indexes, err := client.Search.Indexes()
+	assert.Nil(t, err)
+	assert.Contains(t, indexes, "users")
+
+	_, err = client.Search.Exec("nodez", "*:*")
+	assert.Equal(t, http.StatusNotFound, statusCode(err))
+}
+
+func TestCookbookUpload(t *testing.T) {
+	_, _, client := newTestServer(t)
+
+	content := []byte("package 'nginx'\n")
+	sum := md5.Sum(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	box, err := client.Sandboxes.Post([]string{checksum})
+	require.Nil(t, err)
+	item := box.Checksums[checksum]
+	assert.True(t, item.Upload)
+
+	// completing the sandbox before the upload fails
+	_, err = client.Sandboxes.Put(box.ID)
+	assert.NotNil(t, err)
+
+	req, _ := http.NewRequest("PUT", item.Url, strings.NewReader(string(content)))
+	resp, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	done, err := client.Sandboxes.Put(box.ID)
+	assert.Nil(t, err)
+	assert.True(t, done.Completed)
+
+	manifest := chef.Cookbook{
+		CookbookName: "nginx",
+		Name:         "nginx-1.0.0",
+		Version:      "1.0.0",
+		Recipes:      []chef.CookbookItem{{Name: "default.rb", Path: "recipes/default.rb", Checksum: checksum, Specificity: "default"}},
+		Metadata:     chef.CookbookMeta{Name: "nginx", Version: "1.0.0"},
+	}
+	body, _ := chef.JSONReader(manifest)
+	put, err := client.NewRequest("PUT", "cookbooks/nginx/1.0.0", body)
+	require.Nil(t, err)
+	_, err = client.Do(put, nil)
+	require.Nil(t, err)
+
+	versions, err := client.Cookbooks.GetAvailableVersions("nginx", "all")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.0.0", versions["nginx"].Versions[0].Version)
+
+	recipes, err := client.Cookbooks.ListAllRecipes()
+	assert.Nil(t, err)
+	assert.Equal(t, chef.CookbookRecipesResult{"nginx"}, recipes)
+
+	dir := t.TempDir()
+	require.Nil(t, client.Cookbooks.DownloadTo("nginx", "latest", dir))
+	data, err := os.ReadFile(filepath.Join(dir, "nginx-1.0.0", "recipes", "default.rb"))
+	assert.Nil(t, err)
+	assert.Equal(t, content, data)
+}
+
+func TestPolicies(t *testing.T) {
+	_, _, client := newTestServer(t)
+
+	revision := chef.RevisionDetailsResponse{RevisionID: "abc123", Name: "base", RunList: []string{"recipe[base::default]"}}
+	body, _ := chef.JSONReader(revision)
+	put, err := client.NewRequest("PUT", "policy_groups/prod/policies/base", body)
+	require.Nil(t, err)
+	_, err = client.Do(put, nil)
+	require.Nil(t, err)
+
+	groups, err := client.PolicyGroups.List()
+	assert.Nil(t, err)
+	assert.Equal(t, "abc123", groups["prod"].Policies["base"]["revision_id"])
+
+	got, err := client.Policies.GetRevisionDetails("base", "abc123")
+	assert.Nil(t, err)
+	assert.Equal(t, revision.RunList, got.RunList)
+
+	policy, err := client.PolicyGroups.GetPolicy("prod", "base")
+	assert.Nil(t, err)
+	assert.Equal(t, "abc123", policy.RevisionID)
+}
+
+func TestGroupsAndACLs(t *testing.T) {
+	_, _, client := newTestServer(t)
+
+	group, err := client.Groups.Get("admins")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{AdminUser}, group.Users)
+
+	_, err = client.Groups.Create(chef.Group{Name: "ops"})
+	assert.Nil(t, err)
+	update := chef.GroupUpdate{Name: "ops", GroupName: "ops"}
+	update.Actors.Users = []string{AdminUser}
+	_, err = client.Groups.Update(update)
+	assert.Nil(t, err)
+	group, _ = client.Groups.Get("ops")
+	assert.Equal(t, []string{AdminUser}, group.Actors)
+
+	_, err = client.Nodes.Post(chef.NewNode("web1"))
+	require.Nil(t, err)
+	acl, err := client.ACLs.Get("nodes", "web1")
+	assert.Nil(t, err)
+	assert.Contains(t, acl["read"].Groups, "admins")
+
+	newACL := chef.NewACL("read", chef.ACLitem{}, chef.ACLitem{"ops"}, chef.ACLitem{AdminUser}, chef.ACLitem{})
+	assert.Nil(t, client.ACLs.Put("nodes", "web1", "read", newACL))
+	acl, _ = client.ACLs.Get("nodes", "web1")
+	assert.Equal(t, chef.ACLitem{"ops"}, acl["read"].Groups)
+
+	_, err = client.ACLs.Get("nodes", "missing")
+	assert.Equal(t, http.StatusNotFound, statusCode(err))
+}
+
+func TestClientsAndSignatures(t *testing.T) {
+	_, ts, client := newTestServer(t)
+
+	created, err := client.Clients.Create(chef.ApiNewClient{Name: "node1", CreateKey: true})
+	require.Nil(t, err)
+	assert.NotEmpty(t, created.ChefKey.PrivateKey)
+
+	nodeClient, err := chef.NewClient(&chef.Config{Name: "node1", Key: created.ChefKey.PrivateKey, BaseURL: ts.URL + "/organizations/test/"})
+	require.Nil(t, err)
+	_, err = nodeClient.Nodes.List()
+	assert.Nil(t, err, "the new client's key is accepted")
+
+	_, otherKey, err := newKey()
+	require.Nil(t, err)
+	forged, err := chef.NewClient(&chef.Config{Name: "node1", Key: otherKey, BaseURL: ts.URL + "/organizations/test/"})
+	require.Nil(t, err)
+	_, err = forged.Nodes.List()
+	assert.Equal(t, http.StatusUnauthorized, statusCode(err))
+
+	unknown, err := chef.NewClient(&chef.Config{Name: "nobody", Key: otherKey, BaseURL: ts.URL + "/organizations/test/"})
+	require.Nil(t, err)
+	_, err = unknown.Nodes.List()
+	assert.Equal(t, http.StatusUnauthorized, statusCode(err))
+
+	assert.Nil(t, client.Clients.Delete("node1"))
+	_, err = nodeClient.Nodes.List()
+	assert.Equal(t, http.StatusUnauthorized, statusCode(err), "deleted clients are rejected")
+}
+
+func TestSkipSignatures(t *testing.T) {
+	srv := NewServer()
+	srv.SkipSignatures = true
+	srv.CreateOrganization("test")
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/organizations/test/environments/_default")
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestUsersAndOrganizations(t *testing.T) {
+	_, _, client := newTestServer(t)
+
+	_, err := client.Users.Create(chef.User{UserName: "alice", Email: "alice@example.com", CreateKey: true})
+	assert.Nil(t, err)
+	users, err := client.Users.List()
+	assert.Nil(t, err)
+	assert.Contains(t, users, "alice")
+	assert.Contains(t, users, AdminUser)
+
+	result, err := client.Organizations.Create(chef.Organization{Name: "acme", FullName: "Acme"})
+	require.Nil(t, err)
+	assert.Equal(t, "acme-validator", result.ClientName)
+
+	acme, err := client.ForOrganization("acme")
+	require.Nil(t, err)
+	_, err = acme.Nodes.Post(chef.NewNode("web1"))
+	assert.Nil(t, err)
+	_, err = client.Nodes.Get("web1")
+	assert.Equal(t, http.StatusNotFound, statusCode(err), "organizations are separate")
+}
@@ -1,6 +1,9 @@
 package chef
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type ApiClientService struct {
 	client *Client
@@ -46,7 +49,12 @@ func (c ApiClientListResult) String() (out string) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server/#get-11
 func (e *ApiClientService) List() (data ApiClientListResult, err error) {
-	err = e.client.magicRequestDecoder("GET", "clients", nil, &data)
+	return e.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (e *ApiClientService) ListWithContext(ctx context.Context) (data ApiClientListResult, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "clients", nil, &data)
 	return
 }
 
@@ -54,11 +62,16 @@ func (e *ApiClientService) List() (data ApiClientListResult, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#clients
 func (e *ApiClientService) Create(client ApiNewClient) (data *ApiClientCreateResult, err error) {
+	return e.CreateWithContext(context.Background(), client)
+}
+
+// CreateWithContext is Create with a context for cancellation and deadlines.
+func (e *ApiClientService) CreateWithContext(ctx context.Context, client ApiNewClient) (data *ApiClientCreateResult, err error) {
 	body, err := JSONReader(client)
 	if err != nil {
 		return
 	}
-	err = e.client.magicRequestDecoder("POST", "clients", body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "POST", "clients", body, &data)
 	return
 }
 
@@ -66,8 +79,13 @@ func (e *ApiClientService) Create(client ApiNewClient) (data *ApiClientCreateRes
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#clients-name
 func (e *ApiClientService) Delete(name string) (err error) {
+	return e.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (e *ApiClientService) DeleteWithContext(ctx context.Context, name string) (err error) {
 	url := fmt.Sprintf("clients/%s", name)
-	err = e.client.magicRequestDecoder("DELETE", url, nil, nil)
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", url, nil, nil)
 	return
 }
 
@@ -75,8 +93,13 @@ func (e *ApiClientService) Delete(name string) (err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#clients-name
 func (e *ApiClientService) Get(name string) (client ApiClient, err error) {
+	return e.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *ApiClientService) GetWithContext(ctx context.Context, name string) (client ApiClient, err error) {
 	url := fmt.Sprintf("clients/%s", name)
-	err = e.client.magicRequestDecoder("GET", url, nil, &client)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &client)
 	return
 }
 
@@ -84,12 +107,17 @@ func (e *ApiClientService) Get(name string) (client ApiClient, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#clients-name
 func (e *ApiClientService) Update(name string, client ApiNewClient) (data *ApiClient, err error) {
+	return e.UpdateWithContext(context.Background(), name, client)
+}
+
+// UpdateWithContext is Update with a context for cancellation and deadlines.
+func (e *ApiClientService) UpdateWithContext(ctx context.Context, name string, client ApiNewClient) (data *ApiClient, err error) {
 	body, err := JSONReader(client)
 	url := fmt.Sprintf("clients/%s", name)
 	if err != nil {
 		return
 	}
-	err = e.client.magicRequestDecoder("PUT", url, body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "PUT", url, body, &data)
 	return
 }
 
@@ -97,8 +125,13 @@ func (e *ApiClientService) Update(name string, client ApiNewClient) (data *ApiCl
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#clients-client-keys
 func (e *ApiClientService) ListKeys(name string) (data []KeyItem, err error) {
+	return e.ListKeysWithContext(context.Background(), name)
+}
+
+// ListKeysWithContext is ListKeys with a context for cancellation and deadlines.
+func (e *ApiClientService) ListKeysWithContext(ctx context.Context, name string) (data []KeyItem, err error) {
 	url := fmt.Sprintf("clients/%s/keys", name)
-	err = e.client.magicRequestDecoder("GET", url, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &data)
 	return
 }
 
@@ -112,9 +145,14 @@ func (e *ApiClientService) ListKeys(name string) (data []KeyItem, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#clients-name
 func (e *ApiClientService) AddKey(name string, keyadd AccessKey) (key KeyItem, err error) {
+	return e.AddKeyWithContext(context.Background(), name, keyadd)
+}
+
+// AddKeyWithContext is AddKey with a context for cancellation and deadlines.
+func (e *ApiClientService) AddKeyWithContext(ctx context.Context, name string, keyadd AccessKey) (key KeyItem, err error) {
 	url := fmt.Sprintf("clients/%s/keys", name)
 	body, err := JSONReader(keyadd)
-	err = e.client.magicRequestDecoder("POST", url, body, &key)
+	err = e.client.magicRequestDecoderWithContext(ctx, "POST", url, body, &key)
 	return
 }
 
@@ -127,8 +165,13 @@ func (e *ApiClientService) AddKey(name string, keyadd AccessKey) (key KeyItem, e
 //
 // Chef API docs: https://docs.chef.io/api_chef_server/#clientskeys
 func (e *ApiClientService) DeleteKey(name string, keyname string) (key AccessKey, err error) {
+	return e.DeleteKeyWithContext(context.Background(), name, keyname)
+}
+
+// DeleteKeyWithContext is DeleteKey with a context for cancellation and deadlines.
+func (e *ApiClientService) DeleteKeyWithContext(ctx context.Context, name string, keyname string) (key AccessKey, err error) {
 	url := fmt.Sprintf("clients/%s/keys/%s", name, keyname)
-	err = e.client.magicRequestDecoder("DELETE", url, nil, &key)
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", url, nil, &key)
 	return
 }
 
@@ -136,8 +179,13 @@ func (e *ApiClientService) DeleteKey(name string, keyname string) (key AccessKey
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#clients-client-keys-key
 func (e *ApiClientService) GetKey(name string, keyname string) (key AccessKey, err error) {
+	return e.GetKeyWithContext(context.Background(), name, keyname)
+}
+
+// GetKeyWithContext is GetKey with a context for cancellation and deadlines.
+func (e *ApiClientService) GetKeyWithContext(ctx context.Context, name string, keyname string) (key AccessKey, err error) {
 	url := fmt.Sprintf("clients/%s/keys/%s", name, keyname)
-	err = e.client.magicRequestDecoder("GET", url, nil, &key)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &key)
 	return
 }
 
@@ -150,8 +198,13 @@ func (e *ApiClientService) GetKey(name string, keyname string) (key AccessKey, e
 //
 // Chef API docs: https://docs.chef.io/api_chef_server/#clientskeys
 func (e *ApiClientService) UpdateKey(name string, keyname string, keyupd AccessKey) (key AccessKey, err error) {
+	return e.UpdateKeyWithContext(context.Background(), name, keyname, keyupd)
+}
+
+// UpdateKeyWithContext is UpdateKey with a context for cancellation and deadlines.
+func (e *ApiClientService) UpdateKeyWithContext(ctx context.Context, name string, keyname string, keyupd AccessKey) (key AccessKey, err error) {
 	url := fmt.Sprintf("clients/%s/keys/%s", name, keyname)
 	body, err := JSONReader(keyupd)
-	err = e.client.magicRequestDecoder("PUT", url, body, &key)
+	err = e.client.magicRequestDecoderWithContext(ctx, "PUT", url, body, &key)
 	return
 }
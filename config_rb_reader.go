@@ -41,17 +41,24 @@ func NewClientRb(data, path string) (c ConfigRb, err error) {
 	return c, err
 }
 func configKeyParser(s []string, path string, c *ConfigRb) error {
-	str := StringParserForMeta(s)
-	data := strings.Split(str, "/")
-	size := len(data)
-	if size > 0 {
-		keyPath := filepath.Join(path, data[size-1])
-		keyData, err := os.ReadFile(keyPath)
-		if err != nil {
-			return err
-		}
-		c.ClientKey = string(keyData)
+	// the key may live anywhere, evaluate the expression rather than keeping its basename
+	eval := &rbEval{file: filepath.Join(path, "config.rb"), vars: map[string]string{"current_dir": path}}
+	keyPath, err := eval.eval(strings.Join(s, " "))
+	if err != nil {
+		return err
+	}
+	if keyPath == "" {
+		return nil
+	}
+	keyPath, err = expandPath(keyPath, path)
+	if err != nil {
+		return err
+	}
+	keyData, err := os.ReadFile(keyPath)
+	if err != nil {
+		return err
 	}
+	c.ClientKey = string(keyData)
 	return nil
 }
 func configServerParser(s []string, path string, c *ConfigRb) error {
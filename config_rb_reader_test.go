@@ -1,6 +1,8 @@
 package chef
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -29,3 +31,22 @@ func TestNewClientRb(t *testing.T) {
 	assert.Equal(t, "https://server/organizations/test", cb.ChefServerUrl)
 	assert.Equal(t, "test", cb.NodeName)
 }
+
+func TestConfigKeyParser(t *testing.T) {
+	configDir := t.TempDir()
+	keyDir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(keyDir, "test.pem"), []byte("KEY"), 0600))
+	assert.Nil(t, os.WriteFile(filepath.Join(configDir, "local.pem"), []byte("LOCAL"), 0600))
+
+	var c ConfigRb
+	err := configKeyParser([]string{`"` + keyDir + `/test.pem"`}, configDir, &c)
+	assert.Nil(t, err, "absolute key paths outside of the config dir")
+	assert.Equal(t, "KEY", c.ClientKey)
+
+	err = configKeyParser([]string{`"#{current_dir}/local.pem"`}, configDir, &c)
+	assert.Nil(t, err)
+	assert.Equal(t, "LOCAL", c.ClientKey)
+
+	err = configKeyParser([]string{`File.join(current_dir,`, `"missing.pem")`}, configDir, &c)
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,9 @@
 package chef
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type ContainerService struct {
 	client *Client
@@ -32,7 +35,12 @@ func (c ContainerListResult) String() (out string) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server/containers
 func (e *ContainerService) List() (data ContainerListResult, err error) {
-	err = e.client.magicRequestDecoder("GET", "containers", nil, &data)
+	return e.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (e *ContainerService) ListWithContext(ctx context.Context) (data ContainerListResult, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "containers", nil, &data)
 	return
 }
 
@@ -40,11 +48,16 @@ func (e *ContainerService) List() (data ContainerListResult, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#containers
 func (e *ContainerService) Create(container Container) (data *ContainerCreateResult, err error) {
+	return e.CreateWithContext(context.Background(), container)
+}
+
+// CreateWithContext is Create with a context for cancellation and deadlines.
+func (e *ContainerService) CreateWithContext(ctx context.Context, container Container) (data *ContainerCreateResult, err error) {
 	body, err := JSONReader(container)
 	if err != nil {
 		return
 	}
-	err = e.client.magicRequestDecoder("POST", "containers", body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "POST", "containers", body, &data)
 	return
 }
 
@@ -52,8 +65,13 @@ func (e *ContainerService) Create(container Container) (data *ContainerCreateRes
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#container
 func (e *ContainerService) Delete(name string) (err error) {
+	return e.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (e *ContainerService) DeleteWithContext(ctx context.Context, name string) (err error) {
 	url := fmt.Sprintf("containers/%s", name)
-	err = e.client.magicRequestDecoder("DELETE", url, nil, nil)
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", url, nil, nil)
 	return
 }
 
@@ -61,7 +79,12 @@ func (e *ContainerService) Delete(name string) (err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#containers
 func (e *ContainerService) Get(name string) (container Container, err error) {
+	return e.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *ContainerService) GetWithContext(ctx context.Context, name string) (container Container, err error) {
 	url := fmt.Sprintf("containers/%s", name)
-	err = e.client.magicRequestDecoder("GET", url, nil, &container)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &container)
 	return
 }
@@ -1,6 +1,7 @@
 package chef
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -104,10 +105,59 @@ type Cookbook struct {
 	Providers    []CookbookItem `json:"providers,omitempty"`
 	Resources    []CookbookItem `json:"resources,omitempty"`
 	RootFiles    []CookbookItem `json:"root_files,omitempty"`
+	AllFiles     []CookbookItem `json:"all_files,omitempty"` // Server API version 2 lists every file here in place of the segments above
 	Metadata     CookbookMeta   `json:"metadata,omitempty"`
 	Access       CookbookAccess `json:"access,omitempty"`
 }
 
+// cookbookSegments are the file lists of a cookbook before Server API version 2
+var cookbookSegments = []string{"root_files", "files", "templates", "attributes", "recipes", "definitions", "libraries", "providers", "resources"}
+
+// segments maps the segment names to the file lists of the cookbook
+func (c *Cookbook) segments() map[string]*[]CookbookItem {
+	return map[string]*[]CookbookItem{
+		"root_files":  &c.RootFiles,
+		"files":       &c.Files,
+		"templates":   &c.Templates,
+		"attributes":  &c.Attributes,
+		"recipes":     &c.Recipes,
+		"definitions": &c.Definitions,
+		"libraries":   &c.Libraries,
+		"providers":   &c.Providers,
+		"resources":   &c.Resources,
+	}
+}
+
+// fillCookbookFiles completes whichever of the two manifest shapes the server didn't send.
+// API version 2 names files by their path in all_files, for example "recipes/default.rb",
+// while earlier versions list "default.rb" in the recipes segment.
+func fillCookbookFiles(allFiles *[]CookbookItem, segments map[string]*[]CookbookItem) {
+	if len(*allFiles) > 0 {
+		for _, seg := range segments {
+			if len(*seg) > 0 {
+				return
+			}
+		}
+		for _, item := range *allFiles {
+			seg, name, found := strings.Cut(item.Name, "/")
+			list, ok := segments[seg]
+			if !found || !ok {
+				// files outside the known segments have no place in the older shape
+				continue
+			}
+			item.Name = name
+			*list = append(*list, item)
+		}
+		return
+	}
+	for _, seg := range cookbookSegments {
+		for _, item := range *segments[seg] {
+			item.Name = seg + "/" + item.Name
+			*allFiles = append(*allFiles, item)
+		}
+	}
+}
+
 // String makes CookbookListResult implement the string result
 func (c CookbookListResult) String() (out string) {
 	for k, v := range c {
@@ -137,15 +187,25 @@ func versionParams(path, numVersions string) string {
 //
 //	GET /cookbooks/name
 func (c *CookbookService) Get(name string) (data CookbookVersion, err error) {
+	return c.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (c *CookbookService) GetWithContext(ctx context.Context, name string) (data CookbookVersion, err error) {
 	path := fmt.Sprintf("cookbooks/%s", name)
-	err = c.client.magicRequestDecoder("GET", path, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
 // GetAvailable returns the versions of a coookbook available on a server
 func (c *CookbookService) GetAvailableVersions(name, numVersions string) (data CookbookListResult, err error) {
+	return c.GetAvailableVersionsWithContext(context.Background(), name, numVersions)
+}
+
+// GetAvailableVersionsWithContext is GetAvailableVersions with a context for cancellation and deadlines.
+func (c *CookbookService) GetAvailableVersionsWithContext(ctx context.Context, name, numVersions string) (data CookbookListResult, err error) {
 	path := versionParams(fmt.Sprintf("cookbooks/%s", name), numVersions)
-	err = c.client.magicRequestDecoder("GET", path, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -155,8 +215,14 @@ func (c *CookbookService) GetAvailableVersions(name, numVersions string) (data C
 //	GET /cookbook/foo/_latest
 //	Chef API docs: https://docs.chef.io/api_chef_server.html#cookbooks-name-version
 func (c *CookbookService) GetVersion(name, version string) (data Cookbook, err error) {
+	return c.GetVersionWithContext(context.Background(), name, version)
+}
+
+// GetVersionWithContext is GetVersion with a context for cancellation and deadlines.
+func (c *CookbookService) GetVersionWithContext(ctx context.Context, name, version string) (data Cookbook, err error) {
 	url := fmt.Sprintf("cookbooks/%s/%s", name, version)
-	err = c.client.magicRequestDecoder("GET", url, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &data)
+	fillCookbookFiles(&data.AllFiles, data.segments())
 	return
 }
 
@@ -164,8 +230,13 @@ func (c *CookbookService) GetVersion(name, version string) (data Cookbook, err e
 //
 //	Chef API docs: https://docs.chef.io/api_chef_server.html#cookbooks-name
 func (c *CookbookService) ListAvailableVersions(numVersions string) (data CookbookListResult, err error) {
+	return c.ListAvailableVersionsWithContext(context.Background(), numVersions)
+}
+
+// ListAvailableVersionsWithContext is ListAvailableVersions with a context for cancellation and deadlines.
+func (c *CookbookService) ListAvailableVersionsWithContext(ctx context.Context, numVersions string) (data CookbookListResult, err error) {
 	path := versionParams("cookbooks", numVersions)
-	err = c.client.magicRequestDecoder("GET", path, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -173,20 +244,35 @@ func (c *CookbookService) ListAvailableVersions(numVersions string) (data Cookbo
 //
 //	Chef API docs: https://docs.chef.io/api_chef_server.html#cookbooks-recipes
 func (c *CookbookService) ListAllRecipes() (data CookbookRecipesResult, err error) {
+	return c.ListAllRecipesWithContext(context.Background())
+}
+
+// ListAllRecipesWithContext is ListAllRecipes with a context for cancellation and deadlines.
+func (c *CookbookService) ListAllRecipesWithContext(ctx context.Context) (data CookbookRecipesResult, err error) {
 	path := "cookbooks/_recipes"
-	err = c.client.magicRequestDecoder("GET", path, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
 // List returns a CookbookListResult with the latest versions of cookbooks available on the server
 func (c *CookbookService) List() (CookbookListResult, error) {
-	return c.ListAvailableVersions("")
+	return c.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (c *CookbookService) ListWithContext(ctx context.Context) (CookbookListResult, error) {
+	return c.ListAvailableVersionsWithContext(ctx, "")
 }
 
 // DeleteVersion removes a version of a cook from a server
 func (c *CookbookService) Delete(name, version string) (err error) {
+	return c.DeleteWithContext(context.Background(), name, version)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (c *CookbookService) DeleteWithContext(ctx context.Context, name, version string) (err error) {
 	path := fmt.Sprintf("cookbooks/%s/%s", name, version)
-	err = c.client.magicRequestDecoder("DELETE", path, nil, nil)
+	err = c.client.magicRequestDecoderWithContext(ctx, "DELETE", path, nil, nil)
 	return
 }
 func ReadMetaData(path string) (m CookbookMeta, err error) {
@@ -1,6 +1,9 @@
 package chef
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // CBAService  is the service for interacting with chef server cookbook_artifacts endpoint
 type CBAService struct {
@@ -32,11 +35,27 @@ type CBADetail struct {
 	Definitions []CookbookItem `json:"definitions,omitempty"`
 	Files       []CookbookItem `json:"files,omitempty"`
 	Templates   []CookbookItem `json:"templates,omitempty"`
+	AllFiles    []CookbookItem `json:"all_files,omitempty"` // Server API version 2 lists every file here in place of the segments above
 	Frozen      bool           `json:"frozen?,omitempty"`
 	ChefType    string         `json:"chef_type,omitempty"`
 	Metadata    CBAMeta        `json:"metadata,omitempty"`
 }
 
+// segments maps the segment names to the file lists of the cookbook artifact
+func (c *CBADetail) segments() map[string]*[]CookbookItem {
+	return map[string]*[]CookbookItem{
+		"root_files":  &c.RootFiles,
+		"files":       &c.Files,
+		"templates":   &c.Templates,
+		"attributes":  &c.Attributes,
+		"recipes":     &c.Recipes,
+		"definitions": &c.Definitions,
+		"libraries":   &c.Libraries,
+		"providers":   &c.Providers,
+		"resources":   &c.Resources,
+	}
+}
+
 // CBAMeta represents the cookbook_artifacts metadata information
 type CBAMeta struct {
 	Name            string                 `json:"name,omitempty"`
@@ -68,7 +87,12 @@ type CBAMeta struct {
 //
 //	GET /cookbook_artifacts
 func (c *CBAService) List() (data CBAGetResponse, err error) {
-	err = c.client.magicRequestDecoder("GET", "cookbook_artifacts", nil, &data)
+	return c.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (c *CBAService) ListWithContext(ctx context.Context) (data CBAGetResponse, err error) {
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", "cookbook_artifacts", nil, &data)
 	return
 }
 
@@ -76,8 +100,13 @@ func (c *CBAService) List() (data CBAGetResponse, err error) {
 //
 //	GET /cookbook_artifacts/name
 func (c *CBAService) Get(name string) (data CBAGetResponse, err error) {
+	return c.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (c *CBAService) GetWithContext(ctx context.Context, name string) (data CBAGetResponse, err error) {
 	path := fmt.Sprintf("cookbook_artifacts/%s", name)
-	err = c.client.magicRequestDecoder("GET", path, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -85,7 +114,13 @@ func (c *CBAService) Get(name string) (data CBAGetResponse, err error) {
 //
 //	GET /cookbook_artifacts/foo/1ef062de1bc4cb14e4a78fb739e104eb9508473e
 func (c *CBAService) GetVersion(name, id string) (data CBADetail, err error) {
+	return c.GetVersionWithContext(context.Background(), name, id)
+}
+
+// GetVersionWithContext is GetVersion with a context for cancellation and deadlines.
+func (c *CBAService) GetVersionWithContext(ctx context.Context, name, id string) (data CBADetail, err error) {
 	url := fmt.Sprintf("cookbook_artifacts/%s/%s", name, id)
-	err = c.client.magicRequestDecoder("GET", url, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &data)
+	fillCookbookFiles(&data.AllFiles, data.segments())
 	return
 }
@@ -1,34 +1,54 @@
 package chef
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"path"
 )
 
 // DownloadTo downloads a cookbook artifact to the specified local directory on disk
 func (c *CBAService) DownloadTo(name, id, localDir string) error {
+	return c.DownloadToWithContext(context.Background(), name, id, localDir)
+}
 
-	cba, err := c.GetVersion(name, id)
+// DownloadToWithContext is DownloadTo with a context for cancellation and deadlines.
+// The context is checked before each cookbook artifact file is fetched.
+func (c *CBAService) DownloadToWithContext(ctx context.Context, name, id, localDir string) error {
+	cba, err := c.GetVersionWithContext(ctx, name, id)
 	if err != nil {
 		return err
 	}
 
-	debug("Downloading %s cookbook artifact with id %s\n", cba.Name, cba.Identifier)
+	c.client.log(ctx, slog.LevelDebug, "downloading cookbook artifact", slog.String("cookbook", cba.Name), slog.String("identifier", cba.Identifier))
 
+	// the directory is named after the first 20 characters of the identifier, as knife does
+	identifier := cba.Identifier
+	if identifier == "" {
+		return fmt.Errorf("cookbook artifact %s has no identifier", name)
+	}
+	if len(identifier) > 20 {
+		identifier = identifier[:20]
+	}
 	cookbookService := CookbookService{client: c.client}
-	cookbookLongName := fmt.Sprintf("%v-%v", name, cba.Identifier[0:20])
-	cookbookPath := path.Join(localDir, cookbookLongName)
+	cookbookPath, err := localFilePath(localDir, fmt.Sprintf("%v-%v", name, identifier))
+	if err != nil {
+		return err
+	}
+	if err := checkItemNames(cba.segments(), cookbookPath); err != nil {
+		return err
+	}
 
 	downloadErrs := []error{
-		cookbookService.downloadCookbookItems(cba.RootFiles, "root_files", cookbookPath),
-		cookbookService.downloadCookbookItems(cba.Files, "files", path.Join(cookbookPath, "files")),
-		cookbookService.downloadCookbookItems(cba.Templates, "templates", path.Join(cookbookPath, "templates")),
-		cookbookService.downloadCookbookItems(cba.Attributes, "attributes", path.Join(cookbookPath, "attributes")),
-		cookbookService.downloadCookbookItems(cba.Recipes, "recipes", path.Join(cookbookPath, "recipes")),
-		cookbookService.downloadCookbookItems(cba.Definitions, "definitions", path.Join(cookbookPath, "definitions")),
-		cookbookService.downloadCookbookItems(cba.Libraries, "libraries", path.Join(cookbookPath, "libraries")),
-		cookbookService.downloadCookbookItems(cba.Providers, "providers", path.Join(cookbookPath, "providers")),
-		cookbookService.downloadCookbookItems(cba.Resources, "resources", path.Join(cookbookPath, "resources")),
+		cookbookService.downloadCookbookItems(ctx, cba.RootFiles, "root_files", cookbookPath),
+		cookbookService.downloadCookbookItems(ctx, cba.Files, "files", path.Join(cookbookPath, "files")),
+		cookbookService.downloadCookbookItems(ctx, cba.Templates, "templates", path.Join(cookbookPath, "templates")),
+		cookbookService.downloadCookbookItems(ctx, cba.Attributes, "attributes", path.Join(cookbookPath, "attributes")),
+		cookbookService.downloadCookbookItems(ctx, cba.Recipes, "recipes", path.Join(cookbookPath, "recipes")),
+		cookbookService.downloadCookbookItems(ctx, cba.Definitions, "definitions", path.Join(cookbookPath, "definitions")),
+		cookbookService.downloadCookbookItems(ctx, cba.Libraries, "libraries", path.Join(cookbookPath, "libraries")),
+		cookbookService.downloadCookbookItems(ctx, cba.Providers, "providers", path.Join(cookbookPath, "providers")),
+		cookbookService.downloadCookbookItems(ctx, cba.Resources, "resources", path.Join(cookbookPath, "resources")),
 	}
 
 	for _, err := range downloadErrs {
@@ -37,6 +57,6 @@ func (c *CBAService) DownloadTo(name, id, localDir string) error {
 		}
 	}
 
-	debug("Cookbook artifact downloaded to %s\n", cookbookPath)
+	c.client.log(ctx, slog.LevelDebug, "cookbook artifact downloaded", slog.String("path", cookbookPath))
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -69,6 +70,40 @@ func TestCBADownloadTo(t *testing.T) {
 
 }
 
+func TestCBADownloadTo_badManifest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tempDir := t.TempDir()
+	fetched := 0
+	mux.HandleFunc("/bookshelf/", func(w http.ResponseWriter, r *http.Request) {
+		fetched++
+		fmt.Fprint(w, map[string]string{"metadata_rb": "name 'foo'", "default_rb": "log 'this is a resource'"}[path.Base(r.URL.Path)])
+	})
+	manifests := map[string]string{
+		"escape": strings.Replace(cbaData(), `"name": "default.rb"`, `"name": "../../../escaped.rb"`, 1),
+		"short":  strings.Replace(cbaData(), `"identifier": "0e1fed3b56aa5e84205e330d92aca22d8704a014"`, `"identifier": ""`, 1),
+	}
+	for id, manifest := range manifests {
+		mux.HandleFunc("/cookbook_artifacts/seven_zip/"+id, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, manifest)
+		})
+		err := client.CookbookArtifacts.DownloadTo("seven_zip", id, tempDir)
+		assert.NotNil(t, err, id)
+	}
+	assert.Equal(t, 0, fetched, "no file fetched before the names are checked")
+	entries, _ := os.ReadDir(tempDir)
+	assert.Empty(t, entries, "nothing written")
+
+	// a short identifier is used whole
+	mux.HandleFunc("/cookbook_artifacts/seven_zip/abc", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Replace(cbaData(), `"identifier": "0e1fed3b56aa5e84205e330d92aca22d8704a014"`, `"identifier": "abc"`, 1))
+	})
+	err := client.CookbookArtifacts.DownloadTo("seven_zip", "abc", tempDir)
+	assert.Nil(t, err)
+	assert.DirExists(t, path.Join(tempDir, "seven_zip-abc"))
+}
+
 func cbaData() string {
 	return `
 
@@ -5,51 +5,71 @@
 package chef
 
 import (
+	"context"
 	"crypto/md5"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path"
+	"path/filepath"
 )
 
 // Download downloads a cookbook to the current directory on disk
 func (c *CookbookService) Download(name, version string) error {
+	return c.DownloadWithContext(context.Background(), name, version)
+}
+
+// DownloadWithContext is Download with a context for cancellation and deadlines.
+func (c *CookbookService) DownloadWithContext(ctx context.Context, name, version string) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return err
 	}
 
-	return c.DownloadTo(name, version, cwd)
+	return c.DownloadToWithContext(ctx, name, version, cwd)
 }
 
 // DownloadTo downloads a cookbook to the specified local directory on disk
 func (c *CookbookService) DownloadTo(name, version, localDir string) error {
+	return c.DownloadToWithContext(context.Background(), name, version, localDir)
+}
+
+// DownloadToWithContext is DownloadTo with a context for cancellation and deadlines.
+// The context is checked before each cookbook file is fetched.
+func (c *CookbookService) DownloadToWithContext(ctx context.Context, name, version, localDir string) error {
 	// If the version is set to 'latest' or it is empty ("") then,
 	// we will set the version to '_latest' which is the default endpoint
 	if version == "" || version == "latest" {
 		version = "_latest"
 	}
 
-	cookbook, err := c.GetVersion(name, version)
+	cookbook, err := c.GetVersionWithContext(ctx, name, version)
 	if err != nil {
 		return err
 	}
 
-	debug("Downloading %s cookbook version %s\n", cookbook.CookbookName, cookbook.Version)
+	c.client.log(ctx, slog.LevelDebug, "downloading cookbook", slog.String("cookbook", cookbook.CookbookName), slog.String("version", cookbook.Version))
 
 	// We use 'cookbook.Name' since it returns the string '{NAME}-{VERSION}'. Ex: 'apache-0.1.0'
-	cookbookPath := path.Join(localDir, cookbook.Name)
+	cookbookPath, err := localFilePath(localDir, cookbook.Name)
+	if err != nil {
+		return err
+	}
+	if err := checkItemNames(cookbook.segments(), cookbookPath); err != nil {
+		return err
+	}
 
 	downloadErrs := []error{
-		c.downloadCookbookItems(cookbook.RootFiles, "root_files", cookbookPath),
-		c.downloadCookbookItems(cookbook.Files, "files", path.Join(cookbookPath, "files")),
-		c.downloadCookbookItems(cookbook.Templates, "templates", path.Join(cookbookPath, "templates")),
-		c.downloadCookbookItems(cookbook.Attributes, "attributes", path.Join(cookbookPath, "attributes")),
-		c.downloadCookbookItems(cookbook.Recipes, "recipes", path.Join(cookbookPath, "recipes")),
-		c.downloadCookbookItems(cookbook.Definitions, "definitions", path.Join(cookbookPath, "definitions")),
-		c.downloadCookbookItems(cookbook.Libraries, "libraries", path.Join(cookbookPath, "libraries")),
-		c.downloadCookbookItems(cookbook.Providers, "providers", path.Join(cookbookPath, "providers")),
-		c.downloadCookbookItems(cookbook.Resources, "resources", path.Join(cookbookPath, "resources")),
+		c.downloadCookbookItems(ctx, cookbook.RootFiles, "root_files", cookbookPath),
+		c.downloadCookbookItems(ctx, cookbook.Files, "files", path.Join(cookbookPath, "files")),
+		c.downloadCookbookItems(ctx, cookbook.Templates, "templates", path.Join(cookbookPath, "templates")),
+		c.downloadCookbookItems(ctx, cookbook.Attributes, "attributes", path.Join(cookbookPath, "attributes")),
+		c.downloadCookbookItems(ctx, cookbook.Recipes, "recipes", path.Join(cookbookPath, "recipes")),
+		c.downloadCookbookItems(ctx, cookbook.Definitions, "definitions", path.Join(cookbookPath, "definitions")),
+		c.downloadCookbookItems(ctx, cookbook.Libraries, "libraries", path.Join(cookbookPath, "libraries")),
+		c.downloadCookbookItems(ctx, cookbook.Providers, "providers", path.Join(cookbookPath, "providers")),
+		c.downloadCookbookItems(ctx, cookbook.Resources, "resources", path.Join(cookbookPath, "resources")),
 	}
 
 	for _, err := range downloadErrs {
@@ -58,7 +78,7 @@ func (c *CookbookService) DownloadTo(name, version, localDir string) error {
 		}
 	}
 
-	debug("Cookbook downloaded to %s\n", cookbookPath)
+	c.client.log(ctx, slog.LevelDebug, "cookbook downloaded", slog.String("path", cookbookPath))
 	return nil
 }
 
@@ -70,28 +90,56 @@ func (c *CookbookService) DownloadAt(name, version, localDir string) error {
 
 // downloadCookbookItems downloads all the provided cookbook items into the provided
 // local path, it also ensures that the provided directory exists by creating it
-func (c *CookbookService) downloadCookbookItems(items []CookbookItem, itemType, localPath string) error {
+func (c *CookbookService) downloadCookbookItems(ctx context.Context, items []CookbookItem, itemType, localPath string) error {
 	if len(items) == 0 {
 		return nil
 	}
 
-	debug("Downloading %s\n", itemType)
+	c.client.log(ctx, slog.LevelDebug, "downloading cookbook items", slog.String("type", itemType), slog.Int("count", len(items)))
 	if err := os.MkdirAll(localPath, 0755); err != nil {
 		return err
 	}
 
 	for _, item := range items {
-		if err := c.downloadCookbookFile(item, localPath); err != nil {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
+		if err := c.downloadCookbookFile(ctx, item, localPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// localFilePath joins dir and a file name from a cookbook manifest, rejecting names
+// that are absolute or would leave dir, as "../../etc/x" does
+func localFilePath(dir, name string) (string, error) {
+	local := filepath.FromSlash(name)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("cookbook file name %q is not a local path", name)
 	}
+	return filepath.Join(dir, local), nil
+}
 
+// checkItemNames checks the names of every file in segments before any is written
+func checkItemNames(segments map[string]*[]CookbookItem, cookbookPath string) error {
+	for _, items := range segments {
+		for _, item := range *items {
+			if _, err := localFilePath(cookbookPath, item.Name); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
 // downloadCookbookFile downloads a single cookbook file to disk
-func (c *CookbookService) downloadCookbookFile(item CookbookItem, localPath string) error {
-	filePath := path.Join(localPath, item.Name)
+func (c *CookbookService) downloadCookbookFile(ctx context.Context, item CookbookItem, localPath string) error {
+	filePath, err := localFilePath(localPath, item.Name)
+	if err != nil {
+		return err
+	}
 
 	// First check and see if the file is already there - if it is and the checksum
 	// matches, there's no need to redownload it.
@@ -99,7 +147,7 @@ func (c *CookbookService) downloadCookbookFile(item CookbookItem, localPath stri
 		return nil
 	}
 
-	request, err := c.client.NewRequest("GET", item.Url, nil)
+	request, err := c.client.NewRequestWithContext(withRequestClass(ctx, classDownload), "GET", item.Url, nil)
 	if err != nil {
 		return err
 	}
@@ -112,6 +160,10 @@ func (c *CookbookService) downloadCookbookFile(item CookbookItem, localPath stri
 		return err
 	}
 
+	// names from an API version 2 manifest may include subdirectories
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
 	f, err := os.Create(filePath)
 	if err != nil {
 		return err
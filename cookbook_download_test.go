@@ -5,10 +5,12 @@
 package chef
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -156,6 +158,68 @@ func TestCookbooksDownloadTo(t *testing.T) {
 
 }
 
+func TestCookbooksDownloadTo_pathTraversal(t *testing.T) {
+	setup()
+	defer teardown()
+
+	tempDir := t.TempDir()
+	localDir := path.Join(tempDir, "cookbooks")
+	fetched := 0
+	mux.HandleFunc("/bookshelf/", func(w http.ResponseWriter, r *http.Request) {
+		fetched++
+		fmt.Fprintf(w, "owned")
+	})
+
+	for _, name := range []string{"../../escaped.rb", "/tmp/escaped.rb", "recipes/../../../escaped.rb"} {
+		manifest := strings.Replace(cookbookData(), `"name": "metadata.rb"`, fmt.Sprintf(`"name": %q`, name), 1)
+		mux.HandleFunc("/cookbooks/foo/"+fmt.Sprint(len(name)), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, manifest)
+		})
+
+		err := client.Cookbooks.DownloadTo("foo", fmt.Sprint(len(name)), localDir)
+		assert.ErrorContains(t, err, "is not a local path", name)
+	}
+	assert.NoFileExists(t, path.Join(tempDir, "escaped.rb"))
+	assert.Equal(t, 0, fetched, "no file fetched for a name outside the cookbook")
+
+	// the cookbook name from the manifest is checked too
+	mux.HandleFunc("/cookbooks/foo/9.9.9", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Replace(cookbookData(), `"name": "foo-0.2.1"`, `"name": "../foo-0.2.1"`, 1))
+	})
+	err := client.Cookbooks.DownloadTo("foo", "9.9.9", localDir)
+	assert.ErrorContains(t, err, "is not a local path")
+	assert.NoDirExists(t, path.Join(tempDir, "foo-0.2.1"))
+}
+
+func TestCookbooksDownloadToWithContextCancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mockedCookbookResponseFile := cookbookData()
+	tempDir, err := os.MkdirTemp("", "foo-cookbook")
+	if err != nil {
+		t.Error(err)
+	}
+	defer os.RemoveAll(tempDir) // clean up
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mux.HandleFunc("/cookbooks/foo/0.2.1", func(w http.ResponseWriter, r *http.Request) {
+		// the caller goes away once the manifest has been served
+		cancel()
+		fmt.Fprintf(w, string(mockedCookbookResponseFile))
+	})
+	fetched := 0
+	mux.HandleFunc("/bookshelf/", func(w http.ResponseWriter, r *http.Request) {
+		fetched++
+	})
+
+	err = client.Cookbooks.DownloadToWithContext(ctx, "foo", "0.2.1", tempDir)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, fetched, "no cookbook files fetched after cancel")
+}
+
 func TestCookbooksDownloadTo_caching(t *testing.T) {
 	setup()
 	defer teardown()
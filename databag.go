@@ -1,6 +1,9 @@
 package chef
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // DataBagService is the service for interacting with the chef server data endpoint
 type DataBagService struct {
@@ -37,8 +40,13 @@ func (d DataBagListResult) String() (out string) {
 //
 //	Chef API Docs: https://docs.chef.io/api_chef_server/#get-19
 func (d *DataBagService) List() (data *DataBagListResult, err error) {
+	return d.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (d *DataBagService) ListWithContext(ctx context.Context) (data *DataBagListResult, err error) {
 	path := fmt.Sprintf("data")
-	err = d.client.magicRequestDecoder("GET", path, nil, &data)
+	err = d.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -46,12 +54,17 @@ func (d *DataBagService) List() (data *DataBagListResult, err error) {
 //
 //	Chef API Docs: https://docs.chef.io/api_chef_server/#post-7
 func (d *DataBagService) Create(databag *DataBag) (result *DataBagCreateResult, err error) {
+	return d.CreateWithContext(context.Background(), databag)
+}
+
+// CreateWithContext is Create with a context for cancellation and deadlines.
+func (d *DataBagService) CreateWithContext(ctx context.Context, databag *DataBag) (result *DataBagCreateResult, err error) {
 	body, err := JSONReader(databag)
 	if err != nil {
 		return
 	}
 
-	err = d.client.magicRequestDecoder("POST", "data", body, &result)
+	err = d.client.magicRequestDecoderWithContext(ctx, "POST", "data", body, &result)
 	return
 }
 
@@ -59,8 +72,13 @@ func (d *DataBagService) Create(databag *DataBag) (result *DataBagCreateResult,
 //
 //	Chef API Docs: https://docs.chef.io/api_chef_server/#delete-7
 func (d *DataBagService) Delete(name string) (result *DataBag, err error) {
+	return d.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (d *DataBagService) DeleteWithContext(ctx context.Context, name string) (result *DataBag, err error) {
 	path := fmt.Sprintf("data/%s", name)
-	err = d.client.magicRequestDecoder("DELETE", path, nil, &result)
+	err = d.client.magicRequestDecoderWithContext(ctx, "DELETE", path, nil, &result)
 	return
 }
 
@@ -68,8 +86,13 @@ func (d *DataBagService) Delete(name string) (result *DataBag, err error) {
 //
 //	Chef API Docs: https://docs.chef.io/api_chef_server/#get-20
 func (d *DataBagService) ListItems(name string) (data *DataBagListResult, err error) {
+	return d.ListItemsWithContext(context.Background(), name)
+}
+
+// ListItemsWithContext is ListItems with a context for cancellation and deadlines.
+func (d *DataBagService) ListItemsWithContext(ctx context.Context, name string) (data *DataBagListResult, err error) {
 	path := fmt.Sprintf("data/%s", name)
-	err = d.client.magicRequestDecoder("GET", path, nil, &data)
+	err = d.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -77,20 +100,30 @@ func (d *DataBagService) ListItems(name string) (data *DataBagListResult, err er
 //
 //	Chef API Docs: https://docs.chef.io/api_chef_server/#post-8
 func (d *DataBagService) CreateItem(databagName string, databagItem DataBagItem) (err error) {
+	return d.CreateItemWithContext(context.Background(), databagName, databagItem)
+}
+
+// CreateItemWithContext is CreateItem with a context for cancellation and deadlines.
+func (d *DataBagService) CreateItemWithContext(ctx context.Context, databagName string, databagItem DataBagItem) (err error) {
 	body, err := JSONReader(databagItem)
 	if err != nil {
 		return
 	}
 	path := fmt.Sprintf("data/%s", databagName)
-	return d.client.magicRequestDecoder("POST", path, body, nil)
+	return d.client.magicRequestDecoderWithContext(ctx, "POST", path, body, nil)
 }
 
 // DeleteItem deletes an item from a data bag
 //
 //	Chef API Docs: https://docs.chef.io/api_chef_server/#delete-8
 func (d *DataBagService) DeleteItem(databagName string, databagItem string) (err error) {
+	return d.DeleteItemWithContext(context.Background(), databagName, databagItem)
+}
+
+// DeleteItemWithContext is DeleteItem with a context for cancellation and deadlines.
+func (d *DataBagService) DeleteItemWithContext(ctx context.Context, databagName string, databagItem string) (err error) {
 	path := fmt.Sprintf("data/%s/%s", databagName, databagItem)
-	err = d.client.magicRequestDecoder("DELETE", path, nil, nil)
+	err = d.client.magicRequestDecoderWithContext(ctx, "DELETE", path, nil, nil)
 	return
 }
 
@@ -98,8 +131,13 @@ func (d *DataBagService) DeleteItem(databagName string, databagItem string) (err
 //
 //	Chef API Docs: https://docs.chef.io/api_chef_server/#get-21
 func (d *DataBagService) GetItem(databagName string, databagItem string) (item DataBagItem, err error) {
+	return d.GetItemWithContext(context.Background(), databagName, databagItem)
+}
+
+// GetItemWithContext is GetItem with a context for cancellation and deadlines.
+func (d *DataBagService) GetItemWithContext(ctx context.Context, databagName string, databagItem string) (item DataBagItem, err error) {
 	path := fmt.Sprintf("data/%s/%s", databagName, databagItem)
-	err = d.client.magicRequestDecoder("GET", path, nil, &item)
+	err = d.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &item)
 	return
 }
 
@@ -107,10 +145,15 @@ func (d *DataBagService) GetItem(databagName string, databagItem string) (item D
 //
 //	Chef API Docs: https://docs.chef.io/api_chef_server/#put-6
 func (d *DataBagService) UpdateItem(databagName string, databagItemId string, databagItem DataBagItem) (err error) {
+	return d.UpdateItemWithContext(context.Background(), databagName, databagItemId, databagItem)
+}
+
+// UpdateItemWithContext is UpdateItem with a context for cancellation and deadlines.
+func (d *DataBagService) UpdateItemWithContext(ctx context.Context, databagName string, databagItemId string, databagItem DataBagItem) (err error) {
 	body, err := JSONReader(databagItem)
 	if err != nil {
 		return
 	}
 	path := fmt.Sprintf("data/%s/%s", databagName, databagItemId)
-	return d.client.magicRequestDecoder("PUT", path, body, nil)
+	return d.client.magicRequestDecoderWithContext(ctx, "PUT", path, body, nil)
 }
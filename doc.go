@@ -96,3 +96,5 @@ This is an example code generating a new node on a Chef Infra Server:
 			}
 */
 package chef
+
+//go:generate go run ./internal/genapi
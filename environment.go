@@ -1,6 +1,7 @@
 package chef
 
 import (
+	"context"
 	"fmt"
 	"sort"
 )
@@ -52,7 +53,12 @@ func (e EnvironmentResult) String() (out string) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#environments
 func (e *EnvironmentService) List() (data *EnvironmentResult, err error) {
-	err = e.client.magicRequestDecoder("GET", "environments", nil, &data)
+	return e.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (e *EnvironmentService) ListWithContext(ctx context.Context) (data *EnvironmentResult, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "environments", nil, &data)
 	return
 }
 
@@ -60,12 +66,17 @@ func (e *EnvironmentService) List() (data *EnvironmentResult, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#environments
 func (e *EnvironmentService) Create(environment *Environment) (data *EnvironmentResult, err error) {
+	return e.CreateWithContext(context.Background(), environment)
+}
+
+// CreateWithContext is Create with a context for cancellation and deadlines.
+func (e *EnvironmentService) CreateWithContext(ctx context.Context, environment *Environment) (data *EnvironmentResult, err error) {
 	body, err := JSONReader(environment)
 	if err != nil {
 		return
 	}
 
-	err = e.client.magicRequestDecoder("POST", "environments", body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "POST", "environments", body, &data)
 	return
 }
 
@@ -73,8 +84,13 @@ func (e *EnvironmentService) Create(environment *Environment) (data *Environment
 //
 // Chef API docs: https://docs.chef.io/api_chef_server/#delete-9
 func (e *EnvironmentService) Delete(name string) (data *Environment, err error) {
+	return e.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (e *EnvironmentService) DeleteWithContext(ctx context.Context, name string) (data *Environment, err error) {
 	path := fmt.Sprintf("environments/%s", name)
-	err = e.client.magicRequestDecoder("DELETE", path, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", path, nil, &data)
 	return
 }
 
@@ -82,8 +98,13 @@ func (e *EnvironmentService) Delete(name string) (data *Environment, err error)
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#environments-name
 func (e *EnvironmentService) Get(name string) (data *Environment, err error) {
+	return e.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *EnvironmentService) GetWithContext(ctx context.Context, name string) (data *Environment, err error) {
 	path := fmt.Sprintf("environments/%s", name)
-	err = e.client.magicRequestDecoder("GET", path, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -92,13 +113,18 @@ func (e *EnvironmentService) Get(name string) (data *Environment, err error) {
 // Chef API docs: https://docs.chef.io/api_chef_server.html#environments-name
 // TODO: Fix the name restriction. The parms should be name, environment
 func (e *EnvironmentService) Put(environment *Environment) (data *Environment, err error) {
+	return e.PutWithContext(context.Background(), environment)
+}
+
+// PutWithContext is Put with a context for cancellation and deadlines.
+func (e *EnvironmentService) PutWithContext(ctx context.Context, environment *Environment) (data *Environment, err error) {
 	path := fmt.Sprintf("environments/%s", environment.Name)
 	body, err := JSONReader(environment)
 	if err != nil {
 		return
 	}
 
-	err = e.client.magicRequestDecoder("PUT", path, body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "PUT", path, body, &data)
 	return
 }
 
@@ -106,8 +132,13 @@ func (e *EnvironmentService) Put(environment *Environment) (data *Environment, e
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#environments-name-cookbooks
 func (e *EnvironmentService) ListCookbooks(name string, numVersions string) (data EnvironmentCookbookResult, err error) {
+	return e.ListCookbooksWithContext(context.Background(), name, numVersions)
+}
+
+// ListCookbooksWithContext is ListCookbooks with a context for cancellation and deadlines.
+func (e *EnvironmentService) ListCookbooksWithContext(ctx context.Context, name string, numVersions string) (data EnvironmentCookbookResult, err error) {
 	path := versionParams(fmt.Sprintf("environments/%s/cookbooks", name), numVersions)
-	err = e.client.magicRequestDecoder("GET", path, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -115,8 +146,13 @@ func (e *EnvironmentService) ListCookbooks(name string, numVersions string) (dat
 //
 // Chef API docs: https://docs.chef.io/api_chef_server/#get-33
 func (e *EnvironmentService) ListRecipes(name string) (data EnvironmentRecipesResult, err error) {
+	return e.ListRecipesWithContext(context.Background(), name)
+}
+
+// ListRecipesWithContext is ListRecipes with a context for cancellation and deadlines.
+func (e *EnvironmentService) ListRecipesWithContext(ctx context.Context, name string) (data EnvironmentRecipesResult, err error) {
 	path := fmt.Sprintf("environments/%s/recipes", name)
-	err = e.client.magicRequestDecoder("GET", path, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -0,0 +1,82 @@
+package chef
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matched by errors.Is against an *ErrorResponse returned from the Chef server.
+//
+//	_, err := client.Nodes.Get("missing")
+//	if errors.Is(err, chef.ErrNotFound) {
+//		// create the node
+//	}
+var (
+	ErrBadRequest         = errors.New("chef: bad request")
+	ErrUnauthorized       = errors.New("chef: unauthorized")
+	ErrForbidden          = errors.New("chef: forbidden")
+	ErrNotFound           = errors.New("chef: not found")
+	ErrNotAcceptable      = errors.New("chef: not acceptable")
+	ErrConflict           = errors.New("chef: conflict")
+	ErrPreconditionFailed = errors.New("chef: precondition failed")
+	ErrServerUnavailable  = errors.New("chef: server unavailable")
+)
+
+// Is reports whether the response status code corresponds to the target sentinel error
+func (r *ErrorResponse) Is(target error) bool {
+	if r.Response == nil {
+		return false
+	}
+	switch r.Response.StatusCode {
+	case http.StatusBadRequest:
+		return target == ErrBadRequest
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusNotAcceptable:
+		return target == ErrNotAcceptable
+	case http.StatusConflict:
+		return target == ErrConflict
+	case http.StatusPreconditionFailed:
+		return target == ErrPreconditionFailed
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return target == ErrServerUnavailable
+	}
+	return false
+}
+
+// RequestID returns the request id the Chef server assigned to the failed request.
+// Quote it when looking for the request in the server logs.
+func (r *ErrorResponse) RequestID() string {
+	if r.Response == nil {
+		return ""
+	}
+	if id := r.Response.Header.Get("X-Request-Id"); id != "" {
+		return id
+	}
+	return r.Response.Header.Get("X-Ops-Request-Id")
+}
+
+// OpsHeaders returns the X-Ops-* diagnostic headers of the failed response,
+// for example X-Ops-Server-API-Version and X-Ops-API-Info
+func (r *ErrorResponse) OpsHeaders() http.Header {
+	headers := http.Header{}
+	if r.Response == nil {
+		return headers
+	}
+	for key, vals := range r.Response.Header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(key), "X-Ops-") {
+			headers[key] = append([]string(nil), vals...)
+		}
+	}
+	return headers
+}
+
+// StatusMsgs returns every error message extracted from the response body
+func (r *ErrorResponse) StatusMsgs() []string {
+	return r.ErrorMsgs
+}
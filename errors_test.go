@@ -0,0 +1,77 @@
+package chef
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorResponse_Is(t *testing.T) {
+	cases := map[int]error{
+		http.StatusBadRequest:         ErrBadRequest,
+		http.StatusUnauthorized:       ErrUnauthorized,
+		http.StatusForbidden:          ErrForbidden,
+		http.StatusNotFound:           ErrNotFound,
+		http.StatusConflict:           ErrConflict,
+		http.StatusPreconditionFailed: ErrPreconditionFailed,
+		http.StatusServiceUnavailable: ErrServerUnavailable,
+		http.StatusGatewayTimeout:     ErrServerUnavailable,
+	}
+	for code, sentinel := range cases {
+		err := error(&ErrorResponse{Response: &http.Response{StatusCode: code}})
+		assert.True(t, errors.Is(err, sentinel), "status %d is %v", code, sentinel)
+		assert.True(t, errors.Is(fmt.Errorf("wrapped: %w", err), sentinel), "wrapped status %d is %v", code, sentinel)
+		for _, other := range cases {
+			if other != sentinel {
+				assert.False(t, errors.Is(err, other), "status %d is not %v", code, other)
+			}
+		}
+	}
+	assert.False(t, errors.Is(&ErrorResponse{Response: &http.Response{StatusCode: 500}}, ErrNotFound))
+}
+
+func TestErrorResponse_Diagnostics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "g3IAA2QAEGVyY2hlZkAxMjcuMC4wLjE")
+		w.Header().Set("X-Ops-Server-API-Version", `{"min_version":"0","max_version":"2","request_version":"1","response_version":"1"}`)
+		w.Header().Set("X-Ops-API-Info", "flavor=cs;version=12.0.0;erchef=12.0.0")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":["Field 'name' missing", "Field 'email' invalid"]}`)
+	}))
+	defer ts.Close()
+
+	resp, _ := http.Get(ts.URL)
+	cerr, _ := ChefError(CheckResponse(resp))
+	if !assert.NotNil(t, cerr) {
+		return
+	}
+	assert.Equal(t, "g3IAA2QAEGVyY2hlZkAxMjcuMC4wLjE", cerr.RequestID())
+	assert.Equal(t, []string{"Field 'name' missing", "Field 'email' invalid"}, cerr.StatusMsgs())
+	assert.Equal(t, "Field 'name' missing\nField 'email' invalid", cerr.StatusMsg())
+	ops := cerr.OpsHeaders()
+	assert.Len(t, ops, 2)
+	assert.Equal(t, "flavor=cs;version=12.0.0;erchef=12.0.0", ops.Get("X-Ops-API-Info"))
+}
+
+func TestExtractErrorMsgs(t *testing.T) {
+	assert.Equal(t, []string{"one", "two"}, extractErrorMsgs([]byte(`{"error":["one","two"]}`)))
+	assert.Equal(t, []string{"single"}, extractErrorMsgs([]byte(`{"error":"single"}`)))
+	assert.Nil(t, extractErrorMsgs([]byte(`not json`)))
+}
+
+func TestChefError_Wrapped(t *testing.T) {
+	orig := &ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	wrapped := fmt.Errorf("getting node: %w", orig)
+	cerr, err := ChefError(wrapped)
+	assert.Equal(t, orig, cerr, "ErrorResponse found through wrapping")
+	assert.Equal(t, wrapped, err)
+
+	cerr, err = ChefError(errors.New("plain"))
+	assert.Nil(t, cerr)
+	assert.NotNil(t, err)
+}
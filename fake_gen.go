@@ -0,0 +1,2586 @@
+// Code generated by go run ./internal/genapi. DO NOT EDIT.
+
+package chef
+
+import (
+	"context"
+)
+
+// FakeACLs is a ACLsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeACLs struct {
+	GetFunc            func(string, string) (ACL, error)
+	GetWithContextFunc func(context.Context, string, string) (ACL, error)
+	PutFunc            func(string, string, string, *ACL) error
+	PutWithContextFunc func(context.Context, string, string, string, *ACL) error
+}
+
+// Get implements ACLsAPI
+func (fake *FakeACLs) Get(subkind string, name string) (r0 ACL, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(subkind, name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), subkind, name)
+	}
+	return
+}
+
+// GetWithContext implements ACLsAPI
+func (fake *FakeACLs) GetWithContext(ctx context.Context, subkind string, name string) (r0 ACL, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, subkind, name)
+	}
+	return
+}
+
+// Put implements ACLsAPI
+func (fake *FakeACLs) Put(subkind string, name string, perm string, item *ACL) (r0 error) {
+	if fake.PutFunc != nil {
+		return fake.PutFunc(subkind, name, perm, item)
+	}
+	if fake.PutWithContextFunc != nil {
+		return fake.PutWithContextFunc(context.Background(), subkind, name, perm, item)
+	}
+	return
+}
+
+// PutWithContext implements ACLsAPI
+func (fake *FakeACLs) PutWithContext(ctx context.Context, subkind string, name string, perm string, item *ACL) (r0 error) {
+	if fake.PutWithContextFunc != nil {
+		return fake.PutWithContextFunc(ctx, subkind, name, perm, item)
+	}
+	return
+}
+
+// FakeAssociations is a AssociationsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeAssociations struct {
+	AcceptInviteFunc            func(string) (string, error)
+	AcceptInviteWithContextFunc func(context.Context, string) (string, error)
+	AddFunc                     func(AddNow) error
+	AddWithContextFunc          func(context.Context, AddNow) error
+	DeleteFunc                  func(string) (OrgUser, error)
+	DeleteInviteFunc            func(string) (RescindInvite, error)
+	DeleteInviteWithContextFunc func(context.Context, string) (RescindInvite, error)
+	DeleteWithContextFunc       func(context.Context, string) (OrgUser, error)
+	GetFunc                     func(string) (OrgUser, error)
+	GetWithContextFunc          func(context.Context, string) (OrgUser, error)
+	InviteFunc                  func(Request) (Association, error)
+	InviteIdFunc                func(string) (string, error)
+	InviteIdWithContextFunc     func(context.Context, string) (string, error)
+	InviteWithContextFunc       func(context.Context, Request) (Association, error)
+	ListFunc                    func() ([]OrgUserListEntry, error)
+	ListInvitesFunc             func() ([]Invite, error)
+	ListInvitesWithContextFunc  func(context.Context) ([]Invite, error)
+	ListWithContextFunc         func(context.Context) ([]OrgUserListEntry, error)
+}
+
+// AcceptInvite implements AssociationsAPI
+func (fake *FakeAssociations) AcceptInvite(id string) (r0 string, r1 error) {
+	if fake.AcceptInviteFunc != nil {
+		return fake.AcceptInviteFunc(id)
+	}
+	if fake.AcceptInviteWithContextFunc != nil {
+		return fake.AcceptInviteWithContextFunc(context.Background(), id)
+	}
+	return
+}
+
+// AcceptInviteWithContext implements AssociationsAPI
+func (fake *FakeAssociations) AcceptInviteWithContext(ctx context.Context, id string) (r0 string, r1 error) {
+	if fake.AcceptInviteWithContextFunc != nil {
+		return fake.AcceptInviteWithContextFunc(ctx, id)
+	}
+	return
+}
+
+// Add implements AssociationsAPI
+func (fake *FakeAssociations) Add(addme AddNow) (r0 error) {
+	if fake.AddFunc != nil {
+		return fake.AddFunc(addme)
+	}
+	if fake.AddWithContextFunc != nil {
+		return fake.AddWithContextFunc(context.Background(), addme)
+	}
+	return
+}
+
+// AddWithContext implements AssociationsAPI
+func (fake *FakeAssociations) AddWithContext(ctx context.Context, addme AddNow) (r0 error) {
+	if fake.AddWithContextFunc != nil {
+		return fake.AddWithContextFunc(ctx, addme)
+	}
+	return
+}
+
+// Delete implements AssociationsAPI
+func (fake *FakeAssociations) Delete(name string) (r0 OrgUser, r1 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// DeleteInvite implements AssociationsAPI
+func (fake *FakeAssociations) DeleteInvite(id string) (r0 RescindInvite, r1 error) {
+	if fake.DeleteInviteFunc != nil {
+		return fake.DeleteInviteFunc(id)
+	}
+	if fake.DeleteInviteWithContextFunc != nil {
+		return fake.DeleteInviteWithContextFunc(context.Background(), id)
+	}
+	return
+}
+
+// DeleteInviteWithContext implements AssociationsAPI
+func (fake *FakeAssociations) DeleteInviteWithContext(ctx context.Context, id string) (r0 RescindInvite, r1 error) {
+	if fake.DeleteInviteWithContextFunc != nil {
+		return fake.DeleteInviteWithContextFunc(ctx, id)
+	}
+	return
+}
+
+// DeleteWithContext implements AssociationsAPI
+func (fake *FakeAssociations) DeleteWithContext(ctx context.Context, name string) (r0 OrgUser, r1 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Get implements AssociationsAPI
+func (fake *FakeAssociations) Get(name string) (r0 OrgUser, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetWithContext implements AssociationsAPI
+func (fake *FakeAssociations) GetWithContext(ctx context.Context, name string) (r0 OrgUser, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Invite implements AssociationsAPI
+func (fake *FakeAssociations) Invite(invite Request) (r0 Association, r1 error) {
+	if fake.InviteFunc != nil {
+		return fake.InviteFunc(invite)
+	}
+	if fake.InviteWithContextFunc != nil {
+		return fake.InviteWithContextFunc(context.Background(), invite)
+	}
+	return
+}
+
+// InviteId implements AssociationsAPI
+func (fake *FakeAssociations) InviteId(user string) (r0 string, r1 error) {
+	if fake.InviteIdFunc != nil {
+		return fake.InviteIdFunc(user)
+	}
+	if fake.InviteIdWithContextFunc != nil {
+		return fake.InviteIdWithContextFunc(context.Background(), user)
+	}
+	return
+}
+
+// InviteIdWithContext implements AssociationsAPI
+func (fake *FakeAssociations) InviteIdWithContext(ctx context.Context, user string) (r0 string, r1 error) {
+	if fake.InviteIdWithContextFunc != nil {
+		return fake.InviteIdWithContextFunc(ctx, user)
+	}
+	return
+}
+
+// InviteWithContext implements AssociationsAPI
+func (fake *FakeAssociations) InviteWithContext(ctx context.Context, invite Request) (r0 Association, r1 error) {
+	if fake.InviteWithContextFunc != nil {
+		return fake.InviteWithContextFunc(ctx, invite)
+	}
+	return
+}
+
+// List implements AssociationsAPI
+func (fake *FakeAssociations) List() (r0 []OrgUserListEntry, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListInvites implements AssociationsAPI
+func (fake *FakeAssociations) ListInvites() (r0 []Invite, r1 error) {
+	if fake.ListInvitesFunc != nil {
+		return fake.ListInvitesFunc()
+	}
+	if fake.ListInvitesWithContextFunc != nil {
+		return fake.ListInvitesWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListInvitesWithContext implements AssociationsAPI
+func (fake *FakeAssociations) ListInvitesWithContext(ctx context.Context) (r0 []Invite, r1 error) {
+	if fake.ListInvitesWithContextFunc != nil {
+		return fake.ListInvitesWithContextFunc(ctx)
+	}
+	return
+}
+
+// ListWithContext implements AssociationsAPI
+func (fake *FakeAssociations) ListWithContext(ctx context.Context) (r0 []OrgUserListEntry, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// FakeAuthenticateUser is a AuthenticateUserAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeAuthenticateUser struct {
+	AuthenticateFunc            func(Authenticate) error
+	AuthenticateWithContextFunc func(context.Context, Authenticate) error
+}
+
+// Authenticate implements AuthenticateUserAPI
+func (fake *FakeAuthenticateUser) Authenticate(authenticate_request Authenticate) (r0 error) {
+	if fake.AuthenticateFunc != nil {
+		return fake.AuthenticateFunc(authenticate_request)
+	}
+	if fake.AuthenticateWithContextFunc != nil {
+		return fake.AuthenticateWithContextFunc(context.Background(), authenticate_request)
+	}
+	return
+}
+
+// AuthenticateWithContext implements AuthenticateUserAPI
+func (fake *FakeAuthenticateUser) AuthenticateWithContext(ctx context.Context, authenticate_request Authenticate) (r0 error) {
+	if fake.AuthenticateWithContextFunc != nil {
+		return fake.AuthenticateWithContextFunc(ctx, authenticate_request)
+	}
+	return
+}
+
+// FakeClients is a ClientsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeClients struct {
+	AddKeyFunc               func(string, AccessKey) (KeyItem, error)
+	AddKeyWithContextFunc    func(context.Context, string, AccessKey) (KeyItem, error)
+	CreateFunc               func(ApiNewClient) (*ApiClientCreateResult, error)
+	CreateWithContextFunc    func(context.Context, ApiNewClient) (*ApiClientCreateResult, error)
+	DeleteFunc               func(string) error
+	DeleteKeyFunc            func(string, string) (AccessKey, error)
+	DeleteKeyWithContextFunc func(context.Context, string, string) (AccessKey, error)
+	DeleteWithContextFunc    func(context.Context, string) error
+	GetFunc                  func(string) (ApiClient, error)
+	GetKeyFunc               func(string, string) (AccessKey, error)
+	GetKeyWithContextFunc    func(context.Context, string, string) (AccessKey, error)
+	GetWithContextFunc       func(context.Context, string) (ApiClient, error)
+	ListFunc                 func() (ApiClientListResult, error)
+	ListKeysFunc             func(string) ([]KeyItem, error)
+	ListKeysWithContextFunc  func(context.Context, string) ([]KeyItem, error)
+	ListWithContextFunc      func(context.Context) (ApiClientListResult, error)
+	UpdateFunc               func(string, ApiNewClient) (*ApiClient, error)
+	UpdateKeyFunc            func(string, string, AccessKey) (AccessKey, error)
+	UpdateKeyWithContextFunc func(context.Context, string, string, AccessKey) (AccessKey, error)
+	UpdateWithContextFunc    func(context.Context, string, ApiNewClient) (*ApiClient, error)
+}
+
+// AddKey implements ClientsAPI
+func (fake *FakeClients) AddKey(name string, keyadd AccessKey) (r0 KeyItem, r1 error) {
+	if fake.AddKeyFunc != nil {
+		return fake.AddKeyFunc(name, keyadd)
+	}
+	if fake.AddKeyWithContextFunc != nil {
+		return fake.AddKeyWithContextFunc(context.Background(), name, keyadd)
+	}
+	return
+}
+
+// AddKeyWithContext implements ClientsAPI
+func (fake *FakeClients) AddKeyWithContext(ctx context.Context, name string, keyadd AccessKey) (r0 KeyItem, r1 error) {
+	if fake.AddKeyWithContextFunc != nil {
+		return fake.AddKeyWithContextFunc(ctx, name, keyadd)
+	}
+	return
+}
+
+// Create implements ClientsAPI
+func (fake *FakeClients) Create(client ApiNewClient) (r0 *ApiClientCreateResult, r1 error) {
+	if fake.CreateFunc != nil {
+		return fake.CreateFunc(client)
+	}
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(context.Background(), client)
+	}
+	return
+}
+
+// CreateWithContext implements ClientsAPI
+func (fake *FakeClients) CreateWithContext(ctx context.Context, client ApiNewClient) (r0 *ApiClientCreateResult, r1 error) {
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(ctx, client)
+	}
+	return
+}
+
+// Delete implements ClientsAPI
+func (fake *FakeClients) Delete(name string) (r0 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// DeleteKey implements ClientsAPI
+func (fake *FakeClients) DeleteKey(name string, keyname string) (r0 AccessKey, r1 error) {
+	if fake.DeleteKeyFunc != nil {
+		return fake.DeleteKeyFunc(name, keyname)
+	}
+	if fake.DeleteKeyWithContextFunc != nil {
+		return fake.DeleteKeyWithContextFunc(context.Background(), name, keyname)
+	}
+	return
+}
+
+// DeleteKeyWithContext implements ClientsAPI
+func (fake *FakeClients) DeleteKeyWithContext(ctx context.Context, name string, keyname string) (r0 AccessKey, r1 error) {
+	if fake.DeleteKeyWithContextFunc != nil {
+		return fake.DeleteKeyWithContextFunc(ctx, name, keyname)
+	}
+	return
+}
+
+// DeleteWithContext implements ClientsAPI
+func (fake *FakeClients) DeleteWithContext(ctx context.Context, name string) (r0 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Get implements ClientsAPI
+func (fake *FakeClients) Get(name string) (r0 ApiClient, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetKey implements ClientsAPI
+func (fake *FakeClients) GetKey(name string, keyname string) (r0 AccessKey, r1 error) {
+	if fake.GetKeyFunc != nil {
+		return fake.GetKeyFunc(name, keyname)
+	}
+	if fake.GetKeyWithContextFunc != nil {
+		return fake.GetKeyWithContextFunc(context.Background(), name, keyname)
+	}
+	return
+}
+
+// GetKeyWithContext implements ClientsAPI
+func (fake *FakeClients) GetKeyWithContext(ctx context.Context, name string, keyname string) (r0 AccessKey, r1 error) {
+	if fake.GetKeyWithContextFunc != nil {
+		return fake.GetKeyWithContextFunc(ctx, name, keyname)
+	}
+	return
+}
+
+// GetWithContext implements ClientsAPI
+func (fake *FakeClients) GetWithContext(ctx context.Context, name string) (r0 ApiClient, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements ClientsAPI
+func (fake *FakeClients) List() (r0 ApiClientListResult, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListKeys implements ClientsAPI
+func (fake *FakeClients) ListKeys(name string) (r0 []KeyItem, r1 error) {
+	if fake.ListKeysFunc != nil {
+		return fake.ListKeysFunc(name)
+	}
+	if fake.ListKeysWithContextFunc != nil {
+		return fake.ListKeysWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// ListKeysWithContext implements ClientsAPI
+func (fake *FakeClients) ListKeysWithContext(ctx context.Context, name string) (r0 []KeyItem, r1 error) {
+	if fake.ListKeysWithContextFunc != nil {
+		return fake.ListKeysWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// ListWithContext implements ClientsAPI
+func (fake *FakeClients) ListWithContext(ctx context.Context) (r0 ApiClientListResult, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// Update implements ClientsAPI
+func (fake *FakeClients) Update(name string, client ApiNewClient) (r0 *ApiClient, r1 error) {
+	if fake.UpdateFunc != nil {
+		return fake.UpdateFunc(name, client)
+	}
+	if fake.UpdateWithContextFunc != nil {
+		return fake.UpdateWithContextFunc(context.Background(), name, client)
+	}
+	return
+}
+
+// UpdateKey implements ClientsAPI
+func (fake *FakeClients) UpdateKey(name string, keyname string, keyupd AccessKey) (r0 AccessKey, r1 error) {
+	if fake.UpdateKeyFunc != nil {
+		return fake.UpdateKeyFunc(name, keyname, keyupd)
+	}
+	if fake.UpdateKeyWithContextFunc != nil {
+		return fake.UpdateKeyWithContextFunc(context.Background(), name, keyname, keyupd)
+	}
+	return
+}
+
+// UpdateKeyWithContext implements ClientsAPI
+func (fake *FakeClients) UpdateKeyWithContext(ctx context.Context, name string, keyname string, keyupd AccessKey) (r0 AccessKey, r1 error) {
+	if fake.UpdateKeyWithContextFunc != nil {
+		return fake.UpdateKeyWithContextFunc(ctx, name, keyname, keyupd)
+	}
+	return
+}
+
+// UpdateWithContext implements ClientsAPI
+func (fake *FakeClients) UpdateWithContext(ctx context.Context, name string, client ApiNewClient) (r0 *ApiClient, r1 error) {
+	if fake.UpdateWithContextFunc != nil {
+		return fake.UpdateWithContextFunc(ctx, name, client)
+	}
+	return
+}
+
+// FakeContainers is a ContainersAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeContainers struct {
+	CreateFunc            func(Container) (*ContainerCreateResult, error)
+	CreateWithContextFunc func(context.Context, Container) (*ContainerCreateResult, error)
+	DeleteFunc            func(string) error
+	DeleteWithContextFunc func(context.Context, string) error
+	GetFunc               func(string) (Container, error)
+	GetWithContextFunc    func(context.Context, string) (Container, error)
+	ListFunc              func() (ContainerListResult, error)
+	ListWithContextFunc   func(context.Context) (ContainerListResult, error)
+}
+
+// Create implements ContainersAPI
+func (fake *FakeContainers) Create(container Container) (r0 *ContainerCreateResult, r1 error) {
+	if fake.CreateFunc != nil {
+		return fake.CreateFunc(container)
+	}
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(context.Background(), container)
+	}
+	return
+}
+
+// CreateWithContext implements ContainersAPI
+func (fake *FakeContainers) CreateWithContext(ctx context.Context, container Container) (r0 *ContainerCreateResult, r1 error) {
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(ctx, container)
+	}
+	return
+}
+
+// Delete implements ContainersAPI
+func (fake *FakeContainers) Delete(name string) (r0 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// DeleteWithContext implements ContainersAPI
+func (fake *FakeContainers) DeleteWithContext(ctx context.Context, name string) (r0 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Get implements ContainersAPI
+func (fake *FakeContainers) Get(name string) (r0 Container, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetWithContext implements ContainersAPI
+func (fake *FakeContainers) GetWithContext(ctx context.Context, name string) (r0 Container, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements ContainersAPI
+func (fake *FakeContainers) List() (r0 ContainerListResult, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListWithContext implements ContainersAPI
+func (fake *FakeContainers) ListWithContext(ctx context.Context) (r0 ContainerListResult, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// FakeCookbookArtifacts is a CookbookArtifactsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeCookbookArtifacts struct {
+	DownloadToFunc            func(string, string, string) error
+	DownloadToWithContextFunc func(context.Context, string, string, string) error
+	GetFunc                   func(string) (CBAGetResponse, error)
+	GetVersionFunc            func(string, string) (CBADetail, error)
+	GetVersionWithContextFunc func(context.Context, string, string) (CBADetail, error)
+	GetWithContextFunc        func(context.Context, string) (CBAGetResponse, error)
+	ListFunc                  func() (CBAGetResponse, error)
+	ListWithContextFunc       func(context.Context) (CBAGetResponse, error)
+}
+
+// DownloadTo implements CookbookArtifactsAPI
+func (fake *FakeCookbookArtifacts) DownloadTo(name string, id string, localDir string) (r0 error) {
+	if fake.DownloadToFunc != nil {
+		return fake.DownloadToFunc(name, id, localDir)
+	}
+	if fake.DownloadToWithContextFunc != nil {
+		return fake.DownloadToWithContextFunc(context.Background(), name, id, localDir)
+	}
+	return
+}
+
+// DownloadToWithContext implements CookbookArtifactsAPI
+func (fake *FakeCookbookArtifacts) DownloadToWithContext(ctx context.Context, name string, id string, localDir string) (r0 error) {
+	if fake.DownloadToWithContextFunc != nil {
+		return fake.DownloadToWithContextFunc(ctx, name, id, localDir)
+	}
+	return
+}
+
+// Get implements CookbookArtifactsAPI
+func (fake *FakeCookbookArtifacts) Get(name string) (r0 CBAGetResponse, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetVersion implements CookbookArtifactsAPI
+func (fake *FakeCookbookArtifacts) GetVersion(name string, id string) (r0 CBADetail, r1 error) {
+	if fake.GetVersionFunc != nil {
+		return fake.GetVersionFunc(name, id)
+	}
+	if fake.GetVersionWithContextFunc != nil {
+		return fake.GetVersionWithContextFunc(context.Background(), name, id)
+	}
+	return
+}
+
+// GetVersionWithContext implements CookbookArtifactsAPI
+func (fake *FakeCookbookArtifacts) GetVersionWithContext(ctx context.Context, name string, id string) (r0 CBADetail, r1 error) {
+	if fake.GetVersionWithContextFunc != nil {
+		return fake.GetVersionWithContextFunc(ctx, name, id)
+	}
+	return
+}
+
+// GetWithContext implements CookbookArtifactsAPI
+func (fake *FakeCookbookArtifacts) GetWithContext(ctx context.Context, name string) (r0 CBAGetResponse, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements CookbookArtifactsAPI
+func (fake *FakeCookbookArtifacts) List() (r0 CBAGetResponse, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListWithContext implements CookbookArtifactsAPI
+func (fake *FakeCookbookArtifacts) ListWithContext(ctx context.Context) (r0 CBAGetResponse, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// FakeCookbooks is a CookbooksAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeCookbooks struct {
+	DeleteFunc                           func(string, string) error
+	DeleteWithContextFunc                func(context.Context, string, string) error
+	DownloadFunc                         func(string, string) error
+	DownloadAtFunc                       func(string, string, string) error
+	DownloadToFunc                       func(string, string, string) error
+	DownloadToWithContextFunc            func(context.Context, string, string, string) error
+	DownloadWithContextFunc              func(context.Context, string, string) error
+	GetFunc                              func(string) (CookbookVersion, error)
+	GetAvailableVersionsFunc             func(string, string) (CookbookListResult, error)
+	GetAvailableVersionsWithContextFunc  func(context.Context, string, string) (CookbookListResult, error)
+	GetVersionFunc                       func(string, string) (Cookbook, error)
+	GetVersionWithContextFunc            func(context.Context, string, string) (Cookbook, error)
+	GetWithContextFunc                   func(context.Context, string) (CookbookVersion, error)
+	ListFunc                             func() (CookbookListResult, error)
+	ListAllRecipesFunc                   func() (CookbookRecipesResult, error)
+	ListAllRecipesWithContextFunc        func(context.Context) (CookbookRecipesResult, error)
+	ListAvailableVersionsFunc            func(string) (CookbookListResult, error)
+	ListAvailableVersionsWithContextFunc func(context.Context, string) (CookbookListResult, error)
+	ListWithContextFunc                  func(context.Context) (CookbookListResult, error)
+}
+
+// Delete implements CookbooksAPI
+func (fake *FakeCookbooks) Delete(name string, version string) (r0 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name, version)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name, version)
+	}
+	return
+}
+
+// DeleteWithContext implements CookbooksAPI
+func (fake *FakeCookbooks) DeleteWithContext(ctx context.Context, name string, version string) (r0 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name, version)
+	}
+	return
+}
+
+// Download implements CookbooksAPI
+func (fake *FakeCookbooks) Download(name string, version string) (r0 error) {
+	if fake.DownloadFunc != nil {
+		return fake.DownloadFunc(name, version)
+	}
+	if fake.DownloadWithContextFunc != nil {
+		return fake.DownloadWithContextFunc(context.Background(), name, version)
+	}
+	return
+}
+
+// DownloadAt implements CookbooksAPI
+func (fake *FakeCookbooks) DownloadAt(name string, version string, localDir string) (r0 error) {
+	if fake.DownloadAtFunc != nil {
+		return fake.DownloadAtFunc(name, version, localDir)
+	}
+	return
+}
+
+// DownloadTo implements CookbooksAPI
+func (fake *FakeCookbooks) DownloadTo(name string, version string, localDir string) (r0 error) {
+	if fake.DownloadToFunc != nil {
+		return fake.DownloadToFunc(name, version, localDir)
+	}
+	if fake.DownloadToWithContextFunc != nil {
+		return fake.DownloadToWithContextFunc(context.Background(), name, version, localDir)
+	}
+	return
+}
+
+// DownloadToWithContext implements CookbooksAPI
+func (fake *FakeCookbooks) DownloadToWithContext(ctx context.Context, name string, version string, localDir string) (r0 error) {
+	if fake.DownloadToWithContextFunc != nil {
+		return fake.DownloadToWithContextFunc(ctx, name, version, localDir)
+	}
+	return
+}
+
+// DownloadWithContext implements CookbooksAPI
+func (fake *FakeCookbooks) DownloadWithContext(ctx context.Context, name string, version string) (r0 error) {
+	if fake.DownloadWithContextFunc != nil {
+		return fake.DownloadWithContextFunc(ctx, name, version)
+	}
+	return
+}
+
+// Get implements CookbooksAPI
+func (fake *FakeCookbooks) Get(name string) (r0 CookbookVersion, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetAvailableVersions implements CookbooksAPI
+func (fake *FakeCookbooks) GetAvailableVersions(name string, numVersions string) (r0 CookbookListResult, r1 error) {
+	if fake.GetAvailableVersionsFunc != nil {
+		return fake.GetAvailableVersionsFunc(name, numVersions)
+	}
+	if fake.GetAvailableVersionsWithContextFunc != nil {
+		return fake.GetAvailableVersionsWithContextFunc(context.Background(), name, numVersions)
+	}
+	return
+}
+
+// GetAvailableVersionsWithContext implements CookbooksAPI
+func (fake *FakeCookbooks) GetAvailableVersionsWithContext(ctx context.Context, name string, numVersions string) (r0 CookbookListResult, r1 error) {
+	if fake.GetAvailableVersionsWithContextFunc != nil {
+		return fake.GetAvailableVersionsWithContextFunc(ctx, name, numVersions)
+	}
+	return
+}
+
+// GetVersion implements CookbooksAPI
+func (fake *FakeCookbooks) GetVersion(name string, version string) (r0 Cookbook, r1 error) {
+	if fake.GetVersionFunc != nil {
+		return fake.GetVersionFunc(name, version)
+	}
+	if fake.GetVersionWithContextFunc != nil {
+		return fake.GetVersionWithContextFunc(context.Background(), name, version)
+	}
+	return
+}
+
+// GetVersionWithContext implements CookbooksAPI
+func (fake *FakeCookbooks) GetVersionWithContext(ctx context.Context, name string, version string) (r0 Cookbook, r1 error) {
+	if fake.GetVersionWithContextFunc != nil {
+		return fake.GetVersionWithContextFunc(ctx, name, version)
+	}
+	return
+}
+
+// GetWithContext implements CookbooksAPI
+func (fake *FakeCookbooks) GetWithContext(ctx context.Context, name string) (r0 CookbookVersion, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements CookbooksAPI
+func (fake *FakeCookbooks) List() (r0 CookbookListResult, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListAllRecipes implements CookbooksAPI
+func (fake *FakeCookbooks) ListAllRecipes() (r0 CookbookRecipesResult, r1 error) {
+	if fake.ListAllRecipesFunc != nil {
+		return fake.ListAllRecipesFunc()
+	}
+	if fake.ListAllRecipesWithContextFunc != nil {
+		return fake.ListAllRecipesWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListAllRecipesWithContext implements CookbooksAPI
+func (fake *FakeCookbooks) ListAllRecipesWithContext(ctx context.Context) (r0 CookbookRecipesResult, r1 error) {
+	if fake.ListAllRecipesWithContextFunc != nil {
+		return fake.ListAllRecipesWithContextFunc(ctx)
+	}
+	return
+}
+
+// ListAvailableVersions implements CookbooksAPI
+func (fake *FakeCookbooks) ListAvailableVersions(numVersions string) (r0 CookbookListResult, r1 error) {
+	if fake.ListAvailableVersionsFunc != nil {
+		return fake.ListAvailableVersionsFunc(numVersions)
+	}
+	if fake.ListAvailableVersionsWithContextFunc != nil {
+		return fake.ListAvailableVersionsWithContextFunc(context.Background(), numVersions)
+	}
+	return
+}
+
+// ListAvailableVersionsWithContext implements CookbooksAPI
+func (fake *FakeCookbooks) ListAvailableVersionsWithContext(ctx context.Context, numVersions string) (r0 CookbookListResult, r1 error) {
+	if fake.ListAvailableVersionsWithContextFunc != nil {
+		return fake.ListAvailableVersionsWithContextFunc(ctx, numVersions)
+	}
+	return
+}
+
+// ListWithContext implements CookbooksAPI
+func (fake *FakeCookbooks) ListWithContext(ctx context.Context) (r0 CookbookListResult, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// FakeDataBags is a DataBagsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeDataBags struct {
+	CreateFunc                func(*DataBag) (*DataBagCreateResult, error)
+	CreateItemFunc            func(string, DataBagItem) error
+	CreateItemWithContextFunc func(context.Context, string, DataBagItem) error
+	CreateWithContextFunc     func(context.Context, *DataBag) (*DataBagCreateResult, error)
+	DeleteFunc                func(string) (*DataBag, error)
+	DeleteItemFunc            func(string, string) error
+	DeleteItemWithContextFunc func(context.Context, string, string) error
+	DeleteWithContextFunc     func(context.Context, string) (*DataBag, error)
+	GetItemFunc               func(string, string) (DataBagItem, error)
+	GetItemWithContextFunc    func(context.Context, string, string) (DataBagItem, error)
+	ListFunc                  func() (*DataBagListResult, error)
+	ListItemsFunc             func(string) (*DataBagListResult, error)
+	ListItemsWithContextFunc  func(context.Context, string) (*DataBagListResult, error)
+	ListWithContextFunc       func(context.Context) (*DataBagListResult, error)
+	UpdateItemFunc            func(string, string, DataBagItem) error
+	UpdateItemWithContextFunc func(context.Context, string, string, DataBagItem) error
+}
+
+// Create implements DataBagsAPI
+func (fake *FakeDataBags) Create(databag *DataBag) (r0 *DataBagCreateResult, r1 error) {
+	if fake.CreateFunc != nil {
+		return fake.CreateFunc(databag)
+	}
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(context.Background(), databag)
+	}
+	return
+}
+
+// CreateItem implements DataBagsAPI
+func (fake *FakeDataBags) CreateItem(databagName string, databagItem DataBagItem) (r0 error) {
+	if fake.CreateItemFunc != nil {
+		return fake.CreateItemFunc(databagName, databagItem)
+	}
+	if fake.CreateItemWithContextFunc != nil {
+		return fake.CreateItemWithContextFunc(context.Background(), databagName, databagItem)
+	}
+	return
+}
+
+// CreateItemWithContext implements DataBagsAPI
+func (fake *FakeDataBags) CreateItemWithContext(ctx context.Context, databagName string, databagItem DataBagItem) (r0 error) {
+	if fake.CreateItemWithContextFunc != nil {
+		return fake.CreateItemWithContextFunc(ctx, databagName, databagItem)
+	}
+	return
+}
+
+// CreateWithContext implements DataBagsAPI
+func (fake *FakeDataBags) CreateWithContext(ctx context.Context, databag *DataBag) (r0 *DataBagCreateResult, r1 error) {
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(ctx, databag)
+	}
+	return
+}
+
+// Delete implements DataBagsAPI
+func (fake *FakeDataBags) Delete(name string) (r0 *DataBag, r1 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// DeleteItem implements DataBagsAPI
+func (fake *FakeDataBags) DeleteItem(databagName string, databagItem string) (r0 error) {
+	if fake.DeleteItemFunc != nil {
+		return fake.DeleteItemFunc(databagName, databagItem)
+	}
+	if fake.DeleteItemWithContextFunc != nil {
+		return fake.DeleteItemWithContextFunc(context.Background(), databagName, databagItem)
+	}
+	return
+}
+
+// DeleteItemWithContext implements DataBagsAPI
+func (fake *FakeDataBags) DeleteItemWithContext(ctx context.Context, databagName string, databagItem string) (r0 error) {
+	if fake.DeleteItemWithContextFunc != nil {
+		return fake.DeleteItemWithContextFunc(ctx, databagName, databagItem)
+	}
+	return
+}
+
+// DeleteWithContext implements DataBagsAPI
+func (fake *FakeDataBags) DeleteWithContext(ctx context.Context, name string) (r0 *DataBag, r1 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// GetItem implements DataBagsAPI
+func (fake *FakeDataBags) GetItem(databagName string, databagItem string) (r0 DataBagItem, r1 error) {
+	if fake.GetItemFunc != nil {
+		return fake.GetItemFunc(databagName, databagItem)
+	}
+	if fake.GetItemWithContextFunc != nil {
+		return fake.GetItemWithContextFunc(context.Background(), databagName, databagItem)
+	}
+	return
+}
+
+// GetItemWithContext implements DataBagsAPI
+func (fake *FakeDataBags) GetItemWithContext(ctx context.Context, databagName string, databagItem string) (r0 DataBagItem, r1 error) {
+	if fake.GetItemWithContextFunc != nil {
+		return fake.GetItemWithContextFunc(ctx, databagName, databagItem)
+	}
+	return
+}
+
+// List implements DataBagsAPI
+func (fake *FakeDataBags) List() (r0 *DataBagListResult, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListItems implements DataBagsAPI
+func (fake *FakeDataBags) ListItems(name string) (r0 *DataBagListResult, r1 error) {
+	if fake.ListItemsFunc != nil {
+		return fake.ListItemsFunc(name)
+	}
+	if fake.ListItemsWithContextFunc != nil {
+		return fake.ListItemsWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// ListItemsWithContext implements DataBagsAPI
+func (fake *FakeDataBags) ListItemsWithContext(ctx context.Context, name string) (r0 *DataBagListResult, r1 error) {
+	if fake.ListItemsWithContextFunc != nil {
+		return fake.ListItemsWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// ListWithContext implements DataBagsAPI
+func (fake *FakeDataBags) ListWithContext(ctx context.Context) (r0 *DataBagListResult, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// UpdateItem implements DataBagsAPI
+func (fake *FakeDataBags) UpdateItem(databagName string, databagItemId string, databagItem DataBagItem) (r0 error) {
+	if fake.UpdateItemFunc != nil {
+		return fake.UpdateItemFunc(databagName, databagItemId, databagItem)
+	}
+	if fake.UpdateItemWithContextFunc != nil {
+		return fake.UpdateItemWithContextFunc(context.Background(), databagName, databagItemId, databagItem)
+	}
+	return
+}
+
+// UpdateItemWithContext implements DataBagsAPI
+func (fake *FakeDataBags) UpdateItemWithContext(ctx context.Context, databagName string, databagItemId string, databagItem DataBagItem) (r0 error) {
+	if fake.UpdateItemWithContextFunc != nil {
+		return fake.UpdateItemWithContextFunc(ctx, databagName, databagItemId, databagItem)
+	}
+	return
+}
+
+// FakeEnvironments is a EnvironmentsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeEnvironments struct {
+	CreateFunc                   func(*Environment) (*EnvironmentResult, error)
+	CreateWithContextFunc        func(context.Context, *Environment) (*EnvironmentResult, error)
+	DeleteFunc                   func(string) (*Environment, error)
+	DeleteWithContextFunc        func(context.Context, string) (*Environment, error)
+	GetFunc                      func(string) (*Environment, error)
+	GetWithContextFunc           func(context.Context, string) (*Environment, error)
+	ListFunc                     func() (*EnvironmentResult, error)
+	ListCookbooksFunc            func(string, string) (EnvironmentCookbookResult, error)
+	ListCookbooksWithContextFunc func(context.Context, string, string) (EnvironmentCookbookResult, error)
+	ListRecipesFunc              func(string) (EnvironmentRecipesResult, error)
+	ListRecipesWithContextFunc   func(context.Context, string) (EnvironmentRecipesResult, error)
+	ListWithContextFunc          func(context.Context) (*EnvironmentResult, error)
+	PutFunc                      func(*Environment) (*Environment, error)
+	PutWithContextFunc           func(context.Context, *Environment) (*Environment, error)
+}
+
+// Create implements EnvironmentsAPI
+func (fake *FakeEnvironments) Create(environment *Environment) (r0 *EnvironmentResult, r1 error) {
+	if fake.CreateFunc != nil {
+		return fake.CreateFunc(environment)
+	}
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(context.Background(), environment)
+	}
+	return
+}
+
+// CreateWithContext implements EnvironmentsAPI
+func (fake *FakeEnvironments) CreateWithContext(ctx context.Context, environment *Environment) (r0 *EnvironmentResult, r1 error) {
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(ctx, environment)
+	}
+	return
+}
+
+// Delete implements EnvironmentsAPI
+func (fake *FakeEnvironments) Delete(name string) (r0 *Environment, r1 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// DeleteWithContext implements EnvironmentsAPI
+func (fake *FakeEnvironments) DeleteWithContext(ctx context.Context, name string) (r0 *Environment, r1 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Get implements EnvironmentsAPI
+func (fake *FakeEnvironments) Get(name string) (r0 *Environment, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetWithContext implements EnvironmentsAPI
+func (fake *FakeEnvironments) GetWithContext(ctx context.Context, name string) (r0 *Environment, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements EnvironmentsAPI
+func (fake *FakeEnvironments) List() (r0 *EnvironmentResult, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListCookbooks implements EnvironmentsAPI
+func (fake *FakeEnvironments) ListCookbooks(name string, numVersions string) (r0 EnvironmentCookbookResult, r1 error) {
+	if fake.ListCookbooksFunc != nil {
+		return fake.ListCookbooksFunc(name, numVersions)
+	}
+	if fake.ListCookbooksWithContextFunc != nil {
+		return fake.ListCookbooksWithContextFunc(context.Background(), name, numVersions)
+	}
+	return
+}
+
+// ListCookbooksWithContext implements EnvironmentsAPI
+func (fake *FakeEnvironments) ListCookbooksWithContext(ctx context.Context, name string, numVersions string) (r0 EnvironmentCookbookResult, r1 error) {
+	if fake.ListCookbooksWithContextFunc != nil {
+		return fake.ListCookbooksWithContextFunc(ctx, name, numVersions)
+	}
+	return
+}
+
+// ListRecipes implements EnvironmentsAPI
+func (fake *FakeEnvironments) ListRecipes(name string) (r0 EnvironmentRecipesResult, r1 error) {
+	if fake.ListRecipesFunc != nil {
+		return fake.ListRecipesFunc(name)
+	}
+	if fake.ListRecipesWithContextFunc != nil {
+		return fake.ListRecipesWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// ListRecipesWithContext implements EnvironmentsAPI
+func (fake *FakeEnvironments) ListRecipesWithContext(ctx context.Context, name string) (r0 EnvironmentRecipesResult, r1 error) {
+	if fake.ListRecipesWithContextFunc != nil {
+		return fake.ListRecipesWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// ListWithContext implements EnvironmentsAPI
+func (fake *FakeEnvironments) ListWithContext(ctx context.Context) (r0 *EnvironmentResult, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// Put implements EnvironmentsAPI
+func (fake *FakeEnvironments) Put(environment *Environment) (r0 *Environment, r1 error) {
+	if fake.PutFunc != nil {
+		return fake.PutFunc(environment)
+	}
+	if fake.PutWithContextFunc != nil {
+		return fake.PutWithContextFunc(context.Background(), environment)
+	}
+	return
+}
+
+// PutWithContext implements EnvironmentsAPI
+func (fake *FakeEnvironments) PutWithContext(ctx context.Context, environment *Environment) (r0 *Environment, r1 error) {
+	if fake.PutWithContextFunc != nil {
+		return fake.PutWithContextFunc(ctx, environment)
+	}
+	return
+}
+
+// FakeGroups is a GroupsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeGroups struct {
+	CreateFunc            func(Group) (*GroupResult, error)
+	CreateWithContextFunc func(context.Context, Group) (*GroupResult, error)
+	DeleteFunc            func(string) error
+	DeleteWithContextFunc func(context.Context, string) error
+	GetFunc               func(string) (Group, error)
+	GetWithContextFunc    func(context.Context, string) (Group, error)
+	ListFunc              func() (map[string]string, error)
+	ListWithContextFunc   func(context.Context) (map[string]string, error)
+	UpdateFunc            func(GroupUpdate) (GroupUpdate, error)
+	UpdateWithContextFunc func(context.Context, GroupUpdate) (GroupUpdate, error)
+}
+
+// Create implements GroupsAPI
+func (fake *FakeGroups) Create(group Group) (r0 *GroupResult, r1 error) {
+	if fake.CreateFunc != nil {
+		return fake.CreateFunc(group)
+	}
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(context.Background(), group)
+	}
+	return
+}
+
+// CreateWithContext implements GroupsAPI
+func (fake *FakeGroups) CreateWithContext(ctx context.Context, group Group) (r0 *GroupResult, r1 error) {
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(ctx, group)
+	}
+	return
+}
+
+// Delete implements GroupsAPI
+func (fake *FakeGroups) Delete(name string) (r0 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// DeleteWithContext implements GroupsAPI
+func (fake *FakeGroups) DeleteWithContext(ctx context.Context, name string) (r0 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Get implements GroupsAPI
+func (fake *FakeGroups) Get(name string) (r0 Group, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetWithContext implements GroupsAPI
+func (fake *FakeGroups) GetWithContext(ctx context.Context, name string) (r0 Group, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements GroupsAPI
+func (fake *FakeGroups) List() (r0 map[string]string, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListWithContext implements GroupsAPI
+func (fake *FakeGroups) ListWithContext(ctx context.Context) (r0 map[string]string, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// Update implements GroupsAPI
+func (fake *FakeGroups) Update(g GroupUpdate) (r0 GroupUpdate, r1 error) {
+	if fake.UpdateFunc != nil {
+		return fake.UpdateFunc(g)
+	}
+	if fake.UpdateWithContextFunc != nil {
+		return fake.UpdateWithContextFunc(context.Background(), g)
+	}
+	return
+}
+
+// UpdateWithContext implements GroupsAPI
+func (fake *FakeGroups) UpdateWithContext(ctx context.Context, g GroupUpdate) (r0 GroupUpdate, r1 error) {
+	if fake.UpdateWithContextFunc != nil {
+		return fake.UpdateWithContextFunc(ctx, g)
+	}
+	return
+}
+
+// FakeLicense is a LicenseAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeLicense struct {
+	GetFunc            func() (License, error)
+	GetWithContextFunc func(context.Context) (License, error)
+}
+
+// Get implements LicenseAPI
+func (fake *FakeLicense) Get() (r0 License, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc()
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background())
+	}
+	return
+}
+
+// GetWithContext implements LicenseAPI
+func (fake *FakeLicense) GetWithContext(ctx context.Context) (r0 License, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx)
+	}
+	return
+}
+
+// FakeNodes is a NodesAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeNodes struct {
+	DeleteFunc            func(string) error
+	DeleteWithContextFunc func(context.Context, string) error
+	GetFunc               func(string) (Node, error)
+	GetWithContextFunc    func(context.Context, string) (Node, error)
+	HeadFunc              func(string) error
+	HeadWithContextFunc   func(context.Context, string) error
+	ListFunc              func() (map[string]string, error)
+	ListWithContextFunc   func(context.Context) (map[string]string, error)
+	PostFunc              func(Node) (*NodeResult, error)
+	PostWithContextFunc   func(context.Context, Node) (*NodeResult, error)
+	PutFunc               func(Node) (Node, error)
+	PutWithContextFunc    func(context.Context, Node) (Node, error)
+}
+
+// Delete implements NodesAPI
+func (fake *FakeNodes) Delete(name string) (r0 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// DeleteWithContext implements NodesAPI
+func (fake *FakeNodes) DeleteWithContext(ctx context.Context, name string) (r0 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Get implements NodesAPI
+func (fake *FakeNodes) Get(name string) (r0 Node, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetWithContext implements NodesAPI
+func (fake *FakeNodes) GetWithContext(ctx context.Context, name string) (r0 Node, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Head implements NodesAPI
+func (fake *FakeNodes) Head(name string) (r0 error) {
+	if fake.HeadFunc != nil {
+		return fake.HeadFunc(name)
+	}
+	if fake.HeadWithContextFunc != nil {
+		return fake.HeadWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// HeadWithContext implements NodesAPI
+func (fake *FakeNodes) HeadWithContext(ctx context.Context, name string) (r0 error) {
+	if fake.HeadWithContextFunc != nil {
+		return fake.HeadWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements NodesAPI
+func (fake *FakeNodes) List() (r0 map[string]string, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListWithContext implements NodesAPI
+func (fake *FakeNodes) ListWithContext(ctx context.Context) (r0 map[string]string, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// Post implements NodesAPI
+func (fake *FakeNodes) Post(node Node) (r0 *NodeResult, r1 error) {
+	if fake.PostFunc != nil {
+		return fake.PostFunc(node)
+	}
+	if fake.PostWithContextFunc != nil {
+		return fake.PostWithContextFunc(context.Background(), node)
+	}
+	return
+}
+
+// PostWithContext implements NodesAPI
+func (fake *FakeNodes) PostWithContext(ctx context.Context, node Node) (r0 *NodeResult, r1 error) {
+	if fake.PostWithContextFunc != nil {
+		return fake.PostWithContextFunc(ctx, node)
+	}
+	return
+}
+
+// Put implements NodesAPI
+func (fake *FakeNodes) Put(n Node) (r0 Node, r1 error) {
+	if fake.PutFunc != nil {
+		return fake.PutFunc(n)
+	}
+	if fake.PutWithContextFunc != nil {
+		return fake.PutWithContextFunc(context.Background(), n)
+	}
+	return
+}
+
+// PutWithContext implements NodesAPI
+func (fake *FakeNodes) PutWithContext(ctx context.Context, n Node) (r0 Node, r1 error) {
+	if fake.PutWithContextFunc != nil {
+		return fake.PutWithContextFunc(ctx, n)
+	}
+	return
+}
+
+// FakeOrganizations is a OrganizationsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeOrganizations struct {
+	CreateFunc            func(Organization) (OrganizationResult, error)
+	CreateWithContextFunc func(context.Context, Organization) (OrganizationResult, error)
+	DeleteFunc            func(string) error
+	DeleteWithContextFunc func(context.Context, string) error
+	GetFunc               func(string) (Organization, error)
+	GetWithContextFunc    func(context.Context, string) (Organization, error)
+	ListFunc              func() (map[string]string, error)
+	ListWithContextFunc   func(context.Context) (map[string]string, error)
+	UpdateFunc            func(Organization) (Organization, error)
+	UpdateWithContextFunc func(context.Context, Organization) (Organization, error)
+}
+
+// Create implements OrganizationsAPI
+func (fake *FakeOrganizations) Create(organization Organization) (r0 OrganizationResult, r1 error) {
+	if fake.CreateFunc != nil {
+		return fake.CreateFunc(organization)
+	}
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(context.Background(), organization)
+	}
+	return
+}
+
+// CreateWithContext implements OrganizationsAPI
+func (fake *FakeOrganizations) CreateWithContext(ctx context.Context, organization Organization) (r0 OrganizationResult, r1 error) {
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(ctx, organization)
+	}
+	return
+}
+
+// Delete implements OrganizationsAPI
+func (fake *FakeOrganizations) Delete(name string) (r0 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// DeleteWithContext implements OrganizationsAPI
+func (fake *FakeOrganizations) DeleteWithContext(ctx context.Context, name string) (r0 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Get implements OrganizationsAPI
+func (fake *FakeOrganizations) Get(name string) (r0 Organization, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetWithContext implements OrganizationsAPI
+func (fake *FakeOrganizations) GetWithContext(ctx context.Context, name string) (r0 Organization, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements OrganizationsAPI
+func (fake *FakeOrganizations) List() (r0 map[string]string, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListWithContext implements OrganizationsAPI
+func (fake *FakeOrganizations) ListWithContext(ctx context.Context) (r0 map[string]string, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// Update implements OrganizationsAPI
+func (fake *FakeOrganizations) Update(g Organization) (r0 Organization, r1 error) {
+	if fake.UpdateFunc != nil {
+		return fake.UpdateFunc(g)
+	}
+	if fake.UpdateWithContextFunc != nil {
+		return fake.UpdateWithContextFunc(context.Background(), g)
+	}
+	return
+}
+
+// UpdateWithContext implements OrganizationsAPI
+func (fake *FakeOrganizations) UpdateWithContext(ctx context.Context, g Organization) (r0 Organization, r1 error) {
+	if fake.UpdateWithContextFunc != nil {
+		return fake.UpdateWithContextFunc(ctx, g)
+	}
+	return
+}
+
+// FakePolicies is a PoliciesAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakePolicies struct {
+	DeleteFunc                        func(string) (PolicyGetResponse, error)
+	DeleteRevisionFunc                func(string, string) (RevisionDetailsResponse, error)
+	DeleteRevisionWithContextFunc     func(context.Context, string, string) (RevisionDetailsResponse, error)
+	DeleteWithContextFunc             func(context.Context, string) (PolicyGetResponse, error)
+	GetFunc                           func(string) (PolicyGetResponse, error)
+	GetRevisionDetailsFunc            func(string, string) (RevisionDetailsResponse, error)
+	GetRevisionDetailsWithContextFunc func(context.Context, string, string) (RevisionDetailsResponse, error)
+	GetWithContextFunc                func(context.Context, string) (PolicyGetResponse, error)
+	ListFunc                          func() (PoliciesGetResponse, error)
+	ListWithContextFunc               func(context.Context) (PoliciesGetResponse, error)
+}
+
+// Delete implements PoliciesAPI
+func (fake *FakePolicies) Delete(policyName string) (r0 PolicyGetResponse, r1 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(policyName)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), policyName)
+	}
+	return
+}
+
+// DeleteRevision implements PoliciesAPI
+func (fake *FakePolicies) DeleteRevision(policyName string, revisionID string) (r0 RevisionDetailsResponse, r1 error) {
+	if fake.DeleteRevisionFunc != nil {
+		return fake.DeleteRevisionFunc(policyName, revisionID)
+	}
+	if fake.DeleteRevisionWithContextFunc != nil {
+		return fake.DeleteRevisionWithContextFunc(context.Background(), policyName, revisionID)
+	}
+	return
+}
+
+// DeleteRevisionWithContext implements PoliciesAPI
+func (fake *FakePolicies) DeleteRevisionWithContext(ctx context.Context, policyName string, revisionID string) (r0 RevisionDetailsResponse, r1 error) {
+	if fake.DeleteRevisionWithContextFunc != nil {
+		return fake.DeleteRevisionWithContextFunc(ctx, policyName, revisionID)
+	}
+	return
+}
+
+// DeleteWithContext implements PoliciesAPI
+func (fake *FakePolicies) DeleteWithContext(ctx context.Context, policyName string) (r0 PolicyGetResponse, r1 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, policyName)
+	}
+	return
+}
+
+// Get implements PoliciesAPI
+func (fake *FakePolicies) Get(name string) (r0 PolicyGetResponse, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetRevisionDetails implements PoliciesAPI
+func (fake *FakePolicies) GetRevisionDetails(policyName string, revisionID string) (r0 RevisionDetailsResponse, r1 error) {
+	if fake.GetRevisionDetailsFunc != nil {
+		return fake.GetRevisionDetailsFunc(policyName, revisionID)
+	}
+	if fake.GetRevisionDetailsWithContextFunc != nil {
+		return fake.GetRevisionDetailsWithContextFunc(context.Background(), policyName, revisionID)
+	}
+	return
+}
+
+// GetRevisionDetailsWithContext implements PoliciesAPI
+func (fake *FakePolicies) GetRevisionDetailsWithContext(ctx context.Context, policyName string, revisionID string) (r0 RevisionDetailsResponse, r1 error) {
+	if fake.GetRevisionDetailsWithContextFunc != nil {
+		return fake.GetRevisionDetailsWithContextFunc(ctx, policyName, revisionID)
+	}
+	return
+}
+
+// GetWithContext implements PoliciesAPI
+func (fake *FakePolicies) GetWithContext(ctx context.Context, name string) (r0 PolicyGetResponse, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements PoliciesAPI
+func (fake *FakePolicies) List() (r0 PoliciesGetResponse, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListWithContext implements PoliciesAPI
+func (fake *FakePolicies) ListWithContext(ctx context.Context) (r0 PoliciesGetResponse, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// FakePolicyGroups is a PolicyGroupsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakePolicyGroups struct {
+	DeleteFunc                  func(string) (PolicyGroup, error)
+	DeletePolicyFunc            func(string, string) (RevisionDetailsResponse, error)
+	DeletePolicyWithContextFunc func(context.Context, string, string) (RevisionDetailsResponse, error)
+	DeleteWithContextFunc       func(context.Context, string) (PolicyGroup, error)
+	GetFunc                     func(string) (PolicyGroup, error)
+	GetPolicyFunc               func(string, string) (RevisionDetailsResponse, error)
+	GetPolicyWithContextFunc    func(context.Context, string, string) (RevisionDetailsResponse, error)
+	GetWithContextFunc          func(context.Context, string) (PolicyGroup, error)
+	ListFunc                    func() (PolicyGroupGetResponse, error)
+	ListWithContextFunc         func(context.Context) (PolicyGroupGetResponse, error)
+}
+
+// Delete implements PolicyGroupsAPI
+func (fake *FakePolicyGroups) Delete(policyGroupName string) (r0 PolicyGroup, r1 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(policyGroupName)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), policyGroupName)
+	}
+	return
+}
+
+// DeletePolicy implements PolicyGroupsAPI
+func (fake *FakePolicyGroups) DeletePolicy(policyGroupName string, policyName string) (r0 RevisionDetailsResponse, r1 error) {
+	if fake.DeletePolicyFunc != nil {
+		return fake.DeletePolicyFunc(policyGroupName, policyName)
+	}
+	if fake.DeletePolicyWithContextFunc != nil {
+		return fake.DeletePolicyWithContextFunc(context.Background(), policyGroupName, policyName)
+	}
+	return
+}
+
+// DeletePolicyWithContext implements PolicyGroupsAPI
+func (fake *FakePolicyGroups) DeletePolicyWithContext(ctx context.Context, policyGroupName string, policyName string) (r0 RevisionDetailsResponse, r1 error) {
+	if fake.DeletePolicyWithContextFunc != nil {
+		return fake.DeletePolicyWithContextFunc(ctx, policyGroupName, policyName)
+	}
+	return
+}
+
+// DeleteWithContext implements PolicyGroupsAPI
+func (fake *FakePolicyGroups) DeleteWithContext(ctx context.Context, policyGroupName string) (r0 PolicyGroup, r1 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, policyGroupName)
+	}
+	return
+}
+
+// Get implements PolicyGroupsAPI
+func (fake *FakePolicyGroups) Get(policyGroupName string) (r0 PolicyGroup, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(policyGroupName)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), policyGroupName)
+	}
+	return
+}
+
+// GetPolicy implements PolicyGroupsAPI
+func (fake *FakePolicyGroups) GetPolicy(policyGroupName string, policyName string) (r0 RevisionDetailsResponse, r1 error) {
+	if fake.GetPolicyFunc != nil {
+		return fake.GetPolicyFunc(policyGroupName, policyName)
+	}
+	if fake.GetPolicyWithContextFunc != nil {
+		return fake.GetPolicyWithContextFunc(context.Background(), policyGroupName, policyName)
+	}
+	return
+}
+
+// GetPolicyWithContext implements PolicyGroupsAPI
+func (fake *FakePolicyGroups) GetPolicyWithContext(ctx context.Context, policyGroupName string, policyName string) (r0 RevisionDetailsResponse, r1 error) {
+	if fake.GetPolicyWithContextFunc != nil {
+		return fake.GetPolicyWithContextFunc(ctx, policyGroupName, policyName)
+	}
+	return
+}
+
+// GetWithContext implements PolicyGroupsAPI
+func (fake *FakePolicyGroups) GetWithContext(ctx context.Context, policyGroupName string) (r0 PolicyGroup, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, policyGroupName)
+	}
+	return
+}
+
+// List implements PolicyGroupsAPI
+func (fake *FakePolicyGroups) List() (r0 PolicyGroupGetResponse, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListWithContext implements PolicyGroupsAPI
+func (fake *FakePolicyGroups) ListWithContext(ctx context.Context) (r0 PolicyGroupGetResponse, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// FakePrincipals is a PrincipalsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakePrincipals struct {
+	GetFunc            func(string) (Principal, error)
+	GetWithContextFunc func(context.Context, string) (Principal, error)
+}
+
+// Get implements PrincipalsAPI
+func (fake *FakePrincipals) Get(name string) (r0 Principal, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetWithContext implements PrincipalsAPI
+func (fake *FakePrincipals) GetWithContext(ctx context.Context, name string) (r0 Principal, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// FakeRequiredRecipe is a RequiredRecipeAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeRequiredRecipe struct {
+	GetFunc            func() (RequiredRecipe, error)
+	GetWithContextFunc func(context.Context) (RequiredRecipe, error)
+}
+
+// Get implements RequiredRecipeAPI
+func (fake *FakeRequiredRecipe) Get() (r0 RequiredRecipe, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc()
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background())
+	}
+	return
+}
+
+// GetWithContext implements RequiredRecipeAPI
+func (fake *FakeRequiredRecipe) GetWithContext(ctx context.Context) (r0 RequiredRecipe, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx)
+	}
+	return
+}
+
+// FakeRoles is a RolesAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeRoles struct {
+	CreateFunc                           func(*Role) (*RoleCreateResult, error)
+	CreateWithContextFunc                func(context.Context, *Role) (*RoleCreateResult, error)
+	DeleteFunc                           func(string) error
+	DeleteWithContextFunc                func(context.Context, string) error
+	GetFunc                              func(string) (*Role, error)
+	GetEnvironmentRunlistFunc            func(string, string) (EnvRunList, error)
+	GetEnvironmentRunlistWithContextFunc func(context.Context, string, string) (EnvRunList, error)
+	GetEnvironmentsFunc                  func(string) (RoleEnvironmentsResult, error)
+	GetEnvironmentsWithContextFunc       func(context.Context, string) (RoleEnvironmentsResult, error)
+	GetWithContextFunc                   func(context.Context, string) (*Role, error)
+	ListFunc                             func() (*RoleListResult, error)
+	ListWithContextFunc                  func(context.Context) (*RoleListResult, error)
+	PutFunc                              func(*Role) (*Role, error)
+	PutWithContextFunc                   func(context.Context, *Role) (*Role, error)
+}
+
+// Create implements RolesAPI
+func (fake *FakeRoles) Create(role *Role) (r0 *RoleCreateResult, r1 error) {
+	if fake.CreateFunc != nil {
+		return fake.CreateFunc(role)
+	}
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(context.Background(), role)
+	}
+	return
+}
+
+// CreateWithContext implements RolesAPI
+func (fake *FakeRoles) CreateWithContext(ctx context.Context, role *Role) (r0 *RoleCreateResult, r1 error) {
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(ctx, role)
+	}
+	return
+}
+
+// Delete implements RolesAPI
+func (fake *FakeRoles) Delete(name string) (r0 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// DeleteWithContext implements RolesAPI
+func (fake *FakeRoles) DeleteWithContext(ctx context.Context, name string) (r0 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Get implements RolesAPI
+func (fake *FakeRoles) Get(name string) (r0 *Role, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetEnvironmentRunlist implements RolesAPI
+func (fake *FakeRoles) GetEnvironmentRunlist(role string, environment string) (r0 EnvRunList, r1 error) {
+	if fake.GetEnvironmentRunlistFunc != nil {
+		return fake.GetEnvironmentRunlistFunc(role, environment)
+	}
+	if fake.GetEnvironmentRunlistWithContextFunc != nil {
+		return fake.GetEnvironmentRunlistWithContextFunc(context.Background(), role, environment)
+	}
+	return
+}
+
+// GetEnvironmentRunlistWithContext implements RolesAPI
+func (fake *FakeRoles) GetEnvironmentRunlistWithContext(ctx context.Context, role string, environment string) (r0 EnvRunList, r1 error) {
+	if fake.GetEnvironmentRunlistWithContextFunc != nil {
+		return fake.GetEnvironmentRunlistWithContextFunc(ctx, role, environment)
+	}
+	return
+}
+
+// GetEnvironments implements RolesAPI
+func (fake *FakeRoles) GetEnvironments(role string) (r0 RoleEnvironmentsResult, r1 error) {
+	if fake.GetEnvironmentsFunc != nil {
+		return fake.GetEnvironmentsFunc(role)
+	}
+	if fake.GetEnvironmentsWithContextFunc != nil {
+		return fake.GetEnvironmentsWithContextFunc(context.Background(), role)
+	}
+	return
+}
+
+// GetEnvironmentsWithContext implements RolesAPI
+func (fake *FakeRoles) GetEnvironmentsWithContext(ctx context.Context, role string) (r0 RoleEnvironmentsResult, r1 error) {
+	if fake.GetEnvironmentsWithContextFunc != nil {
+		return fake.GetEnvironmentsWithContextFunc(ctx, role)
+	}
+	return
+}
+
+// GetWithContext implements RolesAPI
+func (fake *FakeRoles) GetWithContext(ctx context.Context, name string) (r0 *Role, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements RolesAPI
+func (fake *FakeRoles) List() (r0 *RoleListResult, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc()
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background())
+	}
+	return
+}
+
+// ListWithContext implements RolesAPI
+func (fake *FakeRoles) ListWithContext(ctx context.Context) (r0 *RoleListResult, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx)
+	}
+	return
+}
+
+// Put implements RolesAPI
+func (fake *FakeRoles) Put(role *Role) (r0 *Role, r1 error) {
+	if fake.PutFunc != nil {
+		return fake.PutFunc(role)
+	}
+	if fake.PutWithContextFunc != nil {
+		return fake.PutWithContextFunc(context.Background(), role)
+	}
+	return
+}
+
+// PutWithContext implements RolesAPI
+func (fake *FakeRoles) PutWithContext(ctx context.Context, role *Role) (r0 *Role, r1 error) {
+	if fake.PutWithContextFunc != nil {
+		return fake.PutWithContextFunc(ctx, role)
+	}
+	return
+}
+
+// FakeSandboxes is a SandboxesAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeSandboxes struct {
+	PostFunc            func([]string) (SandboxPostResponse, error)
+	PostWithContextFunc func(context.Context, []string) (SandboxPostResponse, error)
+	PutFunc             func(string) (Sandbox, error)
+	PutWithContextFunc  func(context.Context, string) (Sandbox, error)
+}
+
+// Post implements SandboxesAPI
+func (fake *FakeSandboxes) Post(sums []string) (r0 SandboxPostResponse, r1 error) {
+	if fake.PostFunc != nil {
+		return fake.PostFunc(sums)
+	}
+	if fake.PostWithContextFunc != nil {
+		return fake.PostWithContextFunc(context.Background(), sums)
+	}
+	return
+}
+
+// PostWithContext implements SandboxesAPI
+func (fake *FakeSandboxes) PostWithContext(ctx context.Context, sums []string) (r0 SandboxPostResponse, r1 error) {
+	if fake.PostWithContextFunc != nil {
+		return fake.PostWithContextFunc(ctx, sums)
+	}
+	return
+}
+
+// Put implements SandboxesAPI
+func (fake *FakeSandboxes) Put(id string) (r0 Sandbox, r1 error) {
+	if fake.PutFunc != nil {
+		return fake.PutFunc(id)
+	}
+	if fake.PutWithContextFunc != nil {
+		return fake.PutWithContextFunc(context.Background(), id)
+	}
+	return
+}
+
+// PutWithContext implements SandboxesAPI
+func (fake *FakeSandboxes) PutWithContext(ctx context.Context, id string) (r0 Sandbox, r1 error) {
+	if fake.PutWithContextFunc != nil {
+		return fake.PutWithContextFunc(ctx, id)
+	}
+	return
+}
+
+// FakeSearch is a SearchAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeSearch struct {
+	ExecFunc                       func(string, string) (SearchResult, error)
+	ExecJSONFunc                   func(string, string) (JSearchResult, error)
+	ExecJSONWithContextFunc        func(context.Context, string, string) (JSearchResult, error)
+	ExecWithContextFunc            func(context.Context, string, string) (SearchResult, error)
+	IndexesFunc                    func() (map[string]string, error)
+	IndexesWithContextFunc         func(context.Context) (map[string]string, error)
+	IteratorFunc                   func(string, string, *SearchOptions) *SearchIterator
+	IteratorWithContextFunc        func(context.Context, string, string, *SearchOptions) *SearchIterator
+	NewQueryFunc                   func(string, string) (SearchQuery, error)
+	PageSizeFunc                   func(int)
+	PartialExecFunc                func(string, string, map[string]interface{}) (SearchResult, error)
+	PartialExecJSONFunc            func(string, string, map[string]interface{}) (JSearchResult, error)
+	PartialExecJSONWithContextFunc func(context.Context, string, string, map[string]interface{}) (JSearchResult, error)
+	PartialExecWithContextFunc     func(context.Context, string, string, map[string]interface{}) (SearchResult, error)
+}
+
+// Exec implements SearchAPI
+func (fake *FakeSearch) Exec(idx string, statement string) (r0 SearchResult, r1 error) {
+	if fake.ExecFunc != nil {
+		return fake.ExecFunc(idx, statement)
+	}
+	if fake.ExecWithContextFunc != nil {
+		return fake.ExecWithContextFunc(context.Background(), idx, statement)
+	}
+	return
+}
+
+// ExecJSON implements SearchAPI
+func (fake *FakeSearch) ExecJSON(idx string, statement string) (r0 JSearchResult, r1 error) {
+	if fake.ExecJSONFunc != nil {
+		return fake.ExecJSONFunc(idx, statement)
+	}
+	if fake.ExecJSONWithContextFunc != nil {
+		return fake.ExecJSONWithContextFunc(context.Background(), idx, statement)
+	}
+	return
+}
+
+// ExecJSONWithContext implements SearchAPI
+func (fake *FakeSearch) ExecJSONWithContext(ctx context.Context, idx string, statement string) (r0 JSearchResult, r1 error) {
+	if fake.ExecJSONWithContextFunc != nil {
+		return fake.ExecJSONWithContextFunc(ctx, idx, statement)
+	}
+	return
+}
+
+// ExecWithContext implements SearchAPI
+func (fake *FakeSearch) ExecWithContext(ctx context.Context, idx string, statement string) (r0 SearchResult, r1 error) {
+	if fake.ExecWithContextFunc != nil {
+		return fake.ExecWithContextFunc(ctx, idx, statement)
+	}
+	return
+}
+
+// Indexes implements SearchAPI
+func (fake *FakeSearch) Indexes() (r0 map[string]string, r1 error) {
+	if fake.IndexesFunc != nil {
+		return fake.IndexesFunc()
+	}
+	if fake.IndexesWithContextFunc != nil {
+		return fake.IndexesWithContextFunc(context.Background())
+	}
+	return
+}
+
+// IndexesWithContext implements SearchAPI
+func (fake *FakeSearch) IndexesWithContext(ctx context.Context) (r0 map[string]string, r1 error) {
+	if fake.IndexesWithContextFunc != nil {
+		return fake.IndexesWithContextFunc(ctx)
+	}
+	return
+}
+
+// Iterator implements SearchAPI
+func (fake *FakeSearch) Iterator(idx string, statement string, opts *SearchOptions) (r0 *SearchIterator) {
+	if fake.IteratorFunc != nil {
+		return fake.IteratorFunc(idx, statement, opts)
+	}
+	if fake.IteratorWithContextFunc != nil {
+		return fake.IteratorWithContextFunc(context.Background(), idx, statement, opts)
+	}
+	return
+}
+
+// IteratorWithContext implements SearchAPI
+func (fake *FakeSearch) IteratorWithContext(ctx context.Context, idx string, statement string, opts *SearchOptions) (r0 *SearchIterator) {
+	if fake.IteratorWithContextFunc != nil {
+		return fake.IteratorWithContextFunc(ctx, idx, statement, opts)
+	}
+	return
+}
+
+// NewQuery implements SearchAPI
+func (fake *FakeSearch) NewQuery(idx string, statement string) (r0 SearchQuery, r1 error) {
+	if fake.NewQueryFunc != nil {
+		return fake.NewQueryFunc(idx, statement)
+	}
+	return
+}
+
+// PageSize implements SearchAPI
+func (fake *FakeSearch) PageSize(setting int) {
+	if fake.PageSizeFunc != nil {
+		fake.PageSizeFunc(setting)
+		return
+	}
+	return
+}
+
+// PartialExec implements SearchAPI
+func (fake *FakeSearch) PartialExec(idx string, statement string, params map[string]interface{}) (r0 SearchResult, r1 error) {
+	if fake.PartialExecFunc != nil {
+		return fake.PartialExecFunc(idx, statement, params)
+	}
+	if fake.PartialExecWithContextFunc != nil {
+		return fake.PartialExecWithContextFunc(context.Background(), idx, statement, params)
+	}
+	return
+}
+
+// PartialExecJSON implements SearchAPI
+func (fake *FakeSearch) PartialExecJSON(idx string, statement string, params map[string]interface{}) (r0 JSearchResult, r1 error) {
+	if fake.PartialExecJSONFunc != nil {
+		return fake.PartialExecJSONFunc(idx, statement, params)
+	}
+	if fake.PartialExecJSONWithContextFunc != nil {
+		return fake.PartialExecJSONWithContextFunc(context.Background(), idx, statement, params)
+	}
+	return
+}
+
+// PartialExecJSONWithContext implements SearchAPI
+func (fake *FakeSearch) PartialExecJSONWithContext(ctx context.Context, idx string, statement string, params map[string]interface{}) (r0 JSearchResult, r1 error) {
+	if fake.PartialExecJSONWithContextFunc != nil {
+		return fake.PartialExecJSONWithContextFunc(ctx, idx, statement, params)
+	}
+	return
+}
+
+// PartialExecWithContext implements SearchAPI
+func (fake *FakeSearch) PartialExecWithContext(ctx context.Context, idx string, statement string, params map[string]interface{}) (r0 SearchResult, r1 error) {
+	if fake.PartialExecWithContextFunc != nil {
+		return fake.PartialExecWithContextFunc(ctx, idx, statement, params)
+	}
+	return
+}
+
+// FakeStats is a StatsAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeStats struct {
+	GetFunc            func(string, string) (Stats, error)
+	GetWithContextFunc func(context.Context, string, string) (Stats, error)
+}
+
+// Get implements StatsAPI
+func (fake *FakeStats) Get(user string, password string) (r0 Stats, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(user, password)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), user, password)
+	}
+	return
+}
+
+// GetWithContext implements StatsAPI
+func (fake *FakeStats) GetWithContext(ctx context.Context, user string, password string) (r0 Stats, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, user, password)
+	}
+	return
+}
+
+// FakeStatus is a StatusAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeStatus struct {
+	GetFunc            func() (Status, error)
+	GetWithContextFunc func(context.Context) (Status, error)
+}
+
+// Get implements StatusAPI
+func (fake *FakeStatus) Get() (r0 Status, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc()
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background())
+	}
+	return
+}
+
+// GetWithContext implements StatusAPI
+func (fake *FakeStatus) GetWithContext(ctx context.Context) (r0 Status, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx)
+	}
+	return
+}
+
+// FakeUniverse is a UniverseAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeUniverse struct {
+	GetFunc            func() (Universe, error)
+	GetWithContextFunc func(context.Context) (Universe, error)
+}
+
+// Get implements UniverseAPI
+func (fake *FakeUniverse) Get() (r0 Universe, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc()
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background())
+	}
+	return
+}
+
+// GetWithContext implements UniverseAPI
+func (fake *FakeUniverse) GetWithContext(ctx context.Context) (r0 Universe, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx)
+	}
+	return
+}
+
+// FakeUpdatedSince is a UpdatedSinceAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeUpdatedSince struct {
+	GetFunc            func(int64) ([]UpdatedSince, error)
+	GetWithContextFunc func(context.Context, int64) ([]UpdatedSince, error)
+}
+
+// Get implements UpdatedSinceAPI
+func (fake *FakeUpdatedSince) Get(sequenceId int64) (r0 []UpdatedSince, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(sequenceId)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), sequenceId)
+	}
+	return
+}
+
+// GetWithContext implements UpdatedSinceAPI
+func (fake *FakeUpdatedSince) GetWithContext(ctx context.Context, sequenceId int64) (r0 []UpdatedSince, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, sequenceId)
+	}
+	return
+}
+
+// FakeUsers is a UsersAPI for tests. Each method calls the function field of the same name.
+// A method without a context falls back to the WithContext function, called with
+// context.Background(). Methods without a function return zero values.
+type FakeUsers struct {
+	AddKeyFunc                 func(string, AccessKey) (KeyItem, error)
+	AddKeyWithContextFunc      func(context.Context, string, AccessKey) (KeyItem, error)
+	CreateFunc                 func(User) (UserResult, error)
+	CreateWithContextFunc      func(context.Context, User) (UserResult, error)
+	DeleteFunc                 func(string) error
+	DeleteKeyFunc              func(string, string) (AccessKey, error)
+	DeleteKeyWithContextFunc   func(context.Context, string, string) (AccessKey, error)
+	DeleteWithContextFunc      func(context.Context, string) error
+	GetFunc                    func(string) (User, error)
+	GetKeyFunc                 func(string, string) (AccessKey, error)
+	GetKeyWithContextFunc      func(context.Context, string, string) (AccessKey, error)
+	GetWithContextFunc         func(context.Context, string) (User, error)
+	ListFunc                   func(...string) (map[string]string, error)
+	ListKeysFunc               func(string) ([]KeyItem, error)
+	ListKeysWithContextFunc    func(context.Context, string) ([]KeyItem, error)
+	ListWithContextFunc        func(context.Context, ...string) (map[string]string, error)
+	UpdateFunc                 func(string, User) (UserResult, error)
+	UpdateKeyFunc              func(string, string, AccessKey) (AccessKey, error)
+	UpdateKeyWithContextFunc   func(context.Context, string, string, AccessKey) (AccessKey, error)
+	UpdateWithContextFunc      func(context.Context, string, User) (UserResult, error)
+	VerboseListFunc            func(...string) (map[string]UserVerboseResult, error)
+	VerboseListWithContextFunc func(context.Context, ...string) (map[string]UserVerboseResult, error)
+}
+
+// AddKey implements UsersAPI
+func (fake *FakeUsers) AddKey(name string, keyadd AccessKey) (r0 KeyItem, r1 error) {
+	if fake.AddKeyFunc != nil {
+		return fake.AddKeyFunc(name, keyadd)
+	}
+	if fake.AddKeyWithContextFunc != nil {
+		return fake.AddKeyWithContextFunc(context.Background(), name, keyadd)
+	}
+	return
+}
+
+// AddKeyWithContext implements UsersAPI
+func (fake *FakeUsers) AddKeyWithContext(ctx context.Context, name string, keyadd AccessKey) (r0 KeyItem, r1 error) {
+	if fake.AddKeyWithContextFunc != nil {
+		return fake.AddKeyWithContextFunc(ctx, name, keyadd)
+	}
+	return
+}
+
+// Create implements UsersAPI
+func (fake *FakeUsers) Create(user User) (r0 UserResult, r1 error) {
+	if fake.CreateFunc != nil {
+		return fake.CreateFunc(user)
+	}
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(context.Background(), user)
+	}
+	return
+}
+
+// CreateWithContext implements UsersAPI
+func (fake *FakeUsers) CreateWithContext(ctx context.Context, user User) (r0 UserResult, r1 error) {
+	if fake.CreateWithContextFunc != nil {
+		return fake.CreateWithContextFunc(ctx, user)
+	}
+	return
+}
+
+// Delete implements UsersAPI
+func (fake *FakeUsers) Delete(name string) (r0 error) {
+	if fake.DeleteFunc != nil {
+		return fake.DeleteFunc(name)
+	}
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// DeleteKey implements UsersAPI
+func (fake *FakeUsers) DeleteKey(name string, keyname string) (r0 AccessKey, r1 error) {
+	if fake.DeleteKeyFunc != nil {
+		return fake.DeleteKeyFunc(name, keyname)
+	}
+	if fake.DeleteKeyWithContextFunc != nil {
+		return fake.DeleteKeyWithContextFunc(context.Background(), name, keyname)
+	}
+	return
+}
+
+// DeleteKeyWithContext implements UsersAPI
+func (fake *FakeUsers) DeleteKeyWithContext(ctx context.Context, name string, keyname string) (r0 AccessKey, r1 error) {
+	if fake.DeleteKeyWithContextFunc != nil {
+		return fake.DeleteKeyWithContextFunc(ctx, name, keyname)
+	}
+	return
+}
+
+// DeleteWithContext implements UsersAPI
+func (fake *FakeUsers) DeleteWithContext(ctx context.Context, name string) (r0 error) {
+	if fake.DeleteWithContextFunc != nil {
+		return fake.DeleteWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// Get implements UsersAPI
+func (fake *FakeUsers) Get(name string) (r0 User, r1 error) {
+	if fake.GetFunc != nil {
+		return fake.GetFunc(name)
+	}
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// GetKey implements UsersAPI
+func (fake *FakeUsers) GetKey(name string, keyname string) (r0 AccessKey, r1 error) {
+	if fake.GetKeyFunc != nil {
+		return fake.GetKeyFunc(name, keyname)
+	}
+	if fake.GetKeyWithContextFunc != nil {
+		return fake.GetKeyWithContextFunc(context.Background(), name, keyname)
+	}
+	return
+}
+
+// GetKeyWithContext implements UsersAPI
+func (fake *FakeUsers) GetKeyWithContext(ctx context.Context, name string, keyname string) (r0 AccessKey, r1 error) {
+	if fake.GetKeyWithContextFunc != nil {
+		return fake.GetKeyWithContextFunc(ctx, name, keyname)
+	}
+	return
+}
+
+// GetWithContext implements UsersAPI
+func (fake *FakeUsers) GetWithContext(ctx context.Context, name string) (r0 User, r1 error) {
+	if fake.GetWithContextFunc != nil {
+		return fake.GetWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// List implements UsersAPI
+func (fake *FakeUsers) List(filters ...string) (r0 map[string]string, r1 error) {
+	if fake.ListFunc != nil {
+		return fake.ListFunc(filters...)
+	}
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(context.Background(), filters...)
+	}
+	return
+}
+
+// ListKeys implements UsersAPI
+func (fake *FakeUsers) ListKeys(name string) (r0 []KeyItem, r1 error) {
+	if fake.ListKeysFunc != nil {
+		return fake.ListKeysFunc(name)
+	}
+	if fake.ListKeysWithContextFunc != nil {
+		return fake.ListKeysWithContextFunc(context.Background(), name)
+	}
+	return
+}
+
+// ListKeysWithContext implements UsersAPI
+func (fake *FakeUsers) ListKeysWithContext(ctx context.Context, name string) (r0 []KeyItem, r1 error) {
+	if fake.ListKeysWithContextFunc != nil {
+		return fake.ListKeysWithContextFunc(ctx, name)
+	}
+	return
+}
+
+// ListWithContext implements UsersAPI
+func (fake *FakeUsers) ListWithContext(ctx context.Context, filters ...string) (r0 map[string]string, r1 error) {
+	if fake.ListWithContextFunc != nil {
+		return fake.ListWithContextFunc(ctx, filters...)
+	}
+	return
+}
+
+// Update implements UsersAPI
+func (fake *FakeUsers) Update(name string, user User) (r0 UserResult, r1 error) {
+	if fake.UpdateFunc != nil {
+		return fake.UpdateFunc(name, user)
+	}
+	if fake.UpdateWithContextFunc != nil {
+		return fake.UpdateWithContextFunc(context.Background(), name, user)
+	}
+	return
+}
+
+// UpdateKey implements UsersAPI
+func (fake *FakeUsers) UpdateKey(username string, keyname string, keyUp AccessKey) (r0 AccessKey, r1 error) {
+	if fake.UpdateKeyFunc != nil {
+		return fake.UpdateKeyFunc(username, keyname, keyUp)
+	}
+	if fake.UpdateKeyWithContextFunc != nil {
+		return fake.UpdateKeyWithContextFunc(context.Background(), username, keyname, keyUp)
+	}
+	return
+}
+
+// UpdateKeyWithContext implements UsersAPI
+func (fake *FakeUsers) UpdateKeyWithContext(ctx context.Context, username string, keyname string, keyUp AccessKey) (r0 AccessKey, r1 error) {
+	if fake.UpdateKeyWithContextFunc != nil {
+		return fake.UpdateKeyWithContextFunc(ctx, username, keyname, keyUp)
+	}
+	return
+}
+
+// UpdateWithContext implements UsersAPI
+func (fake *FakeUsers) UpdateWithContext(ctx context.Context, name string, user User) (r0 UserResult, r1 error) {
+	if fake.UpdateWithContextFunc != nil {
+		return fake.UpdateWithContextFunc(ctx, name, user)
+	}
+	return
+}
+
+// VerboseList implements UsersAPI
+func (fake *FakeUsers) VerboseList(filters ...string) (r0 map[string]UserVerboseResult, r1 error) {
+	if fake.VerboseListFunc != nil {
+		return fake.VerboseListFunc(filters...)
+	}
+	if fake.VerboseListWithContextFunc != nil {
+		return fake.VerboseListWithContextFunc(context.Background(), filters...)
+	}
+	return
+}
+
+// VerboseListWithContext implements UsersAPI
+func (fake *FakeUsers) VerboseListWithContext(ctx context.Context, filters ...string) (r0 map[string]UserVerboseResult, r1 error) {
+	if fake.VerboseListWithContextFunc != nil {
+		return fake.VerboseListWithContextFunc(ctx, filters...)
+	}
+	return
+}
+
+var (
+	_ ACLsAPI              = (*FakeACLs)(nil)
+	_ AssociationsAPI      = (*FakeAssociations)(nil)
+	_ AuthenticateUserAPI  = (*FakeAuthenticateUser)(nil)
+	_ ClientsAPI           = (*FakeClients)(nil)
+	_ ContainersAPI        = (*FakeContainers)(nil)
+	_ CookbookArtifactsAPI = (*FakeCookbookArtifacts)(nil)
+	_ CookbooksAPI         = (*FakeCookbooks)(nil)
+	_ DataBagsAPI          = (*FakeDataBags)(nil)
+	_ EnvironmentsAPI      = (*FakeEnvironments)(nil)
+	_ GroupsAPI            = (*FakeGroups)(nil)
+	_ LicenseAPI           = (*FakeLicense)(nil)
+	_ NodesAPI             = (*FakeNodes)(nil)
+	_ OrganizationsAPI     = (*FakeOrganizations)(nil)
+	_ PoliciesAPI          = (*FakePolicies)(nil)
+	_ PolicyGroupsAPI      = (*FakePolicyGroups)(nil)
+	_ PrincipalsAPI        = (*FakePrincipals)(nil)
+	_ RequiredRecipeAPI    = (*FakeRequiredRecipe)(nil)
+	_ RolesAPI             = (*FakeRoles)(nil)
+	_ SandboxesAPI         = (*FakeSandboxes)(nil)
+	_ SearchAPI            = (*FakeSearch)(nil)
+	_ StatsAPI             = (*FakeStats)(nil)
+	_ StatusAPI            = (*FakeStatus)(nil)
+	_ UniverseAPI          = (*FakeUniverse)(nil)
+	_ UpdatedSinceAPI      = (*FakeUpdatedSince)(nil)
+	_ UsersAPI             = (*FakeUsers)(nil)
+)
@@ -0,0 +1,66 @@
+package chef
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeNodes(t *testing.T) {
+	client := &Client{}
+	client.Nodes = &FakeNodes{
+		GetFunc: func(name string) (Node, error) {
+			return NewNode(name), nil
+		},
+		DeleteWithContextFunc: func(ctx context.Context, name string) error {
+			return errors.New("delete " + name)
+		},
+	}
+
+	node, err := client.Nodes.Get("web1")
+	assert.Nil(t, err)
+	assert.Equal(t, "web1", node.Name)
+
+	// without a Delete stub the WithContext stub answers
+	err = client.Nodes.Delete("web1")
+	assert.EqualError(t, err, "delete web1")
+
+	// methods without a stub return zero values
+	nodes, err := client.Nodes.List()
+	assert.Nil(t, err)
+	assert.Nil(t, nodes)
+}
+
+func TestFakeSearch(t *testing.T) {
+	client := &Client{}
+	client.Search = &FakeSearch{
+		ExecWithContextFunc: func(ctx context.Context, idx, statement string) (SearchResult, error) {
+			return SearchResult{Total: 1, Rows: []interface{}{idx + " " + statement}}, nil
+		},
+	}
+
+	res, err := client.Search.Exec("node", "name:web1")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"node name:web1"}, res.Rows)
+}
+
+func TestFakeSearchIterator(t *testing.T) {
+	// a fake without an iterator function returns a nil iterator
+	client := &Client{Search: &FakeSearch{}}
+	it := client.Search.Iterator("node", "name:web1", nil)
+	assert.False(t, it.Next())
+	assert.NotNil(t, it.Err())
+	it.Close()
+
+	_, err := SearchTyped[Node](client, "node", "name:web1")
+	assert.ErrorContains(t, err, "nil search iterator")
+	_, err = PartialSearchTyped[struct {
+		Name string `chef:"name"`
+	}](client, "node", "name:web1")
+	assert.ErrorContains(t, err, "nil search iterator")
+	for _, err := range TypedRows[Node](nil) {
+		assert.NotNil(t, err)
+	}
+}
@@ -1,17 +1,23 @@
 module github.com/go-chef/chef
 
-go 1.20
+go 1.23
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/ctdk/goiardi v0.11.10
 	github.com/davecgh/go-spew v1.1.1
 	github.com/hashicorp/go-retryablehttp v0.7.2
 	github.com/r3labs/diff v0.0.0-20191120142937-b4ed99a31f5a
 	github.com/stretchr/testify v1.8.0
+	golang.org/x/net v0.21.0
 )
 
 require (
 	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rogpeppe/go-internal v1.10.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
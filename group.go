@@ -1,6 +1,9 @@
 package chef
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type GroupService struct {
 	client *Client
@@ -36,7 +39,12 @@ type GroupResult struct {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#groups
 func (e *GroupService) List() (grouplist map[string]string, err error) {
-	err = e.client.magicRequestDecoder("GET", "groups", nil, &grouplist)
+	return e.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (e *GroupService) ListWithContext(ctx context.Context) (grouplist map[string]string, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "groups", nil, &grouplist)
 	return
 }
 
@@ -44,8 +52,13 @@ func (e *GroupService) List() (grouplist map[string]string, err error) {
 //
 // Chef API docs: http://docs.opscode.com/api_chef_server.html#id28
 func (e *GroupService) Get(name string) (group Group, err error) {
+	return e.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *GroupService) GetWithContext(ctx context.Context, name string) (group Group, err error) {
 	url := fmt.Sprintf("groups/%s", name)
-	err = e.client.magicRequestDecoder("GET", url, nil, &group)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &group)
 	return
 }
 
@@ -53,12 +66,17 @@ func (e *GroupService) Get(name string) (group Group, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#groups
 func (e *GroupService) Create(group Group) (data *GroupResult, err error) {
+	return e.CreateWithContext(context.Background(), group)
+}
+
+// CreateWithContext is Create with a context for cancellation and deadlines.
+func (e *GroupService) CreateWithContext(ctx context.Context, group Group) (data *GroupResult, err error) {
 	body, err := JSONReader(group)
 	if err != nil {
 		return
 	}
 
-	err = e.client.magicRequestDecoder("POST", "groups", body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "POST", "groups", body, &data)
 	return
 }
 
@@ -66,13 +84,18 @@ func (e *GroupService) Create(group Group) (data *GroupResult, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#groups
 func (e *GroupService) Update(g GroupUpdate) (group GroupUpdate, err error) {
+	return e.UpdateWithContext(context.Background(), g)
+}
+
+// UpdateWithContext is Update with a context for cancellation and deadlines.
+func (e *GroupService) UpdateWithContext(ctx context.Context, g GroupUpdate) (group GroupUpdate, err error) {
 	url := fmt.Sprintf("groups/%s", g.Name)
 	body, err := JSONReader(g)
 	if err != nil {
 		return
 	}
 
-	err = e.client.magicRequestDecoder("PUT", url, body, &group)
+	err = e.client.magicRequestDecoderWithContext(ctx, "PUT", url, body, &group)
 	return
 }
 
@@ -80,6 +103,11 @@ func (e *GroupService) Update(g GroupUpdate) (group GroupUpdate, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#groups
 func (e *GroupService) Delete(name string) (err error) {
-	err = e.client.magicRequestDecoder("DELETE", "groups/"+name, nil, nil)
+	return e.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (e *GroupService) DeleteWithContext(ctx context.Context, name string) (err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", "groups/"+name, nil, nil)
 	return
 }
@@ -2,6 +2,8 @@ package chef
 
 import (
 	"bytes"
+	"context"
+	"crypto"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
@@ -11,12 +13,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,22 +29,19 @@ type AuthVersion = string
 
 const (
 	AuthVersion10 AuthVersion = "1.0"
+	AuthVersion11 AuthVersion = "1.1"
 	AuthVersion13 AuthVersion = "1.3"
 )
 
 // DefaultChefVersion that we pretend to emulate
 const DefaultChefVersion = "14.0.0"
 
-// Body wraps io.Reader and adds methods for calculating hashes and detecting content
-type Body struct {
-	io.Reader
-}
-
 // AuthConfig representing a client and a private key used for encryption
 //
 //	This is embedded in the Client type
 type AuthConfig struct {
 	PrivateKey            *rsa.PrivateKey
+	Signer                Signer // signs requests in place of PrivateKey when set
 	ClientName            string
 	AuthenticationVersion AuthVersion
 	ServerVersion         string
@@ -53,31 +54,61 @@ type Client struct {
 	Client     *http.Client
 	IsWebuiKey bool
 
-	ACLs              *ACLService
-	Associations      *AssociationService
-	AuthenticateUser  *AuthenticateUserService
-	Clients           *ApiClientService
-	Containers        *ContainerService
-	CookbookArtifacts *CBAService
-	Cookbooks         *CookbookService
-	DataBags          *DataBagService
-	Environments      *EnvironmentService
-	Groups            *GroupService
-	License           *LicenseService
-	Nodes             *NodeService
-	Organizations     *OrganizationService
-	Policies          *PolicyService
-	PolicyGroups      *PolicyGroupService
-	Principals        *PrincipalService
-	RequiredRecipe    *RequiredRecipeService
-	Roles             *RoleService
-	Sandboxes         *SandboxService
-	Search            *SearchService
-	Stats             *StatsService
-	Status            *StatusService
-	Universe          *UniverseService
-	UpdatedSince      *UpdatedSinceService
-	Users             *UserService
+	// Retry controls retries of failed requests. A nil policy makes a single attempt.
+	Retry *RetryPolicy
+
+	// Logger receives request and response diagnostics. Logging is off when nil.
+	Logger *slog.Logger
+	// RequestLogLevel is the level for request and response summaries, slog.LevelDebug when nil
+	RequestLogLevel slog.Leveler
+	// BodyLogLevel is the level for redacted request and response bodies, LevelTrace when nil
+	BodyLogLevel slog.Leveler
+
+	// Observers are notified of every request attempt
+	Observers []Observer
+
+	// SearchSettings are the page size, sort order and row limit of searches, defaults are used when nil
+	SearchSettings *SearchSettings
+
+	// searchPageSize is the page size set with SearchService.PageSize, shared with the derived clients
+	searchPageSize *atomic.Int64
+
+	// limits are the rate and concurrency budgets set by Config.Limits
+	limits *limiters
+
+	// apiVersions tracks the Server API version negotiated with the server
+	apiVersions *apiVersions
+
+	// global caches the client returned by Global
+	global *globalClient
+
+	// The services of the API. Each field is an interface so tests can replace a service,
+	// for example with a FakeNodes.
+	ACLs              ACLsAPI
+	Associations      AssociationsAPI
+	AuthenticateUser  AuthenticateUserAPI
+	Clients           ClientsAPI
+	Containers        ContainersAPI
+	CookbookArtifacts CookbookArtifactsAPI
+	Cookbooks         CookbooksAPI
+	DataBags          DataBagsAPI
+	Environments      EnvironmentsAPI
+	Groups            GroupsAPI
+	License           LicenseAPI
+	Nodes             NodesAPI
+	Organizations     OrganizationsAPI
+	Policies          PoliciesAPI
+	PolicyGroups      PolicyGroupsAPI
+	Principals        PrincipalsAPI
+	RequiredRecipe    RequiredRecipeAPI
+	Roles             RolesAPI
+	Sandboxes         SandboxesAPI
+	Search            SearchAPI
+	Stats             StatsAPI
+	Status            StatusAPI
+	Universe          UniverseAPI
+	UpdatedSince      UpdatedSinceAPI
+	Users             UsersAPI
 }
 
 // Config contains the configuration options for a chef client. This structure is used primarily in the NewClient() constructor in order to setup a proper client object
@@ -88,6 +119,10 @@ type Config struct {
 	// This is the plain text private Key for the user
 	Key string
 
+	// Signer signs requests with a key held outside of this process, such as by
+	// an HSM or key agent. When set Key is ignored. See NewSigner.
+	Signer crypto.Signer
+
 	// BaseURL is the chef server URL used to connect to. If using orgs you should include your org in the url and terminate the url with a "/"
 	BaseURL string
 
@@ -97,6 +132,27 @@ type Config struct {
 	// RootCAs is a reference to x509.CertPool for TLS
 	RootCAs *x509.CertPool
 
+	// TrustedCertsDir is a knife style trusted_certs directory. Every PEM certificate in it
+	// is added to RootCAs, or to the system roots when RootCAs is nil. See FetchServerCerts.
+	TrustedCertsDir string
+
+	// ClientCertificates are presented to servers asking for a client certificate, such
+	// as a load balancer in front of the Chef server requiring mutual TLS
+	ClientCertificates []tls.Certificate
+
+	// GetClientCertificate returns the client certificate for each handshake, so it can be
+	// rotated without a new client. It takes precedence over ClientCertificates. See CertificateReloader.
+	GetClientCertificate func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
+
+	// MinTLSVersion is the lowest TLS version used, for example tls.VersionTLS12
+	MinTLSVersion uint16
+
+	// CipherSuites limits the TLS 1.0-1.2 cipher suites. TLS 1.3 suites are not configurable.
+	CipherSuites []uint16
+
+	// TLSServerName overrides the host name sent with SNI and verified in the server certificate
+	TLSServerName string
+
 	// Time to wait in seconds before giving up on a request to the server
 	Timeout int
 
@@ -112,12 +168,45 @@ type Config struct {
 	// Proxy function to be used when making requests
 	Proxy func(*http.Request) (*url.URL, error)
 
-	// Pointer to an HTTP Client to use instead of the default
+	// Pointer to an HTTP Client to use instead of the default. When its Transport is
+	// nil or an *http.Transport the TLS settings of this Config are applied to a copy.
 	Client *http.Client
 
 	// A function which wraps an existing RoundTripper.
 	// Cannot be used if Client is set.
 	RoundTripper func(http.RoundTripper) http.RoundTripper
+
+	// RetryPolicy enables retries of transient failures. Each attempt is re-signed.
+	// When nil every request is attempted once.
+	RetryPolicy *RetryPolicy
+
+	// Logger receives request and response diagnostics at runtime. Secrets such as
+	// signatures, private keys and passwords are redacted. Logging is off when nil.
+	Logger *slog.Logger
+
+	// RequestLogLevel sets the level of request and response summaries. Defaults to slog.LevelDebug.
+	// A *slog.LevelVar allows the level to be changed while the client is in use.
+	RequestLogLevel slog.Leveler
+
+	// BodyLogLevel sets the level of request and response bodies. Defaults to LevelTrace.
+	BodyLogLevel slog.Leveler
+
+	// Observers are notified of every request attempt with the logical operation,
+	// templated path, duration and outcome. Use them for metrics and tracing.
+	Observers []Observer
+
+	// Limits caps the request rate and the number of requests in flight, with
+	// optional separate budgets for search, cookbook downloads and mutating calls
+	Limits *Limits
+
+	// SearchSettings sets the page size, sort order and row limit of searches. The
+	// defaults of the Chef server are used when nil.
+	SearchSettings *SearchSettings
+
+	// APIVersion is the range of Chef Server API versions the client may use. The highest
+	// version supported by the server is picked. When nil version 1 is always requested.
+	// NewClient returns an error for ranges outside of MinAPIVersion to MaxAPIVersion.
+	APIVersion *APIVersionRange
 }
 
 /*
@@ -133,6 +222,8 @@ type ErrorResponse struct {
 	Response *http.Response // HTTP response that caused this error
 	// extracted error message converted to string if possible
 	ErrorMsg string
+	// each error message extracted from the json body
+	ErrorMsgs []string `json:"-"`
 	// json body raw byte stream from an error
 	ErrorText []byte
 }
@@ -141,51 +232,6 @@ type ErrorMsg struct {
 	Error interface{} `json:"error"`
 }
 
-// Buffer creates a  byte.Buffer copy from a io.Reader resets read on reader to 0,0
-func (body *Body) Buffer() *bytes.Buffer {
-	var b bytes.Buffer
-	if body.Reader == nil {
-		return &b
-	}
-
-	_, _ = b.ReadFrom(body.Reader)
-	_, err := body.Reader.(io.Seeker).Seek(0, 0)
-	if err != nil {
-		log.Fatal(err)
-	}
-	return &b
-}
-
-// Hash calculates the body content hash
-func (body *Body) Hash() (h string) {
-	b := body.Buffer()
-	// empty buffs should return a empty string
-	if b.Len() == 0 {
-		h = HashStr("")
-	}
-	h = HashStr(b.String())
-	return
-}
-
-// Hash256 calculates the body content hash
-func (body *Body) Hash256() (h string) {
-	b := body.Buffer()
-	// empty buffs should return a empty string
-	if b.Len() == 0 {
-		h = HashStr256("")
-	}
-	h = HashStr256(b.String())
-	return
-}
-
-// ContentType returns the content-type string of Body as detected by http.DetectContentType()
-func (body *Body) ContentType() string {
-	if json.Unmarshal(body.Buffer().Bytes(), &struct{}{}) == nil {
-		return "application/json"
-	}
-	return http.DetectContentType(body.Buffer().Bytes())
-}
-
 // Error implements the error interface method for ErrorResponse
 func (r *ErrorResponse) Error() string {
 	return fmt.Sprintf("%v %v: %d",
@@ -223,7 +269,16 @@ func (r *ErrorResponse) StatusURL() *url.URL {
 // It is a simple constructor for the Client struct intended as a easy interface for issuing
 // signed requests
 func NewClient(cfg *Config) (*Client, error) {
-	pk, err := PrivateKeyFromString([]byte(cfg.Key))
+	var (
+		pk     *rsa.PrivateKey
+		signer Signer
+		err    error
+	)
+	if cfg.Signer != nil {
+		signer, err = NewSigner(cfg.Signer)
+	} else {
+		pk, err = PrivateKeyFromString([]byte(cfg.Key))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -233,22 +288,8 @@ func NewClient(cfg *Config) (*Client, error) {
 		return nil, err
 	}
 
-	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.SkipSSL}
-	if cfg.RootCAs != nil {
-		tlsConfig.RootCAs = cfg.RootCAs
-	}
-	tr := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
-		TLSClientConfig:     tlsConfig,
-		TLSHandshakeTimeout: 10 * time.Second,
-	}
-
-	if cfg.Proxy != nil {
-		tr.Proxy = cfg.Proxy
+	if err := cfg.APIVersion.validate(); err != nil {
+		return nil, err
 	}
 
 	if cfg.AuthenticationVersion == "" {
@@ -262,6 +303,7 @@ func NewClient(cfg *Config) (*Client, error) {
 	c := &Client{
 		Auth: &AuthConfig{
 			PrivateKey:            pk,
+			Signer:                signer,
 			ClientName:            cfg.Name,
 			AuthenticationVersion: cfg.AuthenticationVersion,
 			ServerVersion:         cfg.ServerVersion,
@@ -269,22 +311,24 @@ func NewClient(cfg *Config) (*Client, error) {
 		BaseURL: baseUrl,
 	}
 
+	tlsConfig, err := cfg.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
 	if cfg.Client != nil {
 		if cfg.RoundTripper != nil {
 			return nil, errors.New("NewClient: cannot set both Client and RoundTripper")
 		}
-		c.Client = cfg.Client
-	} else {
-		tlsConfig := &tls.Config{InsecureSkipVerify: cfg.SkipSSL}
-		if cfg.RootCAs != nil {
-			tlsConfig.RootCAs = cfg.RootCAs
+		if c.Client, err = cfg.withTLS(cfg.Client); err != nil {
+			return nil, err
 		}
+	} else {
 		tr := &http.Transport{
 			Proxy: http.ProxyFromEnvironment,
-			Dial: (&net.Dialer{
+			DialContext: (&net.Dialer{
 				Timeout:   30 * time.Second,
 				KeepAlive: 30 * time.Second,
-			}).Dial,
+			}).DialContext,
 			TLSClientConfig:     tlsConfig,
 			TLSHandshakeTimeout: 10 * time.Second,
 		}
@@ -304,6 +348,26 @@ func NewClient(cfg *Config) (*Client, error) {
 		}
 	}
 	c.IsWebuiKey = cfg.IsWebuiKey
+	c.Retry = cfg.RetryPolicy
+	c.SearchSettings = cfg.SearchSettings
+	c.Logger = cfg.Logger
+	c.RequestLogLevel = cfg.RequestLogLevel
+	c.BodyLogLevel = cfg.BodyLogLevel
+	c.Observers = cfg.Observers
+	c.limits = newLimiters(cfg.Limits)
+	c.apiVersions = newAPIVersions(cfg.APIVersion)
+	c.initServices()
+	return c, nil
+}
+
+// initServices points the services at c
+func (c *Client) initServices() {
+	if c.searchPageSize == nil {
+		c.searchPageSize = new(atomic.Int64)
+	}
+	if c.global == nil {
+		c.global = new(globalClient)
+	}
 	c.ACLs = &ACLService{client: c}
 	c.AuthenticateUser = &AuthenticateUserService{client: c}
 	c.Associations = &AssociationService{client: c}
@@ -329,7 +393,6 @@ func NewClient(cfg *Config) (*Client, error) {
 	c.UpdatedSince = &UpdatedSinceService{client: c}
 	c.Universe = &UniverseService{client: c}
 	c.Users = &UserService{client: c}
-	return c, nil
 }
 
 func NewClientWithOutConfig(baseurl string) (*Client, error) {
@@ -359,21 +422,23 @@ func NewClientWithOutConfig(baseurl string) (*Client, error) {
 // basicRequestDecoder is the same code as magic RequestDecoder with the addition of a generated Authentication: Basic header
 // to the http request
 func (c *Client) basicRequestDecoder(method, path string, body io.Reader, v interface{}, user string, password string) error {
-	req, err := c.NewRequest(method, path, body)
+	return c.basicRequestDecoderWithContext(context.Background(), method, path, body, v, user, password)
+}
+
+// basicRequestDecoderWithContext is basicRequestDecoder bound to ctx
+func (c *Client) basicRequestDecoderWithContext(ctx context.Context, method, path string, body io.Reader, v interface{}, user string, password string) error {
+	req, err := c.NewRequestWithContext(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
 
 	basicAuthHeader(req, user, password)
-
-	debug("\n\nRequest: %+v \n", req)
 	res, err := c.Do(req, v)
 	if res != nil {
 		defer func() {
 			_ = res.Body.Close()
 		}()
 	}
-	debug("Response: %+v\n", res)
 	if err != nil {
 		return err
 	}
@@ -382,19 +447,22 @@ func (c *Client) basicRequestDecoder(method, path string, body io.Reader, v inte
 
 // magicRequestDecoder performs a request on an endpoint, and decodes the response into the passed in Type
 func (c *Client) magicRequestDecoder(method, path string, body io.Reader, v interface{}) error {
-	req, err := c.NewRequest(method, path, body)
+	return c.magicRequestDecoderWithContext(context.Background(), method, path, body, v)
+}
+
+// magicRequestDecoderWithContext is magicRequestDecoder bound to ctx
+func (c *Client) magicRequestDecoderWithContext(ctx context.Context, method, path string, body io.Reader, v interface{}) error {
+	req, err := c.NewRequestWithContext(ctx, method, path, body)
 	if err != nil {
 		return err
 	}
 
-	debug("\n\nRequest: %+v \n", req)
 	res, err := c.Do(req, v)
 	if res != nil {
 		defer func() {
 			_ = res.Body.Close()
 		}()
 	}
-	debug("Response: %+v\n", res)
 	if err != nil {
 		return err
 	}
@@ -403,40 +471,51 @@ func (c *Client) magicRequestDecoder(method, path string, body io.Reader, v inte
 
 // NewRequest returns a signed request  suitable for the chef server
 func (c *Client) NewRequest(method string, requestUrl string, body io.Reader) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, requestUrl, body)
+}
+
+// NewRequestWithContext returns a signed request suitable for the chef server.
+// The request is canceled when ctx is done.
+func (c *Client) NewRequestWithContext(ctx context.Context, method string, requestUrl string, body io.Reader) (*http.Request, error) {
 	relativeUrl, err := url.Parse(requestUrl)
 	if err != nil {
 		return nil, err
 	}
 	u := c.BaseURL.ResolveReference(relativeUrl)
 
+	// Hash the body and detect its Content-Type in one pass
+	reader, digest, err := prepareBody(body, c.Auth.AuthenticationVersion)
+	if err != nil {
+		return nil, err
+	}
+
 	// NewRequest uses a new value object of body
-	req, err := http.NewRequest(method, u.String(), body)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
 	if err != nil {
 		return nil, err
 	}
+	if req.ContentLength == 0 && digest.Length > 0 {
+		req.ContentLength = digest.Length
+	}
 
 	// parse and encode Querystring Values
 	values := req.URL.Query()
 	req.URL.RawQuery = values.Encode()
-	debug("Encoded url %+v\n", u)
-
-	myBody := &Body{body}
 
 	if body != nil {
-		// Detect Content-type
-		req.Header.Set("Content-Type", myBody.ContentType())
+		req.Header.Set("Content-Type", digest.ContentType)
 	}
 
-	// Calculate the body hash
 	if c.Auth.AuthenticationVersion == AuthVersion13 {
-		req.Header.Set("X-Ops-Content-Hash", myBody.Hash256())
+		req.Header.Set("X-Ops-Content-Hash", digest.Hash256)
 	} else {
-		req.Header.Set("X-Ops-Content-Hash", myBody.Hash())
+		req.Header.Set("X-Ops-Content-Hash", digest.Hash)
 	}
 
 	if c.IsWebuiKey {
 		req.Header.Set("X-Ops-Request-Source", "web")
 	}
+	req.Header.Set("X-Ops-Server-API-Version", strconv.Itoa(c.APIVersion()))
 	err = c.Auth.SignRequest(req)
 	if err != nil {
 		return nil, err
@@ -447,28 +526,38 @@ func (c *Client) NewRequest(method string, requestUrl string, body io.Reader) (*
 
 // NoAuthNewRequest returns a request  suitable for public apis
 func (c *Client) NoAuthNewRequest(method string, requestUrl string, body io.Reader) (*http.Request, error) {
+	return c.NoAuthNewRequestWithContext(context.Background(), method, requestUrl, body)
+}
+
+// NoAuthNewRequestWithContext returns a request suitable for public apis bound to ctx
+func (c *Client) NoAuthNewRequestWithContext(ctx context.Context, method string, requestUrl string, body io.Reader) (*http.Request, error) {
 	relativeUrl, err := url.Parse(requestUrl)
 	if err != nil {
 		return nil, err
 	}
 	u := c.BaseURL.ResolveReference(relativeUrl)
 
+	// Detect the Content-Type
+	reader, digest, err := prepareBody(body, "")
+	if err != nil {
+		return nil, err
+	}
+
 	// NewRequest uses a new value object of body
-	req, err := http.NewRequest(method, u.String(), body)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reader)
 	if err != nil {
 		return nil, err
 	}
+	if req.ContentLength == 0 && digest.Length > 0 {
+		req.ContentLength = digest.Length
+	}
 
 	// parse and encode Querystring Values
 	values := req.URL.Query()
 	req.URL.RawQuery = values.Encode()
-	debug("Encoded url %+v\n", u)
-
-	myBody := &Body{body}
 
 	if body != nil {
-		// Detect Content-type
-		req.Header.Set("Content-Type", myBody.ContentType())
+		req.Header.Set("Content-Type", digest.ContentType)
 	}
 	return req, nil
 }
@@ -493,138 +582,123 @@ func CheckResponse(r *http.Response) error {
 	}
 	errorResponse := &ErrorResponse{Response: r}
 	data, err := io.ReadAll(r.Body)
-	debug("Response Error Body: %+v\n", string(data))
 	if err == nil && data != nil {
 		json.Unmarshal(data, errorResponse)
 		errorResponse.ErrorText = data
-		errorResponse.ErrorMsg = extractErrorMsg(data)
+		errorResponse.ErrorMsgs = extractErrorMsgs(data)
+		errorResponse.ErrorMsg = strings.Join(errorResponse.ErrorMsgs, "\n")
 	}
 	return errorResponse
 }
 
-// extractErrorMsg makes a best faith effort to extract the error message text
+// extractErrorMsgs makes a best faith effort to extract the error message text
 // from the response body returned from the Chef Server. Error messages are
-// typically formatted in a json body as {"error": ["msg"]}
-func extractErrorMsg(data []byte) string {
+// typically formatted in a json body as {"error": ["msg"]}, every string
+// in the array is returned. A single {"error": "msg"} string is also accepted.
+func extractErrorMsgs(data []byte) (msgs []string) {
 	errorMsg := &ErrorMsg{}
 	json.Unmarshal(data, errorMsg)
 	switch t := errorMsg.Error.(type) {
 	case []interface{}:
-		// Return the string as a byte stream
-		var rmsg string
 		for _, val := range t {
-			switch inval := val.(type) {
-			case string:
-				rmsg = rmsg + inval + "\n"
-			default:
-				debug("Unknown type  %+v data %+v\n", inval, val)
+			if inval, ok := val.(string); ok {
+				if msg := strings.TrimSpace(inval); msg != "" {
+					msgs = append(msgs, msg)
+				}
 			}
-			return strings.TrimSpace(rmsg)
 		}
-	default:
-		debug("Unknown type  %+v data %+v msg %+v\n", t, string(data), errorMsg.Error)
+	case string:
+		if msg := strings.TrimSpace(t); msg != "" {
+			msgs = append(msgs, msg)
+		}
 	}
-	return ""
+	return
 }
 
 // ChefError tries to unwind a chef client err return embedded in an error
-// Unwinding allows easy access the StatusCode, StatusMethod and StatusURL functions
+// Unwinding allows easy access the StatusCode, StatusMethod and StatusURL functions.
+// Wrapped errors are searched with errors.As. cerr is nil when err does not
+// hold an *ErrorResponse.
 func ChefError(err error) (cerr *ErrorResponse, nerr error) {
 	if err == nil {
 		return cerr, err
 	}
-	if cerr, ok := err.(*ErrorResponse); ok {
+	if errors.As(err, &cerr) {
 		return cerr, err
 	}
-	return cerr, err
+	return nil, err
+}
+
+// DoWithContext is Do with the request bound to ctx. Cancelling ctx aborts the
+// request and any read of the response body.
+func (c *Client) DoWithContext(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+	return c.Do(req.WithContext(ctx), v)
 }
 
 // Do is used either internally via our magic request shite or a user may use it
 func (c *Client) Do(req *http.Request, v interface{}) (*http.Response, error) {
-	res, err := c.Client.Do(req)
+	c.logRequest(req)
+	start := time.Now()
+	lim := c.limits.forRequest(c, req)
+	res, release, err := c.roundTrip(req, lim)
+	if err == nil && c.learnAPIVersion(res) && rewindable(req) {
+		// the server refused the API version, send the request again with one it supports
+		_ = res.Body.Close()
+		release()
+		release = func() {}
+		req = req.Clone(req.Context())
+		req.Header.Set("X-Ops-Server-API-Version", strconv.Itoa(c.APIVersion()))
+		if req, err = c.rewind(req); err == nil {
+			res, release, err = c.roundTrip(req, lim)
+		}
+	}
+	// the in flight slot of the last attempt is held until its body has been read
+	defer release()
 	if err != nil {
+		c.logResponse(req, nil, start, nil, err)
 		return nil, err
 	}
 
 	// BUG(fujin) tightly coupled
 	err = CheckResponse(res)
 	if err != nil {
+		c.logResponse(req, res, start, err.(*ErrorResponse).ErrorText, err)
 		return res, err
 	}
 
-	var resBuf bytes.Buffer
-	resTee := io.TeeReader(res.Body, &resBuf)
+	resBody, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
 
 	// add the body back to the response so
 	// subsequent calls to res.Body contain data
-	res.Body = io.NopCloser(&resBuf)
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+	c.logResponse(req, res, start, resBody, err)
+	if err != nil {
+		return res, err
+	}
 
 	// no response interface specified
 	if v == nil {
-		if debug_on() {
-			// show the response body as a string
-			resbody, _ := io.ReadAll(resTee)
-			debug("Response body: %+v\n", string(resbody))
-		} else {
-			_, _ = io.ReadAll(resTee)
-		}
-		debug("No response body requested\n")
 		return res, nil
 	}
 
 	// response interface, v, is an io writer
 	if w, ok := v.(io.Writer); ok {
-		debug("Response output desired is an io Writer\n")
-		_, err = io.Copy(w, resTee)
-		return res, err
-	}
-
-	// response content-type specifies JSON encoded - decode it
-	if hasJsonContentType(res) {
-		err = json.NewDecoder(resTee).Decode(v)
-		if debug_on() {
-			// show the response body as a string
-			resbody, _ := io.ReadAll(&resBuf)
-			debug("Response body: %+v\n", string(resbody))
-			var repBuffer bytes.Buffer
-			repBuffer.Write(resbody)
-			res.Body = io.NopCloser(&repBuffer)
-		}
-		debug("Response body specifies content as JSON: %+v Err: %+v\n", v, err)
+		_, err = w.Write(resBody)
 		return res, err
 	}
 
 	// response interface, v, is type string and the content is plain text
-	if _, ok := v.(*string); ok && hasTextContentType(res) {
-		resbody, _ := io.ReadAll(resTee)
-		if err != nil {
-			return res, err
-		}
-		out := string(resbody)
-		debug("Response body parsed as string: %+v\n", out)
-		*v.(*string) = out
+	if out, ok := v.(*string); ok && hasTextContentType(res) {
+		*out = string(resBody)
 		return res, nil
 	}
 
-	// Default response: Content-Type is not JSON. Assume v is a struct and decode the response as json
-	err = json.NewDecoder(resTee).Decode(v)
-	if debug_on() {
-		// show the response body as a string
-		resbody, _ := io.ReadAll(&resBuf)
-		debug("Response body: %+v\n", string(resbody))
-		var repBuffer bytes.Buffer
-		repBuffer.Write(resbody)
-		res.Body = io.NopCloser(&repBuffer)
-	}
-	debug("Response body defaulted to JSON parsing: %+v Err: %+v\n", v, err)
+	// Content-Type is JSON, or not specified. Assume v is a struct and decode the response as json
+	err = json.NewDecoder(bytes.NewReader(resBody)).Decode(v)
 	return res, err
 }
 
-func hasJsonContentType(res *http.Response) bool {
-	contentType := res.Header.Get("Content-Type")
-	return contentType == "application/json"
-}
-
 func hasTextContentType(res *http.Response) bool {
 	contentType := res.Header.Get("Content-Type")
 	return contentType == "text/plain"
@@ -648,18 +722,27 @@ func (ac AuthConfig) SignRequest(request *http.Request) error {
 		"Method":                   request.Method,
 		"Accept":                   "application/json",
 		"X-Chef-Version":           ac.ServerVersion,
-		"X-Ops-Server-API-Version": "1",
+		"X-Ops-Server-API-Version": request.Header.Get("X-Ops-Server-API-Version"),
 		"X-Ops-Timestamp":          time.Now().UTC().Format(time.RFC3339),
 		"X-Ops-Content-Hash":       request.Header.Get("X-Ops-Content-Hash"),
 		"X-Ops-UserId":             ac.ClientName,
 		"X-Ops-Request-Source":     request.Header.Get("X-Ops-Request-Source"),
 	}
 
-	if ac.AuthenticationVersion == AuthVersion13 {
+	if vals["X-Ops-Server-API-Version"] == "" {
+		vals["X-Ops-Server-API-Version"] = "1"
+	}
+
+	switch ac.AuthenticationVersion {
+	case AuthVersion13:
 		vals["Path"] = endpoint
 		vals["X-Ops-Sign"] = "version=" + AuthVersion13
 		requestHeaders = []string{"Method", "Path", "Accept", "X-Chef-Version", "X-Ops-Server-API-Version", "X-Ops-Timestamp", "X-Ops-UserId", "X-Ops-Sign", "X-Ops-Request-Source"}
-	} else {
+	case AuthVersion11:
+		vals["Hashed Path"] = HashStr(endpoint)
+		vals["X-Ops-Sign"] = "algorithm=sha1;version=" + AuthVersion11
+		requestHeaders = []string{"Method", "Accept", "X-Chef-Version", "X-Ops-Server-API-Version", "X-Ops-Timestamp", "X-Ops-UserId", "X-Ops-Sign", "X-Ops-Request-Source"}
+	default:
 		vals["Hashed Path"] = HashStr(endpoint)
 		vals["X-Ops-Sign"] = "algorithm=sha1;version=" + AuthVersion10
 		requestHeaders = []string{"Method", "Accept", "X-Chef-Version", "X-Ops-Server-API-Version", "X-Ops-Timestamp", "X-Ops-UserId", "X-Ops-Sign", "X-Ops-Request-Source"}
@@ -673,19 +756,9 @@ func (ac AuthConfig) SignRequest(request *http.Request) error {
 	content := ac.SignatureContent(vals)
 
 	// generate signed string of headers
-	var signature []byte
-	var err error
-	if ac.AuthenticationVersion == AuthVersion13 {
-		signature, err = GenerateDigestSignature(ac.PrivateKey, content)
-		if err != nil {
-			fmt.Printf("Error from signature %+v\n", err)
-			return err
-		}
-	} else {
-		signature, err = GenerateSignature(ac.PrivateKey, content)
-		if err != nil {
-			return err
-		}
+	signature, err := ac.signer().Sign(ac.AuthenticationVersion, content)
+	if err != nil {
+		return err
 	}
 
 	// THIS IS CHEF PROTOCOL SPECIFIC
@@ -707,15 +780,21 @@ func (ac AuthConfig) SignatureContent(vals map[string]string) (content string) {
 	// The signature is very particular, the exact headers and the order they are included in the signature matter
 	var signedHeaders []string
 
-	if ac.AuthenticationVersion == AuthVersion13 {
+	switch ac.AuthenticationVersion {
+	case AuthVersion13:
 		signedHeaders = []string{"Method", "Path", "X-Ops-Content-Hash", "X-Ops-Sign", "X-Ops-Timestamp",
 			"X-Ops-UserId", "X-Ops-Server-API-Version"}
-	} else {
+	default:
 		signedHeaders = []string{"Method", "Hashed Path", "X-Ops-Content-Hash", "X-Ops-Timestamp", "X-Ops-UserId"}
 	}
 
 	for _, key := range signedHeaders {
-		content += fmt.Sprintf("%s:%s\n", key, vals[key])
+		val := vals[key]
+		// protocol 1.1 signs the SHA1 hash of the user id rather than the id itself
+		if key == "X-Ops-UserId" && ac.AuthenticationVersion == AuthVersion11 {
+			val = HashStr(val)
+		}
+		content += fmt.Sprintf("%s:%s\n", key, val)
 	}
 
 	content = strings.TrimSuffix(content, "\n")
@@ -745,7 +824,12 @@ func PrivateKeyFromString(key []byte) (*rsa.PrivateKey, error) {
 }
 
 func (c *Client) MagicRequestResponseDecoderWithOutAuth(url, method string, body io.Reader, v interface{}) error {
-	req, err := c.NoAuthNewRequest(method, url, body)
+	return c.MagicRequestResponseDecoderWithOutAuthWithContext(context.Background(), url, method, body, v)
+}
+
+// MagicRequestResponseDecoderWithOutAuthWithContext is MagicRequestResponseDecoderWithOutAuth bound to ctx
+func (c *Client) MagicRequestResponseDecoderWithOutAuthWithContext(ctx context.Context, url, method string, body io.Reader, v interface{}) error {
+	req, err := c.NoAuthNewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return err
 	}
@@ -610,6 +610,79 @@ func TestSignatureContent(t *testing.T) {
 	assert.Equal(t, expected, content, "Signature content")
 }
 
+func TestSignatureContent11(t *testing.T) {
+	pk, _ := PrivateKeyFromString([]byte(privateKeyPKCS1))
+	ac := &AuthConfig{
+		PrivateKey:            pk,
+		ClientName:            userid,
+		AuthenticationVersion: AuthVersion11,
+	}
+	vals := map[string]string{
+		"Method":                   "GET",
+		"Accept":                   "application/json",
+		"Hashed Path":              "FaX3AVJLlDDqHB7giEG/2EbBsR0=",
+		"X-Chef-Version":           DefaultChefVersion,
+		"X-Ops-Server-API-Version": "1",
+		"X-Ops-Timestamp":          "1990-12-31T15:59:60-08:00",
+		"X-Ops-UserId":             ac.ClientName,
+		"X-Ops-Content-Hash":       "Content-Hash",
+	}
+	// The user id is signed as the base64 encoded SHA1 of "tester"
+	expected := "Method:GET\nHashed Path:FaX3AVJLlDDqHB7giEG/2EbBsR0=\nX-Ops-Content-Hash:Content-Hash\nX-Ops-Timestamp:1990-12-31T15:59:60-08:00\nX-Ops-UserId:q02NKl9IChNwZ9oXEAJxzRdmB6E="
+
+	content := ac.SignatureContent(vals)
+	assert.Equal(t, expected, content, "Signature content 1.1")
+}
+
+func TestSignatureContent13(t *testing.T) {
+	pk, _ := PrivateKeyFromString([]byte(privateKeyPKCS1))
+	ac := &AuthConfig{
+		PrivateKey:            pk,
+		ClientName:            userid,
+		AuthenticationVersion: AuthVersion13,
+	}
+	vals := map[string]string{
+		"Method":                   "GET",
+		"Accept":                   "application/json",
+		"Path":                     "/organizations/clownco",
+		"X-Chef-Version":           DefaultChefVersion,
+		"X-Ops-Server-API-Version": "1",
+		"X-Ops-Sign":               "version=1.3",
+		"X-Ops-Timestamp":          "1990-12-31T15:59:60-08:00",
+		"X-Ops-UserId":             ac.ClientName,
+		"X-Ops-Content-Hash":       "Content-Hash",
+	}
+	expected := "Method:GET\nPath:/organizations/clownco\nX-Ops-Content-Hash:Content-Hash\nX-Ops-Sign:version=1.3\nX-Ops-Timestamp:1990-12-31T15:59:60-08:00\nX-Ops-UserId:tester\nX-Ops-Server-API-Version:1"
+
+	content := ac.SignatureContent(vals)
+	assert.Equal(t, expected, content, "Signature content 1.3")
+}
+
+func TestSignRequest11(t *testing.T) {
+	ac, err := makeAuthConfig(privateKeyPKCS1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac.AuthenticationVersion = AuthVersion11
+	request, _ := http.NewRequest("GET", requestURL+"/organizations/clownco", nil)
+	request.Header.Set("X-Ops-Content-Hash", HashStr(""))
+
+	err = ac.SignRequest(request)
+	assert.Nil(t, err, "Sign 1.1 request")
+	assert.Equal(t, "algorithm=sha1;version=1.1", request.Header.Get("X-Ops-Sign"))
+	assert.Equal(t, userid, request.Header.Get("X-Ops-UserId"), "user id header is not hashed")
+
+	signed, err := assembleSignedHeader(request)
+	assert.Nil(t, err)
+	decrypted, err := HeaderDecrypt(publicKeyPKCS1, signed)
+	assert.Nil(t, err, "Decrypt 1.1 signature")
+	expected := "Method:GET\nHashed Path:" + HashStr("/organizations/clownco") +
+		"\nX-Ops-Content-Hash:" + HashStr("") +
+		"\nX-Ops-Timestamp:" + request.Header.Get("X-Ops-Timestamp") +
+		"\nX-Ops-UserId:" + HashStr(userid)
+	assert.Equal(t, expected, string(decrypted), "1.1 signed content")
+}
+
 func TestRequestError(t *testing.T) {
 
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,363 @@
+// Command genapi writes the service interfaces of the chef package to api_gen.go and
+// their fakes to fake_gen.go. The services are the types Client.initServices assigns
+// to the Client fields, the interface of field Nodes is NodesAPI and its fake is
+// FakeNodes. Run it with go generate in the package directory.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// service is a Client field and the type of the service assigned to it
+type service struct {
+	field    string
+	typeName string
+	methods  []*method
+}
+
+type method struct {
+	name    string
+	params  []param
+	results []string
+}
+
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+func main() {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && !strings.HasSuffix(info.Name(), "_gen.go")
+	}, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pkg, ok := pkgs["chef"]
+	if !ok {
+		log.Fatal("genapi: run in the chef package directory")
+	}
+
+	// file names in a fixed order so the output doesn't change between runs
+	var names []string
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	services := findServices(pkg, names)
+	byType := map[string]*service{}
+	for _, s := range services {
+		byType[s.typeName] = s
+	}
+	imports := map[string]string{}
+	for _, name := range names {
+		file := pkg.Files[name]
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || !fn.Name.IsExported() {
+				continue
+			}
+			s, ok := byType[receiverType(fn.Recv.List[0].Type)]
+			if !ok {
+				continue
+			}
+			s.methods = append(s.methods, newMethod(fset, file, fn, imports))
+		}
+	}
+	for _, s := range services {
+		sort.Slice(s.methods, func(i, j int) bool { return s.methods[i].name < s.methods[j].name })
+	}
+
+	write("api_gen.go", apiSource(services, imports))
+	write("fake_gen.go", fakeSource(services, imports))
+}
+
+// findServices reads the assignments c.Field = &Type{...} of Client.initServices
+func findServices(pkg *ast.Package, names []string) []*service {
+	var services []*service
+	for _, name := range names {
+		for _, decl := range pkg.Files[name].Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != "initServices" {
+				continue
+			}
+			for _, stmt := range fn.Body.List {
+				assign, ok := stmt.(*ast.AssignStmt)
+				if !ok || len(assign.Lhs) != 1 {
+					continue
+				}
+				sel, ok := assign.Lhs[0].(*ast.SelectorExpr)
+				if !ok {
+					continue
+				}
+				unary, ok := assign.Rhs[0].(*ast.UnaryExpr)
+				if !ok {
+					continue
+				}
+				lit, ok := unary.X.(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				if ident, ok := lit.Type.(*ast.Ident); ok {
+					services = append(services, &service{field: sel.Sel.Name, typeName: ident.Name})
+				}
+			}
+		}
+	}
+	if len(services) == 0 {
+		log.Fatal("genapi: no services found in Client.initServices")
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].field < services[j].field })
+	return services
+}
+
+func receiverType(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// newMethod reads the signature of fn, adding the packages it uses to imports
+func newMethod(fset *token.FileSet, file *ast.File, fn *ast.FuncDecl, imports map[string]string) *method {
+	ast.Inspect(fn.Type, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if pkgIdent, ok := sel.X.(*ast.Ident); ok {
+			imports[pkgIdent.Name] = importPath(file, pkgIdent.Name)
+		}
+		return false
+	})
+
+	m := &method{name: fn.Name.Name}
+	for i, field := range fn.Type.Params.List {
+		typ := expr(fset, field.Type)
+		_, variadic := field.Type.(*ast.Ellipsis)
+		if len(field.Names) == 0 {
+			m.params = append(m.params, param{name: "p" + strconv.Itoa(i), typ: typ, variadic: variadic})
+		}
+		for _, name := range field.Names {
+			n := name.Name
+			if n == "_" {
+				n = "p" + strconv.Itoa(len(m.params))
+			}
+			m.params = append(m.params, param{name: n, typ: typ, variadic: variadic})
+		}
+	}
+	if fn.Type.Results != nil {
+		for _, field := range fn.Type.Results.List {
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				m.results = append(m.results, expr(fset, field.Type))
+			}
+		}
+	}
+	return m
+}
+
+func importPath(file *ast.File, name string) string {
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if imp.Name != nil && imp.Name.Name == name {
+			return path
+		}
+		if imp.Name == nil && (path == name || strings.HasSuffix(path, "/"+name)) {
+			return path
+		}
+	}
+	log.Fatalf("genapi: no import for %s in %s", name, file.Name.Name)
+	return ""
+}
+
+func expr(fset *token.FileSet, e ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, e); err != nil {
+		log.Fatal(err)
+	}
+	return buf.String()
+}
+
+func (m *method) paramList(named bool) string {
+	var parts []string
+	for _, p := range m.params {
+		if named {
+			parts = append(parts, p.name+" "+p.typ)
+		} else {
+			parts = append(parts, p.typ)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (m *method) resultList(named bool) string {
+	if len(m.results) == 0 {
+		return ""
+	}
+	var parts []string
+	for i, r := range m.results {
+		if named {
+			parts = append(parts, fmt.Sprintf("r%d %s", i, r))
+		} else {
+			parts = append(parts, r)
+		}
+	}
+	if len(parts) == 1 && !named {
+		return " " + parts[0]
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func (m *method) args() string {
+	var parts []string
+	for _, p := range m.params {
+		if p.variadic {
+			parts = append(parts, p.name+"...")
+		} else {
+			parts = append(parts, p.name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// contextVariant returns the XWithContext method taking a context and the parameters of m
+func (s *service) contextVariant(m *method) *method {
+	for _, other := range s.methods {
+		if other.name != m.name+"WithContext" || len(other.params) != len(m.params)+1 || other.params[0].typ != "context.Context" {
+			continue
+		}
+		same := strings.Join(other.results, ",") == strings.Join(m.results, ",")
+		for i, p := range m.params {
+			same = same && p.typ == other.params[i+1].typ
+		}
+		if same {
+			return other
+		}
+	}
+	return nil
+}
+
+const header = "// Code generated by go run ./internal/genapi. DO NOT EDIT.\n\npackage chef\n\n"
+
+func importBlock(imports map[string]string, need func(name string) bool) string {
+	var paths []string
+	for name, path := range imports {
+		if need(name) {
+			paths = append(paths, strconv.Quote(path))
+		}
+	}
+	if len(paths) == 0 {
+		return ""
+	}
+	sort.Strings(paths)
+	return "import (\n" + strings.Join(paths, "\n") + "\n)\n\n"
+}
+
+func apiSource(services []*service, imports map[string]string) []byte {
+	var body bytes.Buffer
+	for _, s := range services {
+		fmt.Fprintf(&body, "// %sAPI is the method set of %s, the type of Client.%s.\n", s.field, s.typeName, s.field)
+		fmt.Fprintf(&body, "// Assign another implementation such as a Fake%s to Client.%s to replace it.\n", s.field, s.field)
+		fmt.Fprintf(&body, "type %sAPI interface {\n", s.field)
+		for _, m := range s.methods {
+			fmt.Fprintf(&body, "%s(%s)%s\n", m.name, m.paramList(true), m.resultList(false))
+		}
+		fmt.Fprintf(&body, "}\n\n")
+	}
+	body.WriteString("var (\n")
+	for _, s := range services {
+		fmt.Fprintf(&body, "_ %sAPI = (*%s)(nil)\n", s.field, s.typeName)
+	}
+	body.WriteString(")\n\n")
+
+	body.WriteString("// copyServices sets the services replaced in from, the ones that aren't its own, on c\n")
+	body.WriteString("func (c *Client) copyServices(from *Client) {\n")
+	for _, s := range services {
+		fmt.Fprintf(&body, "if s, ok := from.%s.(*%s); !ok || s.client != from {\nc.%s = from.%s\n}\n", s.field, s.typeName, s.field, s.field)
+	}
+	body.WriteString("}\n")
+
+	src := header + importBlock(imports, func(name string) bool { return bytes.Contains(body.Bytes(), []byte(name+".")) }) + body.String()
+	return formatSource(src)
+}
+
+func fakeSource(services []*service, imports map[string]string) []byte {
+	var body bytes.Buffer
+	for _, s := range services {
+		fake := "Fake" + s.field
+		fmt.Fprintf(&body, "// %s is a %sAPI for tests. Each method calls the function field of the same name.\n", fake, s.field)
+		fmt.Fprintf(&body, "// A method without a context falls back to the WithContext function, called with\n")
+		fmt.Fprintf(&body, "// context.Background(). Methods without a function return zero values.\n")
+		fmt.Fprintf(&body, "type %s struct {\n", fake)
+		for _, m := range s.methods {
+			fmt.Fprintf(&body, "%sFunc func(%s)%s\n", m.name, m.paramList(false), m.resultList(false))
+		}
+		fmt.Fprintf(&body, "}\n\n")
+
+		for _, m := range s.methods {
+			fmt.Fprintf(&body, "// %s implements %sAPI\n", m.name, s.field)
+			fmt.Fprintf(&body, "func (fake *%s) %s(%s)%s {\n", fake, m.name, m.paramList(true), m.resultList(true))
+			call := func(name, args string) {
+				fmt.Fprintf(&body, "if fake.%sFunc != nil {\n", name)
+				if len(m.results) > 0 {
+					fmt.Fprintf(&body, "return fake.%sFunc(%s)\n", name, args)
+				} else {
+					fmt.Fprintf(&body, "fake.%sFunc(%s)\nreturn\n", name, args)
+				}
+				fmt.Fprintf(&body, "}\n")
+			}
+			call(m.name, m.args())
+			if ctxMethod := s.contextVariant(m); ctxMethod != nil {
+				args := "context.Background()"
+				if len(m.params) > 0 {
+					args += ", " + m.args()
+				}
+				call(ctxMethod.name, args)
+			}
+			fmt.Fprintf(&body, "return\n}\n\n")
+		}
+	}
+	body.WriteString("var (\n")
+	for _, s := range services {
+		fmt.Fprintf(&body, "_ %sAPI = (*Fake%s)(nil)\n", s.field, s.field)
+	}
+	body.WriteString(")\n")
+
+	src := header + importBlock(imports, func(name string) bool { return bytes.Contains(body.Bytes(), []byte(name+".")) }) + body.String()
+	return formatSource(src)
+}
+
+func formatSource(src string) []byte {
+	out, err := format.Source([]byte(src))
+	if err != nil {
+		log.Fatalf("genapi: %v\n%s", err, src)
+	}
+	return out
+}
+
+func write(name string, data []byte) {
+	if err := os.WriteFile(name, data, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
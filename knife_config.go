@@ -0,0 +1,327 @@
+package chef
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// KnifeConfig holds the settings read from a knife.rb or config.rb file and from a
+// profile of the ~/.chef/credentials file. Use Config to create a client from it.
+type KnifeConfig struct {
+	// Profile is the credentials profile the settings came from, if any
+	Profile string
+
+	NodeName      string
+	ChefServerURL string
+
+	// ClientKey is the path of the client key. Relative paths are resolved
+	// against the directory of the file that set them.
+	ClientKey string
+	// ClientKeyPEM is a key given inline in the credentials file
+	ClientKeyPEM string
+
+	ValidationClientName string
+	ValidationKey        string
+
+	// SSLVerifyMode is "verify_peer" or "verify_none"
+	SSLVerifyMode   string
+	TrustedCertsDir string
+
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+// errNoProfile is returned when the credentials file lacks the requested profile
+var errNoProfile = errors.New("no profile")
+
+// knifeSettings are the settings understood in knife.rb, config.rb and credentials files
+var knifeSettings = map[string]func(k *KnifeConfig, v string){
+	"node_name":              func(k *KnifeConfig, v string) { k.NodeName = v },
+	"client_name":            func(k *KnifeConfig, v string) { k.NodeName = v },
+	"chef_server_url":        func(k *KnifeConfig, v string) { k.ChefServerURL = v },
+	"client_key":             func(k *KnifeConfig, v string) { k.ClientKey = v },
+	"validation_client_name": func(k *KnifeConfig, v string) { k.ValidationClientName = v },
+	"validation_key":         func(k *KnifeConfig, v string) { k.ValidationKey = v },
+	"ssl_verify_mode":        func(k *KnifeConfig, v string) { k.SSLVerifyMode = strings.TrimPrefix(v, ":") },
+	"trusted_certs_dir":      func(k *KnifeConfig, v string) { k.TrustedCertsDir = v },
+	"http_proxy":             func(k *KnifeConfig, v string) { k.HTTPProxy = v },
+	"https_proxy":            func(k *KnifeConfig, v string) { k.HTTPSProxy = v },
+	"no_proxy":               func(k *KnifeConfig, v string) { k.NoProxy = v },
+}
+
+// knifePaths are the settings holding file or directory paths
+var knifePaths = []func(k *KnifeConfig) *string{
+	func(k *KnifeConfig) *string { return &k.ClientKey },
+	func(k *KnifeConfig) *string { return &k.ValidationKey },
+	func(k *KnifeConfig) *string { return &k.TrustedCertsDir },
+}
+
+// ReadKnifeConfig reads a knife.rb or config.rb file
+func ReadKnifeConfig(path string) (*KnifeConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKnifeConfig(string(data), path)
+}
+
+// ParseKnifeConfig parses the content of a knife.rb or config.rb file found at path.
+// Settings are given as ruby method calls and may use local variables such as
+// current_dir, string interpolation, ENV[...], File.join and File.expand_path.
+// Lines setting anything else are ignored.
+func ParseKnifeConfig(data, path string) (*KnifeConfig, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	k := &KnifeConfig{}
+	eval := &rbEval{file: path, vars: map[string]string{}}
+	for n, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		end := 0
+		for end < len(line) && isIdentByte(line[end]) {
+			end++
+		}
+		name := line[:end]
+		rest := strings.TrimSpace(line[end:])
+
+		// local variable assignment, for example current_dir = File.dirname(__FILE__)
+		if strings.HasPrefix(rest, "=") && !strings.HasPrefix(rest, "==") {
+			if v, err := eval.eval(rest[1:]); err == nil {
+				eval.vars[name] = v
+			}
+			continue
+		}
+		set, ok := knifeSettings[name]
+		if !ok {
+			continue
+		}
+		v, err := eval.eval(rest)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s: %w", path, n+1, name, err)
+		}
+		set(k, v)
+	}
+	k.resolvePaths(filepath.Dir(path))
+	return k, nil
+}
+
+// ReadCredentials reads a profile from a knife credentials file in TOML format.
+// An empty profile selects the CHEF_PROFILE environment variable, or "default".
+//
+//	[default]
+//	client_name = "barney"
+//	client_key = "barney_rubble.pem"
+//	chef_server_url = "https://api.chef.io/organizations/bedrock"
+func ReadCredentials(path, profile string) (*KnifeConfig, error) {
+	if profile == "" {
+		profile = os.Getenv("CHEF_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	var profiles map[string]map[string]interface{}
+	if _, err := toml.DecodeFile(path, &profiles); err != nil {
+		return nil, err
+	}
+	settings, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w %q", path, errNoProfile, profile)
+	}
+
+	k := &KnifeConfig{Profile: profile}
+	for name, val := range settings {
+		s, ok := val.(string)
+		if !ok {
+			continue
+		}
+		if set, ok := knifeSettings[name]; ok {
+			set(k, s)
+		}
+	}
+	if strings.HasPrefix(strings.TrimSpace(k.ClientKey), "-----BEGIN") {
+		k.ClientKeyPEM, k.ClientKey = k.ClientKey, ""
+	}
+	k.resolvePaths(filepath.Dir(path))
+	return k, nil
+}
+
+// LoadKnifeConfig finds and reads the knife configuration the way knife does. The
+// ~/.chef/credentials profile named by CHEF_PROFILE, or "default", is read first.
+// Settings from the first config.rb or knife.rb found in a .chef directory in the
+// working directory or one of its parents, or else in ~/.chef, take precedence.
+func LoadKnifeConfig() (*KnifeConfig, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	k := &KnifeConfig{}
+	found := false
+
+	credentials := filepath.Join(home, ".chef", "credentials")
+	if _, err := os.Stat(credentials); err == nil {
+		creds, err := ReadCredentials(credentials, "")
+		// a missing default profile is fine when a config file has the settings
+		if err != nil && (os.Getenv("CHEF_PROFILE") != "" || !errors.Is(err, errNoProfile)) {
+			return nil, err
+		}
+		if err == nil {
+			k, found = creds, true
+		}
+	}
+
+	if path := findKnifeConfigFile(home); path != "" {
+		rb, err := ReadKnifeConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		k.merge(rb)
+		found = true
+	}
+	if !found {
+		return nil, errors.New("no knife configuration found in .chef/config.rb, .chef/knife.rb or ~/.chef/credentials")
+	}
+	if k.TrustedCertsDir == "" {
+		k.TrustedCertsDir = filepath.Join(home, ".chef", "trusted_certs")
+	}
+	return k, nil
+}
+
+// findKnifeConfigFile returns the config.rb or knife.rb knife would use
+func findKnifeConfigFile(home string) string {
+	var dirs []string
+	if wd, err := os.Getwd(); err == nil {
+		for dir := wd; ; dir = filepath.Dir(dir) {
+			dirs = append(dirs, filepath.Join(dir, ".chef"))
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+	dirs = append(dirs, filepath.Join(home, ".chef"))
+	for _, dir := range dirs {
+		for _, name := range []string{"config.rb", "knife.rb"} {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// merge copies the settings made in other over k
+func (k *KnifeConfig) merge(other *KnifeConfig) {
+	fields := []struct{ dst, src *string }{
+		{&k.NodeName, &other.NodeName},
+		{&k.ChefServerURL, &other.ChefServerURL},
+		{&k.ClientKey, &other.ClientKey},
+		{&k.ClientKeyPEM, &other.ClientKeyPEM},
+		{&k.ValidationClientName, &other.ValidationClientName},
+		{&k.ValidationKey, &other.ValidationKey},
+		{&k.SSLVerifyMode, &other.SSLVerifyMode},
+		{&k.TrustedCertsDir, &other.TrustedCertsDir},
+		{&k.HTTPProxy, &other.HTTPProxy},
+		{&k.HTTPSProxy, &other.HTTPSProxy},
+		{&k.NoProxy, &other.NoProxy},
+	}
+	for _, f := range fields {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+	// a key file set in a config file replaces an inline credentials key
+	if other.ClientKey != "" && other.ClientKeyPEM == "" {
+		k.ClientKeyPEM = ""
+	}
+}
+
+// resolvePaths makes relative paths absolute against dir and expands ~
+func (k *KnifeConfig) resolvePaths(dir string) {
+	for _, field := range knifePaths {
+		p := field(k)
+		if *p == "" {
+			continue
+		}
+		if expanded, err := expandPath(*p, dir); err == nil {
+			*p = expanded
+		}
+	}
+}
+
+// Config returns the configuration for a client acting as NodeName with the ClientKey
+func (k *KnifeConfig) Config() (*Config, error) {
+	return k.config(k.NodeName, k.ClientKey, k.ClientKeyPEM)
+}
+
+// ValidatorConfig returns the configuration for a client acting as the validation client
+func (k *KnifeConfig) ValidatorConfig() (*Config, error) {
+	return k.config(k.ValidationClientName, k.ValidationKey, "")
+}
+
+func (k *KnifeConfig) config(name, keyPath, keyPEM string) (*Config, error) {
+	if name == "" {
+		return nil, errors.New("knife config: no client name")
+	}
+	if k.ChefServerURL == "" {
+		return nil, errors.New("knife config: no chef_server_url")
+	}
+	key := keyPEM
+	if key == "" {
+		if keyPath == "" {
+			return nil, fmt.Errorf("knife config: no key for %s", name)
+		}
+		data, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		key = string(data)
+	}
+
+	baseURL := k.ChefServerURL
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	cfg := &Config{
+		Name:            name,
+		Key:             key,
+		BaseURL:         baseURL,
+		SkipSSL:         k.SSLVerifyMode == "verify_none",
+		TrustedCertsDir: k.TrustedCertsDir,
+	}
+	if k.HTTPProxy != "" || k.HTTPSProxy != "" || k.NoProxy != "" {
+		cfg.Proxy = k.proxy()
+	}
+	return cfg, nil
+}
+
+// proxy returns a Config.Proxy function for the proxy settings. Settings left empty
+// fall back to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func (k *KnifeConfig) proxy() func(*http.Request) (*url.URL, error) {
+	pc := httpproxy.FromEnvironment()
+	if k.HTTPProxy != "" {
+		pc.HTTPProxy = k.HTTPProxy
+	}
+	if k.HTTPSProxy != "" {
+		pc.HTTPSProxy = k.HTTPSProxy
+	}
+	if k.NoProxy != "" {
+		pc.NoProxy = k.NoProxy
+	}
+	proxyFunc := pc.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
@@ -1,5 +1,7 @@
 package chef
 
+import "context"
+
 type LicenseService struct {
 	client *Client
 }
@@ -16,6 +18,11 @@ type License struct {
 //
 // https://docs.chef.io/api_chef_server/#license
 func (e *LicenseService) Get() (data License, err error) {
-	err = e.client.magicRequestDecoder("GET", "license", nil, &data)
+	return e.GetWithContext(context.Background())
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *LicenseService) GetWithContext(ctx context.Context) (data License, err error) {
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "GET", "license", nil, &data)
 	return
 }
@@ -0,0 +1,215 @@
+package chef
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LevelTrace is below slog.LevelDebug. Request and response bodies are logged at this level by default.
+const LevelTrace = slog.LevelDebug - 4
+
+// redacted replaces secrets in log output
+const redacted = "[REDACTED]"
+
+// maxLogBody limits how much of a request or response body is logged
+const maxLogBody = 64 * 1024
+
+// sensitiveHeader matches headers holding signatures or credentials
+var sensitiveHeader = regexp.MustCompile(`(?i)^(X-Ops-Authorization-\d+|Authorization)$`)
+
+// sensitiveKeys are json keys holding secrets in request and response bodies
+var sensitiveKeys = map[string]bool{
+	"private_key": true,
+	"password":    true,
+}
+
+// SensitiveKey reports whether the values of json key hold secrets, as private_key and
+// password do. RedactJSON replaces them, recorders of requests should too.
+func SensitiveKey(key string) bool {
+	return sensitiveKeys[key]
+}
+
+// requestLevel returns the level requests and responses are logged at
+func (c *Client) requestLevel() slog.Level {
+	if c.RequestLogLevel == nil {
+		return slog.LevelDebug
+	}
+	return c.RequestLogLevel.Level()
+}
+
+// bodyLevel returns the level request and response bodies are logged at
+func (c *Client) bodyLevel() slog.Level {
+	if c.BodyLogLevel == nil {
+		return LevelTrace
+	}
+	return c.BodyLogLevel.Level()
+}
+
+// logEnabled reports if the Logger is set and accepts level
+func (c *Client) logEnabled(ctx context.Context, level slog.Level) bool {
+	return c.Logger != nil && c.Logger.Enabled(ctx, level)
+}
+
+// log writes a message to the Logger, if one is set
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.logEnabled(ctx, level) {
+		c.Logger.LogAttrs(ctx, level, msg, attrs...)
+	}
+}
+
+// logRequest logs an outgoing request with secrets redacted
+func (c *Client) logRequest(req *http.Request) {
+	ctx := req.Context()
+	level := c.requestLevel()
+	if !c.logEnabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Any("headers", RedactHeaders(req.Header)),
+	}
+	c.Logger.LogAttrs(ctx, level, "chef request", attrs...)
+
+	if c.logEnabled(ctx, c.bodyLevel()) && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, maxLogBody))
+			_ = body.Close()
+			c.log(ctx, c.bodyLevel(), "chef request body", slog.String("method", req.Method), slog.String("url", req.URL.String()), slog.String("body", string(RedactJSON(data))))
+		}
+	}
+}
+
+// logResponse logs the response to req, or the error that prevented one
+func (c *Client) logResponse(req *http.Request, res *http.Response, start time.Time, body []byte, err error) {
+	ctx := req.Context()
+	level := c.requestLevel()
+	if !c.logEnabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if res != nil {
+		attrs = append(attrs, slog.Int("status", res.StatusCode))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.Logger.LogAttrs(ctx, level, "chef response", attrs...)
+
+	if len(body) > 0 && res != nil && isTextBody(res.Header.Get("Content-Type"), body) {
+		if len(body) > maxLogBody {
+			body = body[:maxLogBody]
+		}
+		c.log(ctx, c.bodyLevel(), "chef response body", slog.String("method", req.Method), slog.String("url", req.URL.String()), slog.String("body", string(RedactJSON(body))))
+	}
+}
+
+// isTextBody reports whether a body is worth logging. Cookbook files and other binary content is skipped.
+func isTextBody(contentType string, body []byte) bool {
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	return strings.HasPrefix(contentType, "application/json") || strings.HasPrefix(contentType, "text/")
+}
+
+// RedactHeaders returns a copy of h with the X-Ops-Authorization-* and Authorization values replaced
+func RedactHeaders(h http.Header) http.Header {
+	out := h.Clone()
+	for key := range out {
+		if sensitiveHeader.MatchString(key) {
+			out[key] = []string{redacted}
+		}
+	}
+	return out
+}
+
+// RedactJSON returns data with the values of private_key and password keys
+// replaced at any depth. Data that is not valid json is returned unchanged.
+func RedactJSON(data []byte) []byte {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return data
+	}
+	if !redactValue(v) {
+		return data
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// redactValue replaces secrets in a decoded json value and reports if anything changed
+func redactValue(v interface{}) (changed bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, val := range t {
+			if sensitiveKeys[key] {
+				if s, ok := val.(string); !ok || s != "" {
+					t[key] = redacted
+					changed = true
+				}
+				continue
+			}
+			changed = redactValue(val) || changed
+		}
+	case []interface{}:
+		for _, val := range t {
+			changed = redactValue(val) || changed
+		}
+	}
+	return
+}
+
+// LogValue implements slog.LogValuer so the private key is never logged
+func (k ChefKey) LogValue() slog.Value {
+	k.PrivateKey = redactString(k.PrivateKey)
+	type plain ChefKey
+	return slog.AnyValue(plain(k))
+}
+
+// LogValue implements slog.LogValuer so the embedded private key is never logged
+func (r ApiClientCreateResult) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("uri", r.Uri), slog.Any("chef_key", r.ChefKey))
+}
+
+// LogValue implements slog.LogValuer so the embedded private key is never logged
+func (r UserResult) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("uri", r.Uri), slog.Any("chef_key", r.ChefKey))
+}
+
+// LogValue implements slog.LogValuer so the private key is never logged
+func (o OrganizationResult) LogValue() slog.Value {
+	o.PrivateKey = redactString(o.PrivateKey)
+	type plain OrganizationResult
+	return slog.AnyValue(plain(o))
+}
+
+// LogValue implements slog.LogValuer so the password is never logged
+func (u User) LogValue() slog.Value {
+	u.Password = redactString(u.Password)
+	type plain User
+	return slog.AnyValue(plain(u))
+}
+
+// redactString hides non empty secrets
+func redactString(s string) string {
+	if s == "" {
+		return s
+	}
+	return redacted
+}
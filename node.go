@@ -1,6 +1,7 @@
 package chef
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -94,7 +95,12 @@ func NewNode(name string) (node Node) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#nodes
 func (e *NodeService) List() (data map[string]string, err error) {
-	err = e.client.magicRequestDecoder("GET", "nodes", nil, &data)
+	return e.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (e *NodeService) ListWithContext(ctx context.Context) (data map[string]string, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "nodes", nil, &data)
 	return
 }
 
@@ -102,8 +108,13 @@ func (e *NodeService) List() (data map[string]string, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#nodes-name
 func (e *NodeService) Get(name string) (node Node, err error) {
+	return e.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *NodeService) GetWithContext(ctx context.Context, name string) (node Node, err error) {
 	url := fmt.Sprintf("nodes/%s", name)
-	err = e.client.magicRequestDecoder("GET", url, nil, &node)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &node)
 	return
 }
 
@@ -111,8 +122,13 @@ func (e *NodeService) Get(name string) (node Node, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#nodes-name
 func (e *NodeService) Head(name string) (err error) {
+	return e.HeadWithContext(context.Background(), name)
+}
+
+// HeadWithContext is Head with a context for cancellation and deadlines.
+func (e *NodeService) HeadWithContext(ctx context.Context, name string) (err error) {
 	url := fmt.Sprintf("nodes/%s", name)
-	err = e.client.magicRequestDecoder("HEAD", url, nil, nil)
+	err = e.client.magicRequestDecoderWithContext(ctx, "HEAD", url, nil, nil)
 	return
 }
 
@@ -120,12 +136,17 @@ func (e *NodeService) Head(name string) (err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#nodes
 func (e *NodeService) Post(node Node) (data *NodeResult, err error) {
+	return e.PostWithContext(context.Background(), node)
+}
+
+// PostWithContext is Post with a context for cancellation and deadlines.
+func (e *NodeService) PostWithContext(ctx context.Context, node Node) (data *NodeResult, err error) {
 	body, err := JSONReader(node)
 	if err != nil {
 		return
 	}
 
-	err = e.client.magicRequestDecoder("POST", "nodes", body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "POST", "nodes", body, &data)
 	return
 }
 
@@ -134,13 +155,18 @@ func (e *NodeService) Post(node Node) (data *NodeResult, err error) {
 // Chef API docs: https://docs.chef.io/api_chef_server.html#nodes-name
 // TODO: We might want to change the name. name and data should be separate structures
 func (e *NodeService) Put(n Node) (node Node, err error) {
+	return e.PutWithContext(context.Background(), n)
+}
+
+// PutWithContext is Put with a context for cancellation and deadlines.
+func (e *NodeService) PutWithContext(ctx context.Context, n Node) (node Node, err error) {
 	url := fmt.Sprintf("nodes/%s", n.Name)
 	body, err := JSONReader(n)
 	if err != nil {
 		return
 	}
 
-	err = e.client.magicRequestDecoder("PUT", url, body, &node)
+	err = e.client.magicRequestDecoderWithContext(ctx, "PUT", url, body, &node)
 	return
 }
 
@@ -148,6 +174,11 @@ func (e *NodeService) Put(n Node) (node Node, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#nodes-name
 func (e *NodeService) Delete(name string) (err error) {
-	err = e.client.magicRequestDecoder("DELETE", "nodes/"+name, nil, nil)
+	return e.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (e *NodeService) DeleteWithContext(ctx context.Context, name string) (err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", "nodes/"+name, nil, nil)
 	return
 }
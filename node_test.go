@@ -1,6 +1,7 @@
 package chef
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -130,6 +131,24 @@ func TestNodesService_Methods(t *testing.T) {
 	}
 }
 
+func TestNodesService_GetWithContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/nodes/node1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name": "node1", "chef_environment": "development"}`)
+	})
+
+	node, err := client.Nodes.GetWithContext(context.Background(), "node1")
+	assert.Nil(t, err, "Nodes.GetWithContext returned error")
+	assert.Equal(t, "node1", node.Name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Nodes.GetWithContext(ctx, "node1")
+	assert.ErrorIs(t, err, context.Canceled, "Nodes.GetWithContext with a canceled context")
+}
+
 func TestGetAttribute(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,187 @@
+package chef
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestInfo describes a single attempt of a Chef API request
+type RequestInfo struct {
+	// Operation is the logical name of the call, for example "nodes.get" or "users.keys.list"
+	Operation string
+
+	// Path is the templated request path relative to the organization, for example "nodes/{name}"
+	Path string
+
+	// Method is the HTTP method
+	Method string
+
+	// Attempt counts the attempts of this request, starting at 1. See RetryPolicy.
+	Attempt int
+
+	// Duration of the attempt, set when the attempt is done
+	Duration time.Duration
+
+	// StatusCode of the response, 0 when no response was received
+	StatusCode int
+
+	// RequestBytes is the size of the request body, -1 when unknown
+	RequestBytes int64
+
+	// ResponseBytes is the size of the response body
+	ResponseBytes int64
+
+	// Err is the transport error, or the *ErrorResponse for a non 2xx response
+	Err error
+}
+
+// Observer is notified of every request attempt made by a Client. It can be used to
+// record metrics or to create tracing spans.
+type Observer interface {
+	// Start is called before each attempt. The returned context is used for the
+	// attempt and passed to Done, which allows a span to be started.
+	Start(ctx context.Context, info RequestInfo) context.Context
+
+	// Done is called when the attempt has completed and the response body has been read
+	Done(ctx context.Context, info RequestInfo)
+}
+
+// operationTemplates are the Chef API paths, relative to the organization, used to
+// name requests. Literal segments name the operation and {placeholders} match any segment.
+var operationTemplates = splitTemplates([]string{
+	"_stats", "_status", "authenticate_user", "license", "required_recipe", "universe", "updated_since",
+	"association_requests", "association_requests/{id}",
+	"clients", "clients/{name}", "clients/{name}/keys", "clients/{name}/keys/{key}",
+	"containers", "containers/{name}",
+	"cookbook_artifacts", "cookbook_artifacts/{name}", "cookbook_artifacts/{name}/{identifier}",
+	"cookbooks", "cookbooks/_latest", "cookbooks/_recipes", "cookbooks/{name}", "cookbooks/{name}/{version}",
+	"data", "data/{bag}", "data/{bag}/{item}",
+	"environments", "environments/{name}", "environments/{name}/cookbook_versions",
+	"environments/{name}/cookbooks", "environments/{name}/cookbooks/{cookbook}",
+	"environments/{name}/nodes", "environments/{name}/recipes", "environments/{name}/roles/{role}",
+	"groups", "groups/{name}",
+	"nodes", "nodes/{name}",
+	"organizations", "organizations/{name}",
+	"policies", "policies/{name}", "policies/{name}/revisions", "policies/{name}/revisions/{revision}",
+	"policy_groups", "policy_groups/{name}", "policy_groups/{name}/policies/{policy}",
+	"principals/{name}",
+	"roles", "roles/{name}", "roles/{name}/environments", "roles/{name}/environments/{environment}",
+	"sandboxes", "sandboxes/{id}",
+	"search", "search/{index}",
+	"users", "users/{name}", "users/{name}/association_requests", "users/{name}/association_requests/{id}",
+	"users/{name}/keys", "users/{name}/keys/{key}", "users/{name}/organizations",
+	"{type}/{name}/_acl", "{type}/{name}/_acl/{permission}",
+	"{type}/{name}/{sub}/{subname}/_acl", "{type}/{name}/{sub}/{subname}/_acl/{permission}",
+	"bookshelf/{*}",
+})
+
+// singletons are endpoints returning a single object rather than a collection
+var singletons = map[string]bool{
+	"authenticate_user": true,
+	"license":           true,
+	"required_recipe":   true,
+	"universe":          true,
+	"updated_since":     true,
+}
+
+func splitTemplates(templates []string) [][]string {
+	split := make([][]string, len(templates))
+	for i, t := range templates {
+		split[i] = strings.Split(t, "/")
+	}
+	return split
+}
+
+// operation returns the logical operation name and templated path of a request
+func (c *Client) operation(req *http.Request) (operation, template string) {
+	p := strings.Trim(req.URL.Path, "/")
+	if c.BaseURL != nil && (req.URL.Host == "" || req.URL.Host == c.BaseURL.Host) {
+		base := strings.Trim(c.BaseURL.Path, "/")
+		if base != "" && (p == base || strings.HasPrefix(p, base+"/")) {
+			p = strings.TrimPrefix(strings.TrimPrefix(p, base), "/")
+		}
+	}
+	segments := strings.Split(p, "/")
+	// requests from a global client to an organization endpoint
+	if len(segments) > 2 && segments[0] == "organizations" {
+		segments = segments[2:]
+	}
+	if p == "" {
+		segments = nil
+	}
+
+	for _, tmpl := range operationTemplates {
+		if !matchTemplate(tmpl, segments) {
+			continue
+		}
+		var names []string
+		for _, seg := range tmpl {
+			if !strings.HasPrefix(seg, "{") {
+				names = append(names, strings.TrimPrefix(seg, "_"))
+			}
+		}
+		last := tmpl[len(tmpl)-1]
+		collection := !strings.HasPrefix(last, "{") && !strings.HasPrefix(last, "_") && !singletons[last]
+		return strings.Join(names, ".") + "." + verb(req.Method, collection), strings.Join(tmpl, "/")
+	}
+	return "other." + verb(req.Method, true), "{other}"
+}
+
+// matchTemplate reports if the path segments match the template
+func matchTemplate(tmpl, segments []string) bool {
+	if tmpl[len(tmpl)-1] == "{*}" {
+		return len(segments) >= len(tmpl) && matchTemplate(tmpl[:len(tmpl)-1], segments[:len(tmpl)-1])
+	}
+	if len(tmpl) != len(segments) {
+		return false
+	}
+	for i, seg := range tmpl {
+		if strings.HasPrefix(seg, "{") {
+			if segments[i] == "" || strings.HasPrefix(segments[i], "_") {
+				return false
+			}
+			continue
+		}
+		if seg != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// verb names the action of an http method on a collection or an item
+func verb(method string, collection bool) string {
+	switch method {
+	case "GET":
+		if collection {
+			return "list"
+		}
+		return "get"
+	case "POST":
+		return "create"
+	case "PUT":
+		return "update"
+	}
+	return strings.ToLower(method)
+}
+
+// observeStart notifies the observers that an attempt is starting
+func (c *Client) observeStart(req *http.Request, info RequestInfo) *http.Request {
+	ctx := req.Context()
+	for _, o := range c.Observers {
+		ctx = o.Start(ctx, info)
+	}
+	if ctx != req.Context() {
+		req = req.WithContext(ctx)
+	}
+	return req
+}
+
+// observeDone notifies the observers that an attempt is done
+func (c *Client) observeDone(ctx context.Context, info RequestInfo) {
+	for _, o := range c.Observers {
+		o.Done(ctx, info)
+	}
+}
@@ -0,0 +1,117 @@
+package chef
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingObserver struct {
+	started []RequestInfo
+	done    []RequestInfo
+	ctxOK   bool
+}
+
+type observerKey struct{}
+
+func (r *recordingObserver) Start(ctx context.Context, info RequestInfo) context.Context {
+	r.started = append(r.started, info)
+	return context.WithValue(ctx, observerKey{}, info.Attempt)
+}
+
+func (r *recordingObserver) Done(ctx context.Context, info RequestInfo) {
+	r.ctxOK = ctx.Value(observerKey{}) == info.Attempt
+	r.done = append(r.done, info)
+}
+
+func TestOperation(t *testing.T) {
+	base, _ := url.Parse("https://chef.example.com/organizations/clownco/")
+	c := &Client{BaseURL: base}
+	cases := []struct {
+		method, path, operation, template string
+	}{
+		{"GET", "/organizations/clownco/nodes", "nodes.list", "nodes"},
+		{"POST", "/organizations/clownco/nodes", "nodes.create", "nodes"},
+		{"GET", "/organizations/clownco/nodes/web1", "nodes.get", "nodes/{name}"},
+		{"PUT", "/organizations/clownco/nodes/web1", "nodes.update", "nodes/{name}"},
+		{"DELETE", "/organizations/clownco/nodes/web1", "nodes.delete", "nodes/{name}"},
+		{"HEAD", "/organizations/clownco/nodes/web1", "nodes.head", "nodes/{name}"},
+		{"GET", "/organizations/clownco/cookbooks/_latest", "cookbooks.latest.get", "cookbooks/_latest"},
+		{"GET", "/organizations/clownco/cookbooks/apache/1.0.0", "cookbooks.get", "cookbooks/{name}/{version}"},
+		{"GET", "/organizations/clownco/data/bag1/item1", "data.get", "data/{bag}/{item}"},
+		{"GET", "/organizations/clownco/search/node", "search.get", "search/{index}"},
+		{"POST", "/organizations/clownco/search/node", "search.create", "search/{index}"},
+		{"GET", "/users/jdoe/keys/default", "users.keys.get", "users/{name}/keys/{key}"},
+		{"GET", "/users/jdoe/keys", "users.keys.list", "users/{name}/keys"},
+		{"PUT", "/organizations/clownco/nodes/web1/_acl/read", "acl.update", "{type}/{name}/_acl/{permission}"},
+		{"GET", "/organizations/clownco/roles/web/_acl", "acl.get", "{type}/{name}/_acl"},
+		{"GET", "/organizations/clownco/license", "license.get", "license"},
+		{"GET", "/_status", "status.get", "_status"},
+		{"GET", "/bookshelf/organization-1/checksum-2", "bookshelf.get", "bookshelf/{*}"},
+		{"GET", "/organizations/otherorg/nodes/web1", "nodes.get", "nodes/{name}"},
+		{"GET", "/unexpected/path/here/x", "other.list", "{other}"},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest(tc.method, "https://chef.example.com"+tc.path, nil)
+		op, tmpl := c.operation(req)
+		assert.Equal(t, tc.operation, op, "operation for %s %s", tc.method, tc.path)
+		assert.Equal(t, tc.template, tmpl, "template for %s %s", tc.method, tc.path)
+	}
+}
+
+func TestObserver_Attempts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	mux.HandleFunc("/nodes/node1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"name":"node1"}`)
+	})
+	mux.HandleFunc("/nodes/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":["not found"]}`, http.StatusNotFound)
+	})
+
+	obs := &recordingObserver{}
+	client.Observers = []Observer{obs}
+	client.Retry = &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+
+	node, err := client.Nodes.Get("node1")
+	assert.Nil(t, err)
+	assert.Equal(t, "node1", node.Name, "body still decoded")
+	if assert.Len(t, obs.done, 2) {
+		first, second := obs.done[0], obs.done[1]
+		assert.Equal(t, "nodes.get", first.Operation)
+		assert.Equal(t, "nodes/{name}", first.Path)
+		assert.Equal(t, 1, first.Attempt)
+		assert.Equal(t, http.StatusServiceUnavailable, first.StatusCode)
+		assert.ErrorIs(t, first.Err, ErrServerUnavailable)
+		assert.Equal(t, 2, second.Attempt)
+		assert.Equal(t, http.StatusOK, second.StatusCode)
+		assert.Equal(t, int64(len(`{"name":"node1"}`)), second.ResponseBytes)
+		assert.Nil(t, second.Err)
+		assert.True(t, second.Duration > 0)
+	}
+	assert.Len(t, obs.started, 2)
+	assert.True(t, obs.ctxOK, "context from Start passed to Done")
+
+	obs.done = nil
+	_, err = client.Nodes.Get("missing")
+	cerr, _ := ChefError(err)
+	if assert.NotNil(t, cerr) {
+		assert.Equal(t, "not found", cerr.StatusMsg(), "error body still readable")
+	}
+	if assert.Len(t, obs.done, 1) {
+		assert.ErrorIs(t, obs.done[0].Err, ErrNotFound)
+	}
+}
@@ -1,6 +1,9 @@
 package chef
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type OrganizationService struct {
 	client *Client
@@ -23,7 +26,12 @@ type OrganizationResult struct {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#organizations
 func (e *OrganizationService) List() (organizationlist map[string]string, err error) {
-	err = e.client.magicRequestDecoder("GET", "organizations", nil, &organizationlist)
+	return e.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (e *OrganizationService) ListWithContext(ctx context.Context) (organizationlist map[string]string, err error) {
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "GET", "organizations", nil, &organizationlist)
 	return
 }
 
@@ -31,8 +39,13 @@ func (e *OrganizationService) List() (organizationlist map[string]string, err er
 //
 // Chef API docs: http://docs.opscode.com/api_chef_server.html#id28
 func (e *OrganizationService) Get(name string) (organization Organization, err error) {
+	return e.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *OrganizationService) GetWithContext(ctx context.Context, name string) (organization Organization, err error) {
 	url := fmt.Sprintf("organizations/%s", name)
-	err = e.client.magicRequestDecoder("GET", url, nil, &organization)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "GET", url, nil, &organization)
 	return
 }
 
@@ -40,13 +53,18 @@ func (e *OrganizationService) Get(name string) (organization Organization, err e
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#organizations
 func (e *OrganizationService) Create(organization Organization) (data OrganizationResult, err error) {
+	return e.CreateWithContext(context.Background(), organization)
+}
+
+// CreateWithContext is Create with a context for cancellation and deadlines.
+func (e *OrganizationService) CreateWithContext(ctx context.Context, organization Organization) (data OrganizationResult, err error) {
 	body, err := JSONReader(organization)
 	if err != nil {
 		return
 	}
 
 	var orglist map[string]string
-	err = e.client.magicRequestDecoder("POST", "organizations", body, &orglist)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "POST", "organizations", body, &orglist)
 	data.ClientName = orglist["clientname"]
 	data.PrivateKey = orglist["private_key"]
 	data.Uri = orglist["uri"]
@@ -57,13 +75,18 @@ func (e *OrganizationService) Create(organization Organization) (data Organizati
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#organizations
 func (e *OrganizationService) Update(g Organization) (organization Organization, err error) {
+	return e.UpdateWithContext(context.Background(), g)
+}
+
+// UpdateWithContext is Update with a context for cancellation and deadlines.
+func (e *OrganizationService) UpdateWithContext(ctx context.Context, g Organization) (organization Organization, err error) {
 	url := fmt.Sprintf("organizations/%s", g.Name)
 	body, err := JSONReader(g)
 	if err != nil {
 		return
 	}
 
-	err = e.client.magicRequestDecoder("PUT", url, body, &organization)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "PUT", url, body, &organization)
 	return
 }
 
@@ -71,6 +94,11 @@ func (e *OrganizationService) Update(g Organization) (organization Organization,
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#organizations
 func (e *OrganizationService) Delete(name string) (err error) {
-	err = e.client.magicRequestDecoder("DELETE", "organizations/"+name, nil, nil)
+	return e.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (e *OrganizationService) DeleteWithContext(ctx context.Context, name string) (err error) {
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "DELETE", "organizations/"+name, nil, nil)
 	return
 }
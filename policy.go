@@ -1,6 +1,7 @@
 package chef
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -68,7 +69,12 @@ type SolutionDep struct {
 // Chef API docs: https://docs.chef.io/api_chef_server/#policies
 // GET /policies
 func (c *PolicyService) List() (data PoliciesGetResponse, err error) {
-	err = c.client.magicRequestDecoder("GET", "policies", nil, &data)
+	return c.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (c *PolicyService) ListWithContext(ctx context.Context) (data PoliciesGetResponse, err error) {
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", "policies", nil, &data)
 	return
 }
 
@@ -76,8 +82,13 @@ func (c *PolicyService) List() (data PoliciesGetResponse, err error) {
 //
 //	GET /policies/name
 func (c *PolicyService) Get(name string) (data PolicyGetResponse, err error) {
+	return c.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (c *PolicyService) GetWithContext(ctx context.Context, name string) (data PolicyGetResponse, err error) {
 	path := fmt.Sprintf("policies/%s", name)
-	err = c.client.magicRequestDecoder("GET", path, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -85,8 +96,13 @@ func (c *PolicyService) Get(name string) (data PolicyGetResponse, err error) {
 //
 //	DELETE /policies/name
 func (c *PolicyService) Delete(policyName string) (data PolicyGetResponse, err error) {
+	return c.DeleteWithContext(context.Background(), policyName)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (c *PolicyService) DeleteWithContext(ctx context.Context, policyName string) (data PolicyGetResponse, err error) {
 	path := fmt.Sprintf("policies/%s", policyName)
-	err = c.client.magicRequestDecoder("DELETE", path, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "DELETE", path, nil, &data)
 	return
 }
 
@@ -94,8 +110,13 @@ func (c *PolicyService) Delete(policyName string) (data PolicyGetResponse, err e
 //
 //	GET /policies/<policy-name>/revisions/<revision-id>
 func (c *PolicyService) GetRevisionDetails(policyName string, revisionID string) (data RevisionDetailsResponse, err error) {
+	return c.GetRevisionDetailsWithContext(context.Background(), policyName, revisionID)
+}
+
+// GetRevisionDetailsWithContext is GetRevisionDetails with a context for cancellation and deadlines.
+func (c *PolicyService) GetRevisionDetailsWithContext(ctx context.Context, policyName string, revisionID string) (data RevisionDetailsResponse, err error) {
 	path := fmt.Sprintf("policies/%s/revisions/%s", policyName, revisionID)
-	err = c.client.magicRequestDecoder("GET", path, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -103,8 +124,13 @@ func (c *PolicyService) GetRevisionDetails(policyName string, revisionID string)
 //
 //	GET /policies/<policy-name>/revisions/<revision-id>
 func (c *PolicyService) DeleteRevision(policyName string, revisionID string) (data RevisionDetailsResponse, err error) {
+	return c.DeleteRevisionWithContext(context.Background(), policyName, revisionID)
+}
+
+// DeleteRevisionWithContext is DeleteRevision with a context for cancellation and deadlines.
+func (c *PolicyService) DeleteRevisionWithContext(ctx context.Context, policyName string, revisionID string) (data RevisionDetailsResponse, err error) {
 	path := fmt.Sprintf("policies/%s/revisions/%s", policyName, revisionID)
-	err = c.client.magicRequestDecoder("DELETE", path, nil, &data)
+	err = c.client.magicRequestDecoderWithContext(ctx, "DELETE", path, nil, &data)
 	return
 }
 
@@ -1,6 +1,7 @@
 package chef
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -22,7 +23,12 @@ type Revision map[string]string
 // List lists the policy groups in the Chef server.
 // Chef API docs: https://docs.chef.io/api_chef_server/#policy_groups
 func (e *PolicyGroupService) List() (data PolicyGroupGetResponse, err error) {
-	err = e.client.magicRequestDecoder("GET", "policy_groups", nil, &data)
+	return e.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (e *PolicyGroupService) ListWithContext(ctx context.Context) (data PolicyGroupGetResponse, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "policy_groups", nil, &data)
 	return
 }
 
@@ -30,8 +36,13 @@ func (e *PolicyGroupService) List() (data PolicyGroupGetResponse, err error) {
 // GET /policy_groups/GROUP
 // Chef API docs: https://docs.chef.io/api_chef_server/#policy_groups
 func (e *PolicyGroupService) Get(policyGroupName string) (data PolicyGroup, err error) {
+	return e.GetWithContext(context.Background(), policyGroupName)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *PolicyGroupService) GetWithContext(ctx context.Context, policyGroupName string) (data PolicyGroup, err error) {
 	url := fmt.Sprintf("policy_groups/%s", policyGroupName)
-	err = e.client.magicRequestDecoder("GET", url, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &data)
 	return
 }
 
@@ -39,8 +50,13 @@ func (e *PolicyGroupService) Get(policyGroupName string) (data PolicyGroup, err
 // DELETE /policy_groups/GROUP
 // Chef API docs: https://docs.chef.io/api_chef_server/#policy_groups
 func (e *PolicyGroupService) Delete(policyGroupName string) (data PolicyGroup, err error) {
+	return e.DeleteWithContext(context.Background(), policyGroupName)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (e *PolicyGroupService) DeleteWithContext(ctx context.Context, policyGroupName string) (data PolicyGroup, err error) {
 	url := fmt.Sprintf("policy_groups/%s", policyGroupName)
-	err = e.client.magicRequestDecoder("DELETE", url, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", url, nil, &data)
 	return
 }
 
@@ -48,8 +64,13 @@ func (e *PolicyGroupService) Delete(policyGroupName string) (data PolicyGroup, e
 // GET /policy_groups/GROUP/policies/NAME
 // Chef API docs: https://docs.chef.io/api_chef_server/#policy_groups
 func (e *PolicyGroupService) GetPolicy(policyGroupName string, policyName string) (data RevisionDetailsResponse, err error) {
+	return e.GetPolicyWithContext(context.Background(), policyGroupName, policyName)
+}
+
+// GetPolicyWithContext is GetPolicy with a context for cancellation and deadlines.
+func (e *PolicyGroupService) GetPolicyWithContext(ctx context.Context, policyGroupName string, policyName string) (data RevisionDetailsResponse, err error) {
 	url := fmt.Sprintf("policy_groups/%s/policies/%s", policyGroupName, policyName)
-	err = e.client.magicRequestDecoder("GET", url, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &data)
 	return
 }
 
@@ -57,8 +78,13 @@ func (e *PolicyGroupService) GetPolicy(policyGroupName string, policyName string
 // DELETE /policy_groups/GROUP/policies/NAME
 // Chef API docs: https://docs.chef.io/api_chef_server/#policy_groups
 func (e *PolicyGroupService) DeletePolicy(policyGroupName string, policyName string) (data RevisionDetailsResponse, err error) {
+	return e.DeletePolicyWithContext(context.Background(), policyGroupName, policyName)
+}
+
+// DeletePolicyWithContext is DeletePolicy with a context for cancellation and deadlines.
+func (e *PolicyGroupService) DeletePolicyWithContext(ctx context.Context, policyGroupName string, policyName string) (data RevisionDetailsResponse, err error) {
 	url := fmt.Sprintf("policy_groups/%s/policies/%s", policyGroupName, policyName)
-	err = e.client.magicRequestDecoder("DELETE", url, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", url, nil, &data)
 	return
 }
 
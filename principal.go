@@ -1,6 +1,9 @@
 package chef
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type PrincipalService struct {
 	client *Client
@@ -23,7 +26,12 @@ type Principals struct {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#principals-name
 func (e *PrincipalService) Get(name string) (principal Principal, err error) {
+	return e.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *PrincipalService) GetWithContext(ctx context.Context, name string) (principal Principal, err error) {
 	url := fmt.Sprintf("principals/%s", name)
-	err = e.client.magicRequestDecoder("GET", url, nil, &principal)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &principal)
 	return
 }
@@ -0,0 +1,182 @@
+package chef
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limit is a request budget. A zero Limit allows everything.
+type Limit struct {
+	// Rate is the sustained number of requests per second. Zero means no rate limit.
+	Rate float64
+
+	// Burst is the number of requests allowed at once before Rate applies. Defaults to 1.
+	Burst int
+
+	// MaxInFlight caps the number of requests waiting for a response. Zero means no cap.
+	MaxInFlight int
+}
+
+// Limits configures client side rate limiting and concurrency caps, enforced by Client.Do.
+//
+// Search, cookbook file downloads and mutating (POST, PUT, PATCH, DELETE) requests
+// can be given their own budget. A request with its own budget does not use the
+// Default budget, so a runaway batch of one kind can't starve the rest.
+// Requests without a budget of their own use Default.
+//
+// Every attempt made under a RetryPolicy uses a token of the Rate and takes an in
+// flight slot. The slot is released during the backoff between attempts and held
+// until the body of the last response has been read.
+type Limits struct {
+	Default  Limit
+	Search   *Limit
+	Download *Limit
+	Mutating *Limit
+}
+
+// requestClass groups requests sharing a budget
+type requestClass int
+
+const (
+	classDefault requestClass = iota
+	classSearch
+	classDownload
+	classMutating
+)
+
+type requestClassKey struct{}
+
+// withRequestClass marks the requests made with ctx as belonging to class
+func withRequestClass(ctx context.Context, class requestClass) context.Context {
+	return context.WithValue(ctx, requestClassKey{}, class)
+}
+
+// limiters holds the state of the budgets configured by Limits
+type limiters struct {
+	byClass map[requestClass]*limiter
+}
+
+// newLimiters returns the limiters for l, nil when l is nil
+func newLimiters(l *Limits) *limiters {
+	if l == nil {
+		return nil
+	}
+	def := newLimiter(l.Default)
+	ls := &limiters{byClass: map[requestClass]*limiter{
+		classDefault:  def,
+		classSearch:   def,
+		classDownload: def,
+		classMutating: def,
+	}}
+	if l.Search != nil {
+		ls.byClass[classSearch] = newLimiter(*l.Search)
+	}
+	if l.Download != nil {
+		ls.byClass[classDownload] = newLimiter(*l.Download)
+	}
+	if l.Mutating != nil {
+		ls.byClass[classMutating] = newLimiter(*l.Mutating)
+	}
+	return ls
+}
+
+// forRequest returns the limiter req is subject to
+func (ls *limiters) forRequest(c *Client, req *http.Request) *limiter {
+	if ls == nil {
+		return nil
+	}
+	return ls.byClass[c.requestClass(req)]
+}
+
+// requestClass classifies req by the budget it uses
+func (c *Client) requestClass(req *http.Request) requestClass {
+	if class, ok := req.Context().Value(requestClassKey{}).(requestClass); ok {
+		return class
+	}
+	_, template := c.operation(req)
+	switch template {
+	case "search", "search/{index}":
+		return classSearch
+	case "bookshelf/{*}":
+		return classDownload
+	}
+	switch req.Method {
+	case "POST", "PUT", "PATCH", "DELETE":
+		return classMutating
+	}
+	return classDefault
+}
+
+// limiter is a token bucket combined with a cap on requests in flight
+type limiter struct {
+	slots chan struct{}
+
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(l Limit) *limiter {
+	lim := &limiter{rate: l.Rate, burst: float64(l.Burst)}
+	if lim.burst < 1 {
+		lim.burst = 1
+	}
+	lim.tokens = lim.burst
+	if l.MaxInFlight > 0 {
+		lim.slots = make(chan struct{}, l.MaxInFlight)
+	}
+	return lim
+}
+
+// acquire waits for an in flight slot. The returned function releases it.
+func (l *limiter) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil || l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// wait blocks until the rate allows another request
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil || l.rate <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	// take the token now, going into debt if needed, and wait for the debt to be repaid
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// hand back the unused token
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
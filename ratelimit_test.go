@@ -0,0 +1,163 @@
+package chef
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestClass(t *testing.T) {
+	setup()
+	defer teardown()
+
+	cases := []struct {
+		method, path string
+		class        requestClass
+	}{
+		{"GET", "/nodes/node1", classDefault},
+		{"PUT", "/nodes/node1", classMutating},
+		{"DELETE", "/roles/web", classMutating},
+		{"GET", "/search/node?q=*:*", classSearch},
+		{"POST", "/search/node?q=*:*", classSearch},
+		{"GET", "/bookshelf/organization-1/checksum-2", classDownload},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest(tc.method, server.URL+tc.path, nil)
+		assert.Equal(t, tc.class, client.requestClass(req), "%s %s", tc.method, tc.path)
+	}
+
+	req, _ := http.NewRequestWithContext(withRequestClass(context.Background(), classDownload), "GET", "https://s3.example.com/file", nil)
+	assert.Equal(t, classDownload, client.requestClass(req), "class from the context")
+}
+
+func TestLimits_MaxInFlight(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var inFlight, peak int32
+	mux.HandleFunc("/nodes/node1", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		fmt.Fprint(w, `{"name":"node1"}`)
+	})
+	client.limits = newLimiters(&Limits{Default: Limit{MaxInFlight: 2}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Nodes.Get("node1")
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak))
+}
+
+func TestLimits_MaxInFlightRetry(t *testing.T) {
+	setup()
+	defer teardown()
+
+	failed := make(chan struct{})
+	var attempts int32
+	mux.HandleFunc("/nodes/flaky", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			close(failed)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"name":"flaky"}`)
+	})
+	mux.HandleFunc("/nodes/node1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"node1"}`)
+	})
+	client.limits = newLimiters(&Limits{Default: Limit{MaxInFlight: 1}})
+	client.Retry = &RetryPolicy{MaxAttempts: 2, MinBackoff: 400 * time.Millisecond, MaxBackoff: 400 * time.Millisecond}
+
+	done := make(chan error)
+	go func() {
+		_, err := client.Nodes.Get("flaky")
+		done <- err
+	}()
+	<-failed
+
+	// the slot is free while the flaky request waits to retry
+	start := time.Now()
+	_, err := client.Nodes.Get("node1")
+	assert.Nil(t, err)
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+
+	assert.Nil(t, <-done)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
+
+func TestLimits_Rate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/nodes/node1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"node1"}`)
+	})
+	client.limits = newLimiters(&Limits{Default: Limit{Rate: 50, Burst: 2}})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		_, err := client.Nodes.Get("node1")
+		assert.Nil(t, err)
+	}
+	// two requests from the burst then four at 20ms intervals
+	assert.True(t, time.Since(start) >= 70*time.Millisecond, "elapsed %s", time.Since(start))
+}
+
+func TestLimits_SeparateBudgets(t *testing.T) {
+	setup()
+	defer teardown()
+
+	block := make(chan struct{})
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		<-block
+		fmt.Fprint(w, `{"total":0,"start":0,"rows":[]}`)
+	})
+	mux.HandleFunc("/nodes/node1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"node1"}`)
+	})
+	client.limits = newLimiters(&Limits{
+		Default: Limit{MaxInFlight: 1},
+		Search:  &Limit{MaxInFlight: 1},
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err := client.Search.Exec("node", "*:*")
+		done <- err
+	}()
+	// wait for the search to hold the only search slot
+	for len(client.limits.byClass[classSearch].slots) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := client.Nodes.Get("node1")
+	assert.Nil(t, err, "node requests use the default budget")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.Search.ExecWithContext(ctx, "node", "*:*")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "second search waits for the search budget")
+
+	close(block)
+	assert.Nil(t, <-done)
+}
@@ -0,0 +1,313 @@
+package chef
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// rbEval evaluates the small subset of ruby found in knife.rb and config.rb files:
+// string and symbol literals with #{} interpolation, local variables, string
+// concatenation, ENV[...], ENV.fetch, File.join, File.expand_path, File.dirname,
+// __FILE__, __dir__ and Dir.home.
+type rbEval struct {
+	// file is the path of the config file, the value of __FILE__
+	file string
+	// vars holds the local variables assigned so far
+	vars map[string]string
+
+	src string
+	pos int
+}
+
+// errRbSyntax reports an expression rbEval doesn't understand
+var errRbSyntax = errors.New("unsupported ruby expression")
+
+// eval evaluates src as a single expression. Anything but a comment after it is an error.
+func (e *rbEval) eval(src string) (string, error) {
+	sub := &rbEval{file: e.file, vars: e.vars, src: src}
+	v, err := sub.expr()
+	if err != nil {
+		return "", err
+	}
+	sub.skipSpace()
+	if sub.pos < len(sub.src) && sub.src[sub.pos] != '#' {
+		return "", fmt.Errorf("%w: unexpected %q", errRbSyntax, sub.src[sub.pos:])
+	}
+	return v, nil
+}
+
+// expr parses a primary value followed by any number of "+ primary"
+func (e *rbEval) expr() (string, error) {
+	v, err := e.primary()
+	if err != nil {
+		return "", err
+	}
+	for {
+		e.skipSpace()
+		if !e.consume('+') {
+			return v, nil
+		}
+		next, err := e.primary()
+		if err != nil {
+			return "", err
+		}
+		v += next
+	}
+}
+
+func (e *rbEval) primary() (string, error) {
+	e.skipSpace()
+	if e.pos >= len(e.src) {
+		return "", fmt.Errorf("%w: missing value", errRbSyntax)
+	}
+	switch c := e.src[e.pos]; {
+	case c == '"':
+		return e.doubleQuoted()
+	case c == '\'':
+		return e.singleQuoted()
+	case c == ':':
+		e.pos++
+		return e.ident(), nil
+	case c == '(':
+		e.pos++
+		v, err := e.expr()
+		if err != nil {
+			return "", err
+		}
+		e.skipSpace()
+		if !e.consume(')') {
+			return "", fmt.Errorf("%w: missing )", errRbSyntax)
+		}
+		return v, nil
+	case isIdentByte(c):
+		return e.call(e.ident())
+	}
+	return "", fmt.Errorf("%w: unexpected %q", errRbSyntax, e.src[e.pos:])
+}
+
+// call evaluates a name: a variable, a constant or one of the supported methods
+func (e *rbEval) call(name string) (string, error) {
+	switch name {
+	case "nil":
+		return "", nil
+	case "true", "false":
+		return name, nil
+	case "__FILE__":
+		return e.file, nil
+	case "__dir__":
+		return filepath.Dir(e.file), nil
+	case "Dir.home":
+		return os.UserHomeDir()
+	case "ENV":
+		e.skipSpace()
+		if !e.consume('[') {
+			return "", fmt.Errorf("%w: ENV must be indexed", errRbSyntax)
+		}
+		key, err := e.expr()
+		if err != nil {
+			return "", err
+		}
+		e.skipSpace()
+		if !e.consume(']') {
+			return "", fmt.Errorf("%w: missing ]", errRbSyntax)
+		}
+		return os.Getenv(key), nil
+	case "ENV.fetch":
+		args, err := e.args(1, 2)
+		if err != nil {
+			return "", err
+		}
+		if v, ok := os.LookupEnv(args[0]); ok {
+			return v, nil
+		}
+		if len(args) == 2 {
+			return args[1], nil
+		}
+		return "", fmt.Errorf("environment variable %s is not set", args[0])
+	case "File.join":
+		args, err := e.args(1, -1)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(args...), nil
+	case "File.dirname":
+		args, err := e.args(1, 1)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Dir(args[0]), nil
+	case "File.expand_path":
+		args, err := e.args(1, 2)
+		if err != nil {
+			return "", err
+		}
+		base := ""
+		if len(args) == 2 {
+			base = args[1]
+		}
+		return expandPath(args[0], base)
+	}
+	if v, ok := e.vars[name]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("%w: undefined %s", errRbSyntax, name)
+}
+
+// args parses a parenthesized argument list of between min and max values, max -1 is unlimited
+func (e *rbEval) args(min, max int) ([]string, error) {
+	e.skipSpace()
+	if !e.consume('(') {
+		return nil, fmt.Errorf("%w: missing (", errRbSyntax)
+	}
+	var args []string
+	for {
+		e.skipSpace()
+		if e.consume(')') {
+			break
+		}
+		if len(args) > 0 && !e.consume(',') {
+			return nil, fmt.Errorf("%w: missing , or )", errRbSyntax)
+		}
+		v, err := e.expr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return nil, fmt.Errorf("%w: wrong number of arguments", errRbSyntax)
+	}
+	return args, nil
+}
+
+func (e *rbEval) doubleQuoted() (string, error) {
+	e.pos++
+	var out strings.Builder
+	for e.pos < len(e.src) {
+		c := e.src[e.pos]
+		switch {
+		case c == '"':
+			e.pos++
+			return out.String(), nil
+		case c == '\\' && e.pos+1 < len(e.src):
+			e.pos += 2
+			switch esc := e.src[e.pos-1]; esc {
+			case 'n':
+				out.WriteByte('\n')
+			case 't':
+				out.WriteByte('\t')
+			default:
+				out.WriteByte(esc)
+			}
+		case strings.HasPrefix(e.src[e.pos:], "#{"):
+			end := matchingBrace(e.src, e.pos+2)
+			if end < 0 {
+				return "", fmt.Errorf("%w: unterminated #{", errRbSyntax)
+			}
+			v, err := e.eval(e.src[e.pos+2 : end])
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(v)
+			e.pos = end + 1
+		default:
+			out.WriteByte(c)
+			e.pos++
+		}
+	}
+	return "", fmt.Errorf("%w: unterminated string", errRbSyntax)
+}
+
+func (e *rbEval) singleQuoted() (string, error) {
+	e.pos++
+	var out strings.Builder
+	for e.pos < len(e.src) {
+		c := e.src[e.pos]
+		switch {
+		case c == '\'':
+			e.pos++
+			return out.String(), nil
+		case c == '\\' && e.pos+1 < len(e.src) && (e.src[e.pos+1] == '\'' || e.src[e.pos+1] == '\\'):
+			out.WriteByte(e.src[e.pos+1])
+			e.pos += 2
+		default:
+			out.WriteByte(c)
+			e.pos++
+		}
+	}
+	return "", fmt.Errorf("%w: unterminated string", errRbSyntax)
+}
+
+// ident reads a name such as current_dir, File.join or ENV.fetch
+func (e *rbEval) ident() string {
+	start := e.pos
+	for e.pos < len(e.src) && (isIdentByte(e.src[e.pos]) || e.src[e.pos] == '.') {
+		e.pos++
+	}
+	return e.src[start:e.pos]
+}
+
+func (e *rbEval) skipSpace() {
+	for e.pos < len(e.src) && (e.src[e.pos] == ' ' || e.src[e.pos] == '\t' || e.src[e.pos] == '\r') {
+		e.pos++
+	}
+}
+
+func (e *rbEval) consume(c byte) bool {
+	if e.pos < len(e.src) && e.src[e.pos] == c {
+		e.pos++
+		return true
+	}
+	return false
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// matchingBrace returns the index of the } closing the #{ that starts before pos
+func matchingBrace(s string, pos int) int {
+	depth := 1
+	for i := pos; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// expandPath follows ruby's File.expand_path: ~ is the home directory and relative
+// paths are made absolute against base, or the working directory
+func expandPath(p, base string) (string, error) {
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, p[1:]), nil
+	}
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p), nil
+	}
+	if base == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		base = wd
+	}
+	base, err := expandPath(base, "")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, p), nil
+}
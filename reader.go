@@ -3,16 +3,11 @@ package chef
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
 	"io"
 )
 
 // JSONReader handles arbitrary types and synthesizes a streaming encoder for them.
 func JSONReader(v interface{}) (r io.Reader, err error) {
-	if debug_on() {
-		jsonout, err := json.Marshal(v)
-		fmt.Printf("\n\nJSON IN: %+v \n JSON ERR: %+v\n", string(jsonout), err)
-	}
 	buf := new(bytes.Buffer)
 	err = json.NewEncoder(buf).Encode(v)
 	r = bytes.NewReader(buf.Bytes())
@@ -21,10 +16,6 @@ func JSONReader(v interface{}) (r io.Reader, err error) {
 
 // JSONSeeker handles arbitrary types and synthesizes a streaming encoder for them.
 func JSONSeeker(v interface{}) (r io.ReadSeeker, err error) {
-	if debug_on() {
-		jsonout, err := json.Marshal(v)
-		fmt.Printf("\n\nJSON IN: %+v \n JSON ERR: %+v\n", string(jsonout), err)
-	}
 	buf := new(bytes.Buffer)
 	err = json.NewEncoder(buf).Encode(v)
 	r = bytes.NewReader(buf.Bytes())
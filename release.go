@@ -1,11 +0,0 @@
-//go:build !debug
-// +build !debug
-
-package chef
-
-func debug(fmt string, args ...interface{}) {
-}
-
-func debug_on() bool {
-	return false
-}
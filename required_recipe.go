@@ -1,5 +1,7 @@
 package chef
 
+import "context"
+
 type RequiredRecipeService struct {
 	client *Client
 }
@@ -13,8 +15,13 @@ type RequiredRecipe string
 // 200 - required_recipe enabled = true && required_recipe path specified, returns the recipe
 // 404   required_recipe enabled = false
 func (e *RequiredRecipeService) Get() (data RequiredRecipe, err error) {
+	return e.GetWithContext(context.Background())
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *RequiredRecipeService) GetWithContext(ctx context.Context) (data RequiredRecipe, err error) {
 	var getdata string
-	err = e.client.magicRequestDecoder("GET", "required_recipe", nil, &getdata)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "required_recipe", nil, &getdata)
 	data = RequiredRecipe(getdata)
 	return
 }
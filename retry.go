@@ -0,0 +1,270 @@
+package chef
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Defaults used for zero valued RetryPolicy fields
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryMinBackoff  = 500 * time.Millisecond
+	DefaultRetryMaxBackoff  = 30 * time.Second
+)
+
+// RetryPolicy controls how Client.Do retries requests that fail with a
+// transient error. Each attempt is signed again so the X-Ops-Timestamp and
+// signature headers are fresh, and the request body is rewound.
+//
+// Requests are retried on connection resets, refusals and timeouts, on
+// connections closed before a response (io.EOF), on 429 Too Many Requests and
+// on 5xx responses other than 501 Not Implemented. Only idempotent methods (GET,
+// HEAD, OPTIONS, PUT, DELETE) are retried unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+
+	// MinBackoff is the wait before the first retry. It doubles for every later retry.
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the wait between attempts, including waits requested by Retry-After
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent allows POST and PATCH requests to be retried
+	RetryNonIdempotent bool
+}
+
+// attempts returns the number of attempts allowed by the policy
+func (p *RetryPolicy) attempts() int {
+	if p == nil {
+		return 1
+	}
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryMaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the time to wait before the given retry. retry is 1 for the
+// first retry. A positive Retry-After value from the server takes precedence.
+func (p *RetryPolicy) backoff(retry int, retryAfter time.Duration) time.Duration {
+	min, max := p.MinBackoff, p.MaxBackoff
+	if min <= 0 {
+		min = DefaultRetryMinBackoff
+	}
+	if max <= 0 {
+		max = DefaultRetryMaxBackoff
+	}
+	if retryAfter > 0 {
+		if retryAfter > max {
+			return max
+		}
+		return retryAfter
+	}
+
+	wait := min
+	for i := 1; i < retry && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+	// equal jitter: wait somewhere between half and all of the computed backoff
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1))
+}
+
+// retryable reports whether the request method may be retried under the policy
+func (p *RetryPolicy) retryable(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+// shouldRetry decides if a response or transport error is worth another attempt
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		switch {
+		case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+			return true
+		case errors.As(err, &netErr) && netErr.Timeout():
+			return true
+		}
+		return false
+	}
+	return retryableStatus(res.StatusCode)
+}
+
+// retryableStatus reports whether a response status is transient. 501 Not Implemented
+// won't change on another attempt.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || (code >= 500 && code != http.StatusNotImplemented)
+}
+
+// retryAfter parses the Retry-After header, given either as seconds or as an HTTP date
+func retryAfter(res *http.Response) time.Duration {
+	if res == nil {
+		return 0
+	}
+	val := res.Header.Get("Retry-After")
+	if val == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(val); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(val); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
+
+// rewindable reports if the body of req can be sent again
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind prepares req for another attempt. The body is recreated from GetBody
+// and signed requests get new X-Ops-Timestamp and signature headers.
+func (c *Client) rewind(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		next.Body = body
+	}
+	if c.Auth != nil && req.Header.Get("X-Ops-Authorization-1") != "" {
+		if err := c.Auth.SignRequest(next); err != nil {
+			return nil, err
+		}
+	}
+	return next, nil
+}
+
+// attempt sends req once and reports it to the observers. When there are
+// observers the response body is read so its size is known.
+func (c *Client) attempt(req *http.Request, info RequestInfo) (*http.Response, error) {
+	if len(c.Observers) == 0 {
+		return c.Client.Do(req)
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		info.RequestBytes = 0
+	}
+
+	req = c.observeStart(req, info)
+	start := time.Now()
+	res, err := c.Client.Do(req)
+	if err == nil {
+		var body []byte
+		body, err = io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		info.ResponseBytes = int64(len(body))
+		info.StatusCode = res.StatusCode
+		if err == nil {
+			if cerr := CheckResponse(res); cerr != nil {
+				info.Err = cerr
+				res.Body = io.NopCloser(bytes.NewReader(body))
+			}
+		}
+	}
+	info.Duration = time.Since(start)
+	if err != nil {
+		info.Err = err
+	}
+	c.observeDone(req.Context(), info)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// noRetryKey marks the context of requests their caller retries itself
+type noRetryKey struct{}
+
+// withoutRetries returns ctx for requests sent with a single attempt, whatever c.Retry allows
+func withoutRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// roundTrip sends req, retrying according to c.Retry. Each attempt waits for lim and
+// takes one of its in flight slots, the slot isn't held during the backoff between
+// attempts. The slot of the returned response is released by calling release, which
+// is never nil.
+func (c *Client) roundTrip(req *http.Request, lim *limiter) (res *http.Response, release func(), err error) {
+	release = func() {}
+	attempts := c.Retry.attempts()
+	// a body we can't recreate can only be sent once
+	if !rewindable(req) {
+		attempts = 1
+	}
+	if noRetry, _ := req.Context().Value(noRetryKey{}).(bool); noRetry {
+		attempts = 1
+	}
+	if attempts > 1 && !c.Retry.retryable(req) {
+		attempts = 1
+	}
+
+	operation, template := c.operation(req)
+	for attempt := 1; ; attempt++ {
+		if release, err = lim.acquire(req.Context()); err != nil {
+			return nil, func() {}, err
+		}
+		if err = lim.wait(req.Context()); err != nil {
+			release()
+			return nil, func() {}, err
+		}
+		res, err = c.attempt(req, RequestInfo{
+			Operation:    operation,
+			Path:         template,
+			Method:       req.Method,
+			Attempt:      attempt,
+			RequestBytes: req.ContentLength,
+		})
+		if attempt >= attempts || !shouldRetry(res, err) || req.Context().Err() != nil {
+			return res, release, err
+		}
+
+		wait := c.Retry.backoff(attempt, retryAfter(res))
+		c.log(req.Context(), slog.LevelInfo, "chef request retry",
+			slog.String("method", req.Method),
+			slog.String("url", req.URL.String()),
+			slog.Duration("wait", wait),
+			slog.Int("attempt", attempt+1),
+			slog.Int("max_attempts", attempts),
+		)
+		if res != nil {
+			// drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+		release()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, func() {}, req.Context().Err()
+		case <-timer.C:
+		}
+
+		req, err = c.rewind(req)
+		if err != nil {
+			return nil, func() {}, err
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package chef
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/ctdk/goiardi/chefcrypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetryClient(t *testing.T, url string, policy *RetryPolicy) *Client {
+	c, err := NewClient(&Config{
+		Name:        userid,
+		Key:         privateKeyPKCS1,
+		BaseURL:     url,
+		RetryPolicy: policy,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestRetry_ServerErrorThenSuccess(t *testing.T) {
+	var timestamps []string
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		timestamps = append(timestamps, r.Header.Get("X-Ops-Timestamp"))
+
+		// every attempt must carry a valid signature of its own headers
+		signed, err := assembleSignedHeader(r)
+		assert.Nil(t, err, "signature headers present")
+		decrypted, err := HeaderDecrypt(publicKeyPKCS1, signed)
+		assert.Nil(t, err, "signature decrypts")
+		want := "Method:PUT\nHashed Path:" + HashStr("/nodes/node1") +
+			"\nX-Ops-Content-Hash:" + r.Header.Get("X-Ops-Content-Hash") +
+			"\nX-Ops-Timestamp:" + r.Header.Get("X-Ops-Timestamp") +
+			"\nX-Ops-UserId:" + userid
+		assert.Equal(t, want, string(decrypted), "signature matches this attempt")
+
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"node1"}`))
+	}))
+	defer ts.Close()
+
+	c := newRetryClient(t, ts.URL, &RetryPolicy{MaxAttempts: 3, MaxBackoff: 2 * time.Second})
+	node, err := c.Nodes.Put(Node{Name: "node1"})
+	assert.Nil(t, err, "Put succeeds after a retry")
+	assert.Equal(t, "node1", node.Name)
+	if assert.Len(t, bodies, 2, "two attempts made") {
+		assert.Equal(t, bodies[0], bodies[1], "body rewound for the retry")
+		assert.True(t, strings.Contains(bodies[1], `"node1"`))
+		assert.NotEqual(t, timestamps[0], timestamps[1], "retry signed with a new timestamp")
+	}
+}
+
+func TestRetry_GivesUp(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	c := newRetryClient(t, ts.URL, &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+	_, err := c.Nodes.Get("node1")
+	cerr, _ := ChefError(err)
+	if assert.NotNil(t, cerr, "ErrorResponse returned") {
+		assert.Equal(t, http.StatusTooManyRequests, cerr.StatusCode())
+	}
+	assert.Equal(t, 3, calls, "MaxAttempts honored")
+}
+
+func TestRetry_NonIdempotent(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	c := newRetryClient(t, ts.URL, &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+	_, err := c.Nodes.Post(Node{Name: "node1"})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls, "POST is not retried by default")
+
+	calls = 0
+	c.Retry.RetryNonIdempotent = true
+	_, err = c.Nodes.Post(Node{Name: "node1"})
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, calls, "POST retried when opted in")
+}
+
+func TestRetry_ClientErrorNotRetried(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	c := newRetryClient(t, ts.URL, &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+	_, err := c.Nodes.Get("node1")
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls, "404 is not retried")
+}
+
+func TestRetry_NotImplementedNotRetried(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer ts.Close()
+
+	c := newRetryClient(t, ts.URL, &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+	_, err := c.Nodes.Get("node1")
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, calls, "501 is not retried")
+}
+
+func TestRetryPolicy_Retryable(t *testing.T) {
+	p := &RetryPolicy{}
+	for method, want := range map[string]bool{"GET": true, "HEAD": true, "OPTIONS": true, "PUT": true, "DELETE": true, "POST": false, "PATCH": false, "TRACE": false} {
+		req, _ := http.NewRequest(method, "https://chef.example.com/nodes", nil)
+		assert.Equal(t, want, p.retryable(req), method)
+	}
+	for code, want := range map[int]bool{429: true, 500: true, 501: false, 502: true, 503: true, 504: true, 404: false} {
+		assert.Equal(t, want, shouldRetry(&http.Response{StatusCode: code}, nil), "%d", code)
+	}
+	assert.True(t, shouldRetry(nil, io.EOF), "connection closed before a response")
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for retry, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		wait := p.backoff(retry, 0)
+		assert.True(t, wait >= max/2 && wait <= max, "backoff %v for retry %d within [%v, %v]", wait, retry, max/2, max)
+	}
+	assert.Equal(t, 300*time.Millisecond, p.backoff(1, 300*time.Millisecond), "Retry-After honored")
+	assert.Equal(t, time.Second, p.backoff(1, time.Minute), "Retry-After capped by MaxBackoff")
+
+	var none *RetryPolicy
+	assert.Equal(t, 1, none.attempts(), "nil policy makes one attempt")
+	assert.Equal(t, DefaultRetryMaxAttempts, (&RetryPolicy{}).attempts())
+}
+
+func TestRetryAfter(t *testing.T) {
+	res := &http.Response{Header: http.Header{}}
+	assert.Equal(t, time.Duration(0), retryAfter(res))
+	res.Header.Set("Retry-After", "7")
+	assert.Equal(t, 7*time.Second, retryAfter(res))
+	res.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, retryAfter(res) > 59*time.Minute, "HTTP date form parsed")
+}
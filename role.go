@@ -1,6 +1,9 @@
 package chef
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 type RoleService struct {
 	client *Client
@@ -36,7 +39,12 @@ func (e RoleCreateResult) String() (out string) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#roles
 func (e *RoleService) List() (data *RoleListResult, err error) {
-	err = e.client.magicRequestDecoder("GET", "roles", nil, &data)
+	return e.ListWithContext(context.Background())
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (e *RoleService) ListWithContext(ctx context.Context) (data *RoleListResult, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "roles", nil, &data)
 	return
 }
 
@@ -44,13 +52,18 @@ func (e *RoleService) List() (data *RoleListResult, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#roles
 func (e *RoleService) Create(role *Role) (data *RoleCreateResult, err error) {
+	return e.CreateWithContext(context.Background(), role)
+}
+
+// CreateWithContext is Create with a context for cancellation and deadlines.
+func (e *RoleService) CreateWithContext(ctx context.Context, role *Role) (data *RoleCreateResult, err error) {
 	body, err := JSONReader(role)
 	if err != nil {
 		return
 	}
 
 	// BUG(fujiN): This is now both a *response* decoder and handles upload.. gettin smelly
-	err = e.client.magicRequestDecoder("POST", "roles", body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "POST", "roles", body, &data)
 
 	return
 }
@@ -59,8 +72,13 @@ func (e *RoleService) Create(role *Role) (data *RoleCreateResult, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#roles-name
 func (e *RoleService) Delete(name string) (err error) {
+	return e.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (e *RoleService) DeleteWithContext(ctx context.Context, name string) (err error) {
 	path := fmt.Sprintf("roles/%s", name)
-	err = e.client.magicRequestDecoder("DELETE", path, nil, nil)
+	err = e.client.magicRequestDecoderWithContext(ctx, "DELETE", path, nil, nil)
 	return
 }
 
@@ -68,8 +86,13 @@ func (e *RoleService) Delete(name string) (err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#roles-name
 func (e *RoleService) Get(name string) (data *Role, err error) {
+	return e.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *RoleService) GetWithContext(ctx context.Context, name string) (data *Role, err error) {
 	path := fmt.Sprintf("roles/%s", name)
-	err = e.client.magicRequestDecoder("GET", path, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -78,13 +101,18 @@ func (e *RoleService) Get(name string) (data *Role, err error) {
 // Chef API docs: https://docs.chef.io/api_chef_server.html#roles-name
 // Trying to rename a role by specifying a new name in the body returns a 400
 func (e *RoleService) Put(role *Role) (data *Role, err error) {
+	return e.PutWithContext(context.Background(), role)
+}
+
+// PutWithContext is Put with a context for cancellation and deadlines.
+func (e *RoleService) PutWithContext(ctx context.Context, role *Role) (data *Role, err error) {
 	path := fmt.Sprintf("roles/%s", role.Name)
 	body, err := JSONReader(role)
 	if err != nil {
 		return
 	}
 
-	err = e.client.magicRequestDecoder("PUT", path, body, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "PUT", path, body, &data)
 	return
 }
 
@@ -92,8 +120,13 @@ func (e *RoleService) Put(role *Role) (data *Role, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#roles-name-environments
 func (e *RoleService) GetEnvironments(role string) (data RoleEnvironmentsResult, err error) {
+	return e.GetEnvironmentsWithContext(context.Background(), role)
+}
+
+// GetEnvironmentsWithContext is GetEnvironments with a context for cancellation and deadlines.
+func (e *RoleService) GetEnvironmentsWithContext(ctx context.Context, role string) (data RoleEnvironmentsResult, err error) {
 	path := fmt.Sprintf("roles/%s/environments", role)
-	err = e.client.magicRequestDecoder("GET", path, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
 
@@ -101,7 +134,12 @@ func (e *RoleService) GetEnvironments(role string) (data RoleEnvironmentsResult,
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#roles-name-environments-name
 func (e *RoleService) GetEnvironmentRunlist(role string, environment string) (data EnvRunList, err error) {
+	return e.GetEnvironmentRunlistWithContext(context.Background(), role, environment)
+}
+
+// GetEnvironmentRunlistWithContext is GetEnvironmentRunlist with a context for cancellation and deadlines.
+func (e *RoleService) GetEnvironmentRunlistWithContext(ctx context.Context, role string, environment string) (data EnvRunList, err error) {
 	path := fmt.Sprintf("roles/%s/environments/%s", role, environment)
-	err = e.client.magicRequestDecoder("GET", path, nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", path, nil, &data)
 	return
 }
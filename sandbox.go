@@ -1,6 +1,7 @@
 package chef
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -40,6 +41,11 @@ type Sandbox struct {
 // Post creates a new sandbox on the chef-server. Deviates from the Chef-server api in that it takes a []string of sums for the sandbox instead of the IMO rediculous hash of nulls that the API wants. We convert it to the right structure under the hood for the chef-server api.
 // http://docs.getchef.com/api_chef_server.html#id38
 func (s SandboxService) Post(sums []string) (data SandboxPostResponse, err error) {
+	return s.PostWithContext(context.Background(), sums)
+}
+
+// PostWithContext is Post with a context for cancellation and deadlines.
+func (s SandboxService) PostWithContext(ctx context.Context, sums []string) (data SandboxPostResponse, err error) {
 	smap := make(map[string]interface{})
 	for _, hashstr := range sums {
 		smap[hashstr] = nil
@@ -51,12 +57,17 @@ func (s SandboxService) Post(sums []string) (data SandboxPostResponse, err error
 		return
 	}
 
-	err = s.client.magicRequestDecoder("POST", "sandboxes", body, &data)
+	err = s.client.magicRequestDecoderWithContext(ctx, "POST", "sandboxes", body, &data)
 	return
 }
 
 // Put is used to commit a sandbox ID to the chef server. To signal that the sandbox you have Posted is now uploaded.
 func (s SandboxService) Put(id string) (box Sandbox, err error) {
+	return s.PutWithContext(context.Background(), id)
+}
+
+// PutWithContext is Put with a context for cancellation and deadlines.
+func (s SandboxService) PutWithContext(ctx context.Context, id string) (box Sandbox, err error) {
 	answer := make(map[string]bool)
 	answer["is_completed"] = true
 	body, err := JSONReader(answer)
@@ -65,6 +76,6 @@ func (s SandboxService) Put(id string) (box Sandbox, err error) {
 		return box, fmt.Errorf("must supply sandbox id to PUT request.")
 	}
 
-	err = s.client.magicRequestDecoder("PUT", "sandboxes/"+id, body, &box)
+	err = s.client.magicRequestDecoderWithContext(ctx, "PUT", "sandboxes/"+id, body, &box)
 	return
 }
@@ -0,0 +1,87 @@
+package chef
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// orgNameRe matches the organization names the Chef server accepts
+var orgNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,254}$`)
+
+// ForOrganization returns a client for the named organization on the same Chef server.
+// The new client shares the authentication, transport, retry policy, limits, logger
+// and observers of c, so it is cheap to create one per organization. Names the Chef
+// server wouldn't accept, lower case letters, digits, hyphens and underscores starting
+// with a letter or digit, are an error, they could point the client at another path.
+func (c *Client) ForOrganization(name string) (*Client, error) {
+	if !orgNameRe.MatchString(name) {
+		return nil, fmt.Errorf("invalid organization name %q", name)
+	}
+	root := c.serverRoot()
+	return c.rootedAt(root.ResolveReference(&url.URL{Path: "organizations/" + name + "/"})), nil
+}
+
+// Global returns a client for the endpoints outside of any organization, such as
+// /users and /organizations. It shares everything but the BaseURL with c and is c
+// itself when c isn't scoped to an organization.
+//
+// UserService, OrganizationService, AuthenticateUserService, LicenseService, StatsService
+// and StatusService always send their requests through Global, so they can be used
+// from an organization client.
+//
+// The global client is a snapshot of c made once, on the first call. Services replaced
+// on c before, such as a FakeUsers, are kept. Fields of c set later, such as Logger,
+// Retry, SearchSettings or Observers, or services replaced later, don't reach it; set
+// them on Global() too, or before its first call. Fields holding pointers, such as the
+// RetryPolicy a Retry points to, are shared, so changes made through them are seen.
+func (c *Client) Global() *Client {
+	root := c.serverRoot()
+	if c.BaseURL != nil && root.String() == c.BaseURL.String() {
+		return c
+	}
+	if c.global == nil {
+		// clients not made by NewClient have nowhere to keep it
+		return c.rootedAt(root)
+	}
+	c.global.once.Do(func() {
+		c.global.client = c.rootedAt(root)
+	})
+	return c.global.client
+}
+
+// globalClient holds the client returned by Global
+type globalClient struct {
+	once   sync.Once
+	client *Client
+}
+
+// serverRoot returns BaseURL without any /organizations/NAME suffix
+func (c *Client) serverRoot() *url.URL {
+	root := &url.URL{Path: "/"}
+	if c.BaseURL != nil {
+		root = &url.URL{Scheme: c.BaseURL.Scheme, User: c.BaseURL.User, Host: c.BaseURL.Host, Path: c.BaseURL.Path}
+	}
+	segments := strings.Split(strings.TrimSuffix(root.Path, "/"), "/")
+	for i := len(segments) - 2; i >= 0; i-- {
+		if segments[i] == "organizations" && segments[i+1] != "" {
+			segments = segments[:i]
+			break
+		}
+	}
+	root.Path = strings.Join(segments, "/") + "/"
+	return root
+}
+
+// rootedAt returns a copy of c using base as the BaseURL. The services of c are
+// bound to the copy, services replaced in c are shared with it.
+func (c *Client) rootedAt(base *url.URL) *Client {
+	derived := *c
+	derived.BaseURL = base
+	derived.global = new(globalClient)
+	derived.initServices()
+	derived.copyServices(c)
+	return &derived
+}
@@ -0,0 +1,135 @@
+package chef
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerRoot(t *testing.T) {
+	cases := map[string]string{
+		"https://chef.example.com/organizations/clownco/": "https://chef.example.com/",
+		"https://chef.example.com/organizations/clownco":  "https://chef.example.com/",
+		"https://chef.example.com/":                       "https://chef.example.com/",
+		"https://chef.example.com":                        "https://chef.example.com/",
+		"https://example.com/chef/organizations/clownco/": "https://example.com/chef/",
+		"https://chef.example.com/organizations/":         "https://chef.example.com/organizations/",
+	}
+	for base, root := range cases {
+		c, err := NewClient(&Config{Name: userid, Key: privateKeyPKCS1, BaseURL: base})
+		if assert.Nil(t, err) {
+			assert.Equal(t, root, c.serverRoot().String(), base)
+		}
+	}
+}
+
+func TestForOrganization(t *testing.T) {
+	c, err := NewClient(&Config{
+		Name:        userid,
+		Key:         privateKeyPKCS1,
+		BaseURL:     "https://chef.example.com/organizations/clownco/",
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2},
+		Observers:   []Observer{&recordingObserver{}},
+	})
+	assert.Nil(t, err)
+
+	other, err := c.ForOrganization("acme")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://chef.example.com/organizations/acme/", other.BaseURL.String())
+	assert.Equal(t, "https://chef.example.com/organizations/clownco/", c.BaseURL.String(), "original unchanged")
+	assert.Same(t, c.Auth, other.Auth)
+	assert.Same(t, c.Client, other.Client)
+	assert.Same(t, c.Retry, other.Retry)
+	assert.Equal(t, c.Observers, other.Observers)
+	assert.Same(t, other, other.Nodes.(*NodeService).client, "services use the new client")
+
+	global := c.Global()
+	assert.Equal(t, "https://chef.example.com/", global.BaseURL.String())
+	assert.Same(t, global, global.Global(), "a global client is its own global client")
+	acme, err := global.ForOrganization("acme")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://chef.example.com/organizations/acme/", acme.BaseURL.String())
+}
+
+func TestGlobalServices(t *testing.T) {
+	mux = http.NewServeMux()
+	server = httptest.NewServer(mux)
+	defer teardown()
+	client, _ = NewClient(&Config{
+		Name:                  userid,
+		Key:                   privateKeyPKCS1,
+		BaseURL:               server.URL + "/organizations/clownco/",
+		AuthenticationVersion: "1.0",
+	})
+
+	mux.HandleFunc("/users/jdoe", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"username":"jdoe"}`)
+	})
+	mux.HandleFunc("/organizations", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"clownco":"https://chef/organizations/clownco"}`)
+	})
+	mux.HandleFunc("/organizations/clownco/nodes/node1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"node1"}`)
+	})
+	mux.HandleFunc("/organizations/acme/nodes/node1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"acme-node1"}`)
+	})
+
+	user, err := client.Users.Get("jdoe")
+	assert.Nil(t, err)
+	assert.Equal(t, "jdoe", user.UserName)
+
+	orgs, err := client.Organizations.List()
+	assert.Nil(t, err)
+	assert.Contains(t, orgs, "clownco")
+
+	node, err := client.Nodes.Get("node1")
+	assert.Nil(t, err)
+	assert.Equal(t, "node1", node.Name)
+
+	acme, err := client.ForOrganization("acme")
+	assert.Nil(t, err)
+	node, err = acme.Nodes.Get("node1")
+	assert.Nil(t, err)
+	assert.Equal(t, "acme-node1", node.Name)
+}
+
+func TestGlobalCached(t *testing.T) {
+	c, err := NewClient(&Config{Name: userid, Key: privateKeyPKCS1, BaseURL: "https://chef.example.com/organizations/clownco/"})
+	assert.Nil(t, err)
+	users := &FakeUsers{GetFunc: func(name string) (User, error) { return User{UserName: name}, nil }}
+	c.Users = users
+
+	global := c.Global()
+	assert.Same(t, global, c.Global(), "made once")
+	assert.Same(t, global, global.Nodes.(*NodeService).client, "own services use the global client")
+	assert.Same(t, users, global.Users, "replaced services are kept")
+
+	status := &FakeStatus{}
+	global.Status = status
+	assert.Same(t, status, c.Global().Status, "services replaced on the global client stay")
+
+	other, err := c.ForOrganization("acme")
+	assert.Nil(t, err)
+	assert.Same(t, users, other.Users)
+	assert.NotSame(t, global, other.Global(), "each client has its own global client")
+	assert.Same(t, other.Global(), other.Global())
+}
+
+func TestForOrganizationInvalidName(t *testing.T) {
+	c, err := NewClient(&Config{Name: userid, Key: privateKeyPKCS1, BaseURL: "https://chef.example.com/organizations/clownco/"})
+	assert.Nil(t, err)
+	for _, name := range []string{"", "..", "../../x", "a/b", "a?b=c", "a#b", "Acme", "-acme", "a%2Fb"} {
+		_, err := c.ForOrganization(name)
+		assert.NotNil(t, err, name)
+	}
+	for _, name := range []string{"acme", "4th_coffee", "my-org"} {
+		org, err := c.ForOrganization(name)
+		if assert.Nil(t, err, name) {
+			assert.Equal(t, "https://chef.example.com/organizations/"+name+"/", org.BaseURL.String())
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package chef
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type SearchService struct {
@@ -20,19 +25,26 @@ type SearchQuery struct {
 	// The query you want to execute. This is the 'chef' query ex: 'chef_environment:prod'
 	Query string
 
-	// Sort order you want the search results returned
+	// Sort order you want the search results returned. The sort order of the client's
+	// SearchSettings is used when empty.
 	SortBy string
 
 	// Starting position for search
 	Start int
 
-	// Number of rows to return
+	// Number of rows to return. The page size of the client's SearchSettings is used when 0.
 	Rows int
 }
 
-// String implements the Stringer Interface for the SearchQuery
+// String implements the Stringer Interface for the SearchQuery. It returns the index
+// and the URL encoded parameters of the search.
 func (q SearchQuery) String() string {
-	return fmt.Sprintf("%s?q=%s&rows=%d&sort=%s&start=%d", q.Index, q.Query, q.Rows, q.SortBy, q.Start)
+	params := url.Values{}
+	params.Set("q", q.Query)
+	params.Set("rows", strconv.Itoa(q.Rows))
+	params.Set("sort", q.SortBy)
+	params.Set("start", strconv.Itoa(q.Start))
+	return url.PathEscape(q.Index) + "?" + params.Encode()
 }
 
 // SearchResult
@@ -55,52 +67,150 @@ type SearchRow struct {
 	Data json.RawMessage
 }
 
-var inc = 1000
+// SearchSettings are the defaults of the searches made by a Client
+type SearchSettings struct {
+	// PageSize is the number of rows requested per page, 1000 when 0
+	PageSize int
 
+	// SortBy is the sort order of the rows, "X_CHEF_id_CHEF_X asc" when empty
+	SortBy string
+
+	// MaxRows stops Exec, its variants and search iterators after this many rows. The
+	// Total of the result still counts every matching row. There is no limit when 0.
+	MaxRows int
+
+	// Concurrency is the number of pages Exec and its variants request at once once the
+	// first page has told the total. The rows are still returned in order. 1 when 0.
+	Concurrency int
+
+	// PageRetries is the number of times a page failing with a transient error, such as a
+	// connection reset, a 429 or a 5xx response, is requested again before the search
+	// fails. The other pages are kept. The waits between attempts follow the client's
+	// RetryPolicy. When set, it replaces the attempts of the RetryPolicy for search pages,
+	// so a page is requested at most PageRetries+1 times. When 0, the RetryPolicy applies.
+	PageRetries int
+}
+
+const (
+	// These are the defaults in chef: https://github.com/opscode/chef/blob/master/lib/chef/search/query.rb#L102-L105
+	defaultSearchPageSize = 1000
+	defaultSearchSortBy   = "X_CHEF_id_CHEF_X asc"
+)
+
+// ErrSearchChanged is returned when the total of a search changes between its pages,
+// as when objects are added or removed during the search. Rows may have been missed
+// or returned twice.
+var ErrSearchChanged = errors.New("search results changed between pages")
+
+// searchSettings returns the search settings of c with the defaults filled in
+func (c *Client) searchSettings() SearchSettings {
+	var settings SearchSettings
+	if c.SearchSettings != nil {
+		settings = *c.SearchSettings
+	}
+	pageSize := &fallbackSearchPageSize
+	if c.searchPageSize != nil {
+		pageSize = c.searchPageSize
+	}
+	if size := pageSize.Load(); size > 0 {
+		settings.PageSize = int(size)
+	}
+	if settings.PageSize <= 0 {
+		settings.PageSize = defaultSearchPageSize
+	}
+	if settings.SortBy == "" {
+		settings.SortBy = defaultSearchSortBy
+	}
+	return settings
+}
+
+// fallbackSearchPageSize is the page size set with PageSize on a SearchService or
+// Client not made by NewClient, which have nowhere to keep it. As in earlier releases
+// it applies to every such client.
+var fallbackSearchPageSize atomic.Int64
+
+// PageSize sets the number of rows requested per page by the searches of this client
+// and of the clients derived from it with ForOrganization and Global. It overrides
+// SearchSettings.PageSize and may be called while searches run, they keep the page
+// size they started with. Prefer Config.SearchSettings for new code.
 func (e SearchService) PageSize(setting int) {
-	inc = setting
+	if e.client == nil || e.client.searchPageSize == nil {
+		fallbackSearchPageSize.Store(int64(setting))
+		return
+	}
+	e.client.searchPageSize.Store(int64(setting))
+}
+
+// withDefaults returns q with the client's settings in place of a zero Rows or SortBy
+func (q SearchQuery) withDefaults(client *Client) SearchQuery {
+	settings := client.searchSettings()
+	if q.Rows <= 0 {
+		q.Rows = settings.PageSize
+	}
+	if q.SortBy == "" {
+		q.SortBy = settings.SortBy
+	}
+	return q
 }
 
 // Do will execute the search query on the client
 func (q SearchQuery) Do(client *Client) (res SearchResult, err error) {
-	fullUrl := fmt.Sprintf("search/%s", q)
-	err = client.magicRequestDecoder("GET", fullUrl, nil, &res)
+	return q.DoWithContext(context.Background(), client)
+}
+
+// DoWithContext is Do with a context for cancellation and deadlines.
+func (q SearchQuery) DoWithContext(ctx context.Context, client *Client) (res SearchResult, err error) {
+	fullUrl := fmt.Sprintf("search/%s", q.withDefaults(client))
+	err = client.magicRequestDecoderWithContext(ctx, "GET", fullUrl, nil, &res)
 	return
 }
 
 // DoJSON will execute the search query on the client and return
 // rawJSON formatted results
 func (q SearchQuery) DoJSON(client *Client) (res JSearchResult, err error) {
-	fullUrl := fmt.Sprintf("search/%s", q)
-	err = client.magicRequestDecoder("GET", fullUrl, nil, &res)
+	return q.DoJSONWithContext(context.Background(), client)
+}
+
+// DoJSONWithContext is DoJSON with a context for cancellation and deadlines.
+func (q SearchQuery) DoJSONWithContext(ctx context.Context, client *Client) (res JSearchResult, err error) {
+	fullUrl := fmt.Sprintf("search/%s", q.withDefaults(client))
+	err = client.magicRequestDecoderWithContext(ctx, "GET", fullUrl, nil, &res)
 	return
 }
 
 // DoPartial will execute the search query on the client with partial mapping
 func (q SearchQuery) DoPartial(client *Client, params map[string]interface{}) (res SearchResult, err error) {
-	fullUrl := fmt.Sprintf("search/%s", q)
+	return q.DoPartialWithContext(context.Background(), client, params)
+}
+
+// DoPartialWithContext is DoPartial with a context for cancellation and deadlines.
+func (q SearchQuery) DoPartialWithContext(ctx context.Context, client *Client, params map[string]interface{}) (res SearchResult, err error) {
+	fullUrl := fmt.Sprintf("search/%s", q.withDefaults(client))
 
 	body, err := JSONReader(params)
 	if err != nil {
-		debug("Problem encoding params for body %v", err.Error())
 		return
 	}
 
-	err = client.magicRequestDecoder("POST", fullUrl, body, &res)
+	err = client.magicRequestDecoderWithContext(ctx, "POST", fullUrl, body, &res)
 	return
 }
 
 // DoPartialJSON will execute the search query on the client with partial mapping and return raw JSON results
 func (q SearchQuery) DoPartialJSON(client *Client, params map[string]interface{}) (res JSearchResult, err error) {
-	fullUrl := fmt.Sprintf("search/%s", q)
+	return q.DoPartialJSONWithContext(context.Background(), client, params)
+}
+
+// DoPartialJSONWithContext is DoPartialJSON with a context for cancellation and deadlines.
+func (q SearchQuery) DoPartialJSONWithContext(ctx context.Context, client *Client, params map[string]interface{}) (res JSearchResult, err error) {
+	fullUrl := fmt.Sprintf("search/%s", q.withDefaults(client))
 
 	body, err := JSONReader(params)
 	if err != nil {
-		debug("Problem encoding params for body %v", err.Error())
 		return
 	}
 
-	err = client.magicRequestDecoder("POST", fullUrl, body, &res)
+	err = client.magicRequestDecoderWithContext(ctx, "POST", fullUrl, body, &res)
 	return
 }
 
@@ -112,191 +222,287 @@ func (e SearchService) NewQuery(idx, statement string) (query SearchQuery, err e
 		return
 	}
 
-	query = SearchQuery{
-		Index: idx,
-		Query: statement,
-		// These are the defaults in chef: https://github.com/opscode/chef/blob/master/lib/chef/search/query.rb#L102-L105
-		SortBy: "X_CHEF_id_CHEF_X asc",
+	query = e.query(idx, statement)
+	return
+}
+
+// query returns the search of idx for statement with the client's settings
+func (e SearchService) query(idx, statement string) SearchQuery {
+	settings := e.client.searchSettings()
+	return SearchQuery{
+		Index:  idx,
+		Query:  statement,
+		SortBy: settings.SortBy,
 		Start:  0,
-		Rows:   inc,
+		Rows:   settings.PageSize,
 	}
+}
 
-	return
+// rawSearchPage is a page of search results with the rows left encoded
+type rawSearchPage struct {
+	Total int
+	Start int
+	Rows  []json.RawMessage
 }
 
-// Exec runs the query on the index passed in. This is a helper method. If you want more control over the query  use NewQuery and its Do() method.
-// BUG(spheromak): Should we use Exec or SearchQuery.Do() or have both ?
-func (e SearchService) Exec(idx, statement string) (res SearchResult, err error) {
-	//  Copy-paste here till We decide which way to go with Exec vs Do
-	if !strings.Contains(statement, ":") {
-		err = errors.New("statement is malformed")
-		return
+// fetchSearchPage requests a page of query, with a partial search when params isn't nil.
+// Pages failing with a transient error are requested again up to the PageRetries of
+// the client's settings. The client doesn't retry the requests itself then, the two
+// would multiply the attempts.
+func fetchSearchPage(ctx context.Context, client *Client, query SearchQuery, params map[string]interface{}) (page rawSearchPage, err error) {
+	retries := client.searchSettings().PageRetries
+	reqCtx := ctx
+	if retries > 0 {
+		reqCtx = withoutRetries(ctx)
 	}
+	for retry := 1; ; retry++ {
+		page, err = requestSearchPage(reqCtx, client, query, params)
+		if err == nil || retry > retries || !retryablePageError(err) || ctx.Err() != nil {
+			return
+		}
 
-	query := SearchQuery{
-		Index: idx,
-		Query: statement,
-		// These are the defaults in chef: https://github.com/opscode/chef/blob/master/lib/chef/search/query.rb#L102-L105
-		SortBy: "X_CHEF_id_CHEF_X asc",
-		Start:  0,
-		Rows:   inc,
+		policy := client.Retry
+		if policy == nil {
+			policy = &RetryPolicy{}
+		}
+		wait := policy.backoff(retry, 0)
+		client.log(ctx, slog.LevelInfo, "chef search page retry",
+			slog.String("index", query.Index),
+			slog.Int("start", query.Start),
+			slog.Duration("wait", wait),
+			slog.Int("retry", retry),
+			slog.String("error", err.Error()),
+		)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return page, ctx.Err()
+		case <-timer.C:
+		}
 	}
+}
 
-	res, err = query.Do(e.client)
-	if err != nil {
+// requestSearchPage sends a single request for a page of query
+func requestSearchPage(ctx context.Context, client *Client, query SearchQuery, params map[string]interface{}) (page rawSearchPage, err error) {
+	fullUrl := fmt.Sprintf("search/%s", query)
+	if params == nil {
+		err = client.magicRequestDecoderWithContext(ctx, "GET", fullUrl, nil, &page)
 		return
 	}
-	start := res.Start
-	total := res.Total
-
-	for start+inc <= total {
-		query.Start = query.Start + inc
-		start = query.Start
-		ares, err := query.Do(e.client)
-		if err != nil {
-			return res, err
-		}
-		res.Rows = append(res.Rows, ares.Rows...)
+	body, err := JSONReader(params)
+	if err != nil {
+		return
 	}
+	err = client.magicRequestDecoderWithContext(ctx, "POST", fullUrl, body, &page)
 	return
 }
 
-// PartialExec Executes a partial search based on passed in params and the query.
-func (e SearchService) PartialExec(idx, statement string, params map[string]interface{}) (res SearchResult, err error) {
-	query := SearchQuery{
-		Index: idx,
-		Query: statement,
-		// These are the defaults in chef: https://github.com/opscode/chef/blob/master/lib/chef/search/query.rb#L102-L105
-		// SortBy: "X_CHEF_id_CHEF_X asc",
-		SortBy: "X_CHEF_id_CHEF_X asc",
-		Start:  0,
-		Rows:   inc,
+// retryablePageError reports whether a failed page is worth requesting again
+func retryablePageError(err error) bool {
+	if cerr, _ := ChefError(err); cerr != nil {
+		return retryableStatus(cerr.StatusCode())
 	}
+	return shouldRetry(nil, err)
+}
 
-	fullUrl := fmt.Sprintf("search/%s", query)
-	body, err := JSONSeeker(params)
+// checkTotal returns ErrSearchChanged when a page reports another total than the first
+func checkTotal(first, total int) error {
+	if total != first {
+		return fmt.Errorf("%w: total went from %d to %d", ErrSearchChanged, first, total)
+	}
+	return nil
+}
+
+// searchPages requests the pages of query, passing the rows of each to add in order.
+// The first page tells the total, the pages after it are requested with the Concurrency
+// of the client's settings. It stops after the last page or the MaxRows of the settings.
+// The context is checked before each page is requested. It returns the total of the
+// first page, and ErrSearchChanged when a later page reports another total.
+func (e SearchService) searchPages(ctx context.Context, query SearchQuery, params map[string]interface{}, add func(rows []json.RawMessage) error) (total int, err error) {
+	settings := e.client.searchSettings()
+	count := 0
+	addRows := func(rows []json.RawMessage) error {
+		if settings.MaxRows > 0 && count+len(rows) > settings.MaxRows {
+			rows = rows[:settings.MaxRows-count]
+		}
+		count += len(rows)
+		return add(rows)
+	}
+
+	first, err := fetchSearchPage(ctx, e.client, query, params)
 	if err != nil {
-		debug("Problem encoding params for body")
+		return
+	}
+	total = first.Total
+	if err = addRows(first.Rows); err != nil {
 		return
 	}
 
-	err = e.client.magicRequestDecoder("POST", fullUrl, body, &res)
-	if err != nil {
+	var starts []int
+	for start := query.Start + query.Rows; start < total && (settings.MaxRows <= 0 || start < settings.MaxRows); start += query.Rows {
+		starts = append(starts, start)
+	}
+	if len(starts) == 0 {
 		return
 	}
 
-	start := res.Start
-	// the total rows available for this query across all pages
-	total := res.Total
-	paged_res := SearchResult{}
-
-	for start+inc <= total {
-		query.Start = query.Start + inc
-		start = query.Start
-		body.Seek(0, io.SeekStart)
-		if err != nil {
-			fmt.Printf("Seek error %+v\n", err)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		page rawSearchPage
+		err  error
+	}
+	results := make([]chan result, len(starts))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+	// a token is held from the request of a page until its rows are added, so no more
+	// than Concurrency pages are requested or waiting at once
+	tokens := make(chan struct{}, max(settings.Concurrency, 1))
+	go func() {
+		for i, start := range starts {
+			select {
+			case tokens <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			pageQuery := query
+			pageQuery.Start = start
+			go func(pageQuery SearchQuery, results chan<- result) {
+				if err := ctx.Err(); err != nil {
+					results <- result{err: err}
+					return
+				}
+				page, err := fetchSearchPage(ctx, e.client, pageQuery, params)
+				results <- result{page: page, err: err}
+			}(pageQuery, results[i])
+		}
+	}()
+
+	for i := range starts {
+		var res result
+		select {
+		case res = <-results[i]:
+		case <-ctx.Done():
+			return total, ctx.Err()
+		}
+		<-tokens
+		if res.err != nil {
+			return total, res.err
+		}
+		if err = checkTotal(total, res.page.Total); err != nil {
 			return
 		}
-		fullUrl := fmt.Sprintf("search/%s", query)
-		err = e.client.magicRequestDecoder("POST", fullUrl, body, &paged_res)
-		if err != nil {
-			fmt.Printf("Partial search error %+v\n", err)
+		if err = addRows(res.page.Rows); err != nil {
 			return
 		}
-		// add this page of results to the primary SearchResult instance
-		res.Rows = append(res.Rows, paged_res.Rows...)
 	}
 	return
 }
 
-// ExecJSON runs the query on the index passed in. This is a helper method. If you want more control over the query use NewQuery and its Do() method.
-func (e SearchService) ExecJSON(idx, statement string) (res JSearchResult, err error) {
-	//  Copy-paste here till We decide which way to go with Exec vs Do
-	if !strings.Contains(statement, ":") {
-		err = errors.New("statement is malformed")
-		return
+// searchRowValues decodes rows into values, the rows of Exec and PartialExec
+func searchRowValues(rows []json.RawMessage) ([]interface{}, error) {
+	values := make([]interface{}, len(rows))
+	for i, row := range rows {
+		if err := json.Unmarshal(row, &values[i]); err != nil {
+			return nil, err
+		}
 	}
+	return values, nil
+}
 
-	query := SearchQuery{
-		Index: idx,
-		Query: statement,
-		// These are the defaults in chef: https://github.com/opscode/chef/blob/master/lib/chef/search/query.rb#L102-L105
-		SortBy: "X_CHEF_id_CHEF_X asc",
-		Start:  0,
-		Rows:   inc,
+// searchRowsJSON decodes rows into SearchRows, the rows of ExecJSON and PartialExecJSON
+func searchRowsJSON(rows []json.RawMessage) ([]SearchRow, error) {
+	values := make([]SearchRow, len(rows))
+	for i, row := range rows {
+		if err := json.Unmarshal(row, &values[i]); err != nil {
+			return nil, err
+		}
 	}
+	return values, nil
+}
 
-	res, err = query.DoJSON(e.client)
+// Exec runs the query on the index passed in. This is a helper method. If you want more control over the query  use NewQuery and its Do() method.
+// BUG(spheromak): Should we use Exec or SearchQuery.Do() or have both ?
+func (e SearchService) Exec(idx, statement string) (res SearchResult, err error) {
+	return e.ExecWithContext(context.Background(), idx, statement)
+}
+
+// ExecWithContext is Exec with a context for cancellation and deadlines.
+// The context is checked before each page is requested.
+func (e SearchService) ExecWithContext(ctx context.Context, idx, statement string) (res SearchResult, err error) {
+	query, err := e.NewQuery(idx, statement)
 	if err != nil {
 		return
 	}
-	start := res.Start
-	total := res.Total
-
-	for start+inc <= total {
-		query.Start = query.Start + inc
-		start = query.Start
-		ares, err := query.DoJSON(e.client)
-		if err != nil {
-			return res, err
-		}
-		res.Rows = append(res.Rows, ares.Rows...)
-	}
+	res.Total, err = e.searchPages(ctx, query, nil, func(rows []json.RawMessage) error {
+		values, err := searchRowValues(rows)
+		res.Rows = append(res.Rows, values...)
+		return err
+	})
 	return
 }
 
-// PartialExecJSON Executes a partial search based on passed in params and the query.
-func (e SearchService) PartialExecJSON(idx, statement string, params map[string]interface{}) (res JSearchResult, err error) {
-	query := SearchQuery{
-		Index: idx,
-		Query: statement,
-		// These are the defaults in chef: https://github.com/opscode/chef/blob/master/lib/chef/search/query.rb#L102-L105
-		// SortBy: "X_CHEF_id_CHEF_X asc",
-		SortBy: "X_CHEF_id_CHEF_X asc",
-		Start:  0,
-		Rows:   inc,
-	}
+// PartialExec Executes a partial search based on passed in params and the query.
+func (e SearchService) PartialExec(idx, statement string, params map[string]interface{}) (res SearchResult, err error) {
+	return e.PartialExecWithContext(context.Background(), idx, statement, params)
+}
 
-	fullUrl := fmt.Sprintf("search/%s", query)
-	body, err := JSONSeeker(params)
-	if err != nil {
-		debug("Problem encoding params for body")
-		return
-	}
+// PartialExecWithContext is PartialExec with a context for cancellation and deadlines.
+// The context is checked before each page is requested.
+func (e SearchService) PartialExecWithContext(ctx context.Context, idx, statement string, params map[string]interface{}) (res SearchResult, err error) {
+	res.Total, err = e.searchPages(ctx, e.query(idx, statement), params, func(rows []json.RawMessage) error {
+		values, err := searchRowValues(rows)
+		res.Rows = append(res.Rows, values...)
+		return err
+	})
+	return
+}
+
+// ExecJSON runs the query on the index passed in. This is a helper method. If you want more control over the query use NewQuery and its Do() method.
+func (e SearchService) ExecJSON(idx, statement string) (res JSearchResult, err error) {
+	return e.ExecJSONWithContext(context.Background(), idx, statement)
+}
 
-	err = e.client.magicRequestDecoder("POST", fullUrl, body, &res)
+// ExecJSONWithContext is ExecJSON with a context for cancellation and deadlines.
+// The context is checked before each page is requested.
+func (e SearchService) ExecJSONWithContext(ctx context.Context, idx, statement string) (res JSearchResult, err error) {
+	query, err := e.NewQuery(idx, statement)
 	if err != nil {
 		return
 	}
+	res.Total, err = e.searchPages(ctx, query, nil, func(rows []json.RawMessage) error {
+		values, err := searchRowsJSON(rows)
+		res.Rows = append(res.Rows, values...)
+		return err
+	})
+	return
+}
 
-	start := res.Start
-	// the total rows available for this query across all pages
-	total := res.Total
-	paged_res := JSearchResult{}
-
-	for start+inc <= total {
-		query.Start = query.Start + inc
-		start = query.Start
-		body.Seek(0, io.SeekStart)
-		if err != nil {
-			fmt.Printf("Seek error %+v\n", err)
-			return
-		}
-		fullUrl := fmt.Sprintf("search/%s", query)
-		err = e.client.magicRequestDecoder("POST", fullUrl, body, &paged_res)
-		if err != nil {
-			fmt.Printf("Partial search error %+v\n", err)
-			return
-		}
-		// add this page of results to the primary SearchResult instance
-		res.Rows = append(res.Rows, paged_res.Rows...)
-	}
+// PartialExecJSON Executes a partial search based on passed in params and the query.
+func (e SearchService) PartialExecJSON(idx, statement string, params map[string]interface{}) (res JSearchResult, err error) {
+	return e.PartialExecJSONWithContext(context.Background(), idx, statement, params)
+}
+
+// PartialExecJSONWithContext is PartialExecJSON with a context for cancellation and deadlines.
+// The context is checked before each page is requested.
+func (e SearchService) PartialExecJSONWithContext(ctx context.Context, idx, statement string, params map[string]interface{}) (res JSearchResult, err error) {
+	res.Total, err = e.searchPages(ctx, e.query(idx, statement), params, func(rows []json.RawMessage) error {
+		values, err := searchRowsJSON(rows)
+		res.Rows = append(res.Rows, values...)
+		return err
+	})
 	return
 }
 
 // Chef API docs: https://docs.chef.io/api_chef_server/#get-46
 func (e SearchService) Indexes() (data map[string]string, err error) {
-	err = e.client.magicRequestDecoder("GET", "search", nil, &data)
+	return e.IndexesWithContext(context.Background())
+}
+
+// IndexesWithContext is Indexes with a context for cancellation and deadlines.
+func (e SearchService) IndexesWithContext(ctx context.Context) (data map[string]string, err error) {
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "search", nil, &data)
 	return
 }
@@ -0,0 +1,253 @@
+package chef
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+)
+
+// SearchOptions configures a SearchIterator
+type SearchOptions struct {
+	// PageSize is the number of rows requested per page. 0 uses the page size of the
+	// client's SearchSettings.
+	PageSize int
+
+	// Prefetch requests the next page while the rows of the current page are read
+	Prefetch bool
+
+	// Partial selects the fields returned by a partial search, as the params of PartialExec.
+	// The whole objects are returned when it is nil.
+	Partial map[string]interface{}
+}
+
+// SearchIterator reads the rows of a search one page at a time, so only a page of
+// rows is held in memory. Use it as a cursor:
+//
+//	it := client.Search.Iterator("node", "chef_environment:prod", nil)
+//	defer it.Close()
+//	for it.Next() {
+//		var node chef.Node
+//		if err := it.Decode(&node); err != nil {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// or range over All. Pages are only requested when the rows before them have been read,
+// stopping early skips the remaining pages.
+type SearchIterator struct {
+	client  *Client
+	ctx     context.Context
+	cancel  context.CancelFunc
+	query   SearchQuery
+	partial map[string]interface{}
+
+	prefetch bool
+	// pending receives the page requested ahead when prefetching
+	pending chan searchPage
+
+	// maxRows is the MaxRows of the client's settings, count the rows read so far
+	maxRows int
+	count   int
+
+	rows  []SearchRow
+	row   SearchRow
+	total int
+	pages int
+	done  bool
+	err   error
+}
+
+// searchPage is a page of rows or the error requesting it
+type searchPage struct {
+	rows  []SearchRow
+	total int
+	err   error
+}
+
+// Iterator returns an iterator over the rows of the search of index idx for statement
+func (e SearchService) Iterator(idx, statement string, opts *SearchOptions) *SearchIterator {
+	return e.IteratorWithContext(context.Background(), idx, statement, opts)
+}
+
+// IteratorWithContext is Iterator with a context for cancellation and deadlines.
+// The pages are requested with ctx. A malformed statement is reported by Err without
+// requesting any page.
+func (e SearchService) IteratorWithContext(ctx context.Context, idx, statement string, opts *SearchOptions) *SearchIterator {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	query, err := e.NewQuery(idx, statement)
+	if opts.PageSize > 0 {
+		query.Rows = opts.PageSize
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	it := &SearchIterator{
+		client:   e.client,
+		ctx:      ctx,
+		cancel:   cancel,
+		query:    query,
+		partial:  opts.Partial,
+		prefetch: opts.Prefetch,
+		maxRows:  e.client.searchSettings().MaxRows,
+		err:      err,
+	}
+	if err != nil {
+		it.Close()
+	}
+	return it
+}
+
+// errNilIterator is the error of a nil SearchIterator, as returned by a fake SearchAPI
+var errNilIterator = errors.New("nil search iterator")
+
+// Next advances to the next row, requesting the next page when the rows of the current
+// page have been read. It returns false at the end of the rows or after an error.
+func (it *SearchIterator) Next() bool {
+	if it == nil {
+		return false
+	}
+	for len(it.rows) == 0 {
+		if it.err != nil {
+			return false
+		}
+		if it.done {
+			it.Close()
+			return false
+		}
+		page := it.nextPage()
+		if page.err != nil {
+			it.err = page.err
+			it.Close()
+			return false
+		}
+		if it.pages > 0 {
+			if it.err = checkTotal(it.total, page.total); it.err != nil {
+				it.Close()
+				return false
+			}
+		}
+		it.rows = page.rows
+		it.total = page.total
+		it.pages++
+	}
+	if it.maxRows > 0 && it.count >= it.maxRows {
+		it.Close()
+		return false
+	}
+	it.count++
+	it.row, it.rows = it.rows[0], it.rows[1:]
+	return true
+}
+
+// Row returns the current row. Data holds the object, or the selected fields of a
+// partial search, Url is only set by partial searches.
+func (it *SearchIterator) Row() SearchRow {
+	if it == nil {
+		return SearchRow{}
+	}
+	return it.row
+}
+
+// Decode unmarshals the data of the current row into v
+func (it *SearchIterator) Decode(v interface{}) error {
+	if it == nil {
+		return errNilIterator
+	}
+	return json.Unmarshal(it.row.Data, v)
+}
+
+// Total returns the number of rows matched by the search, as reported by the pages
+func (it *SearchIterator) Total() int {
+	if it == nil {
+		return 0
+	}
+	return it.total
+}
+
+// Err returns the error that stopped the iterator, if any. A nil iterator reports an error.
+func (it *SearchIterator) Err() error {
+	if it == nil {
+		return errNilIterator
+	}
+	return it.err
+}
+
+// Close stops the iterator and cancels any prefetched page. Next returns false afterwards.
+func (it *SearchIterator) Close() {
+	if it == nil {
+		return
+	}
+	it.cancel()
+	it.done = true
+	it.rows = nil
+	it.pending = nil
+}
+
+// All returns the rows as a sequence for range loops. An error ends the sequence as
+// the last pair. Breaking out of the loop closes the iterator.
+func (it *SearchIterator) All() iter.Seq2[SearchRow, error] {
+	return func(yield func(SearchRow, error) bool) {
+		defer it.Close()
+		for it.Next() {
+			if !yield(it.row, nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(SearchRow{}, err)
+		}
+	}
+}
+
+// nextPage returns the next page, the prefetched one when there is one, and starts
+// prefetching the page after it
+func (it *SearchIterator) nextPage() searchPage {
+	var page searchPage
+	if it.pending != nil {
+		page = <-it.pending
+		it.pending = nil
+	} else {
+		page = it.fetch(it.query)
+	}
+	if page.err != nil {
+		return page
+	}
+
+	it.query.Start += len(page.rows)
+	it.done = len(page.rows) == 0 || it.query.Start >= page.total || (it.maxRows > 0 && it.query.Start >= it.maxRows)
+	if !it.done && it.prefetch {
+		// buffered so the request can finish after the iterator is closed
+		it.pending = make(chan searchPage, 1)
+		go func(query SearchQuery, pending chan<- searchPage) {
+			pending <- it.fetch(query)
+		}(it.query, it.pending)
+	}
+	return page
+}
+
+// fetch requests the page of query. It only reads fields that don't change after the
+// iterator is created, so it can run in a prefetch goroutine.
+func (it *SearchIterator) fetch(query SearchQuery) (page searchPage) {
+	res, err := fetchSearchPage(it.ctx, it.client, query, it.partial)
+	if err != nil {
+		page.err = err
+		return
+	}
+	page.total = res.Total
+	page.rows = make([]SearchRow, len(res.Rows))
+	for i, raw := range res.Rows {
+		if it.partial == nil {
+			page.rows[i] = SearchRow{Data: raw}
+			continue
+		}
+		// partial search rows are {"url": ..., "data": ...}
+		if page.err = json.Unmarshal(raw, &page.rows[i]); page.err != nil {
+			return
+		}
+	}
+	return
+}
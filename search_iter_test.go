@@ -0,0 +1,148 @@
+package chef
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// serveNodes answers node searches with total nodes named node0, node1, ... paged by
+// the start and rows parameters, counting the requests
+func serveNodes(total int, calls *int32) {
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		rows, _ := strconv.Atoi(r.URL.Query().Get("rows"))
+		var items []string
+		for i := start; i < start+rows && i < total; i++ {
+			if r.Method == http.MethodPost {
+				items = append(items, fmt.Sprintf(`{"url": "nodes/node%d", "data": {"name": "node%d"}}`, i, i))
+			} else {
+				items = append(items, fmt.Sprintf(`{"name": "node%d", "chef_type": "node"}`, i))
+			}
+		}
+		fmt.Fprintf(w, `{"total": %d, "start": %d, "rows": [%s]}`, total, start, strings.Join(items, ","))
+	})
+}
+
+func TestSearchIterator(t *testing.T) {
+	setup()
+	defer teardown()
+	var calls int32
+	serveNodes(5, &calls)
+
+	it := client.Search.Iterator("node", "name:*", &SearchOptions{PageSize: 2})
+	defer it.Close()
+	var names []string
+	for it.Next() {
+		var node Node
+		assert.Nil(t, it.Decode(&node))
+		assert.Empty(t, it.Row().Url)
+		names = append(names, node.Name)
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []string{"node0", "node1", "node2", "node3", "node4"}, names)
+	assert.Equal(t, 5, it.Total())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls), "pages requested")
+	assert.False(t, it.Next())
+}
+
+func TestSearchIteratorStopEarly(t *testing.T) {
+	setup()
+	defer teardown()
+	var calls int32
+	serveNodes(100, &calls)
+
+	it := client.Search.Iterator("node", "name:*", &SearchOptions{PageSize: 10})
+	count := 0
+	for row, err := range it.All() {
+		assert.Nil(t, err)
+		assert.Contains(t, string(row.Data), "node")
+		count++
+		if count == 15 {
+			break
+		}
+	}
+	assert.Equal(t, 15, count)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "only the pages read are requested")
+	assert.False(t, it.Next(), "the iterator is closed by the range loop")
+}
+
+func TestSearchIteratorPrefetch(t *testing.T) {
+	setup()
+	defer teardown()
+	var calls int32
+	serveNodes(25, &calls)
+
+	query := map[string]interface{}{"name": []string{"name"}}
+	it := client.Search.Iterator("node", "name:*", &SearchOptions{PageSize: 10, Prefetch: true, Partial: query})
+	var urls []string
+	for row, err := range it.All() {
+		assert.Nil(t, err)
+		var data map[string]string
+		assert.Nil(t, json.Unmarshal(row.Data, &data))
+		assert.Equal(t, "nodes/"+data["name"], row.Url)
+		urls = append(urls, row.Url)
+	}
+	assert.Len(t, urls, 25)
+	assert.Equal(t, "nodes/node24", urls[24])
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestSearchIteratorMalformed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	serveNodes(10, &calls)
+
+	it := client.Search.Iterator("node", "web1", &SearchOptions{Prefetch: true})
+	assert.False(t, it.Next())
+	assert.ErrorContains(t, it.Err(), "statement is malformed")
+	for _, err := range it.All() {
+		assert.ErrorContains(t, err, "statement is malformed")
+	}
+
+	_, err := SearchTyped[Node](client, "node", "web1")
+	assert.ErrorContains(t, err, "statement is malformed")
+	_, err = PartialSearchTyped[struct {
+		Name string `chef:"name"`
+	}](client, "node", "web1")
+	assert.ErrorContains(t, err, "statement is malformed")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls), "nothing sent to the server")
+}
+
+func TestSearchIteratorError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") != "0" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error": ["bad page"]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"total": 4, "start": 0, "rows": [{"name": "node0"}, {"name": "node1"}]}`)
+	})
+
+	var rows int
+	var errs []error
+	for _, err := range client.Search.Iterator("node", "name:*", &SearchOptions{PageSize: 2}).All() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rows++
+	}
+	assert.Equal(t, 2, rows)
+	if assert.Len(t, errs, 1) {
+		cerr, _ := ChefError(errs[0])
+		assert.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	}
+}
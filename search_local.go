@@ -0,0 +1,552 @@
+package chef
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LocalQuery is a search statement parsed to be matched against objects in memory, such
+// as a cached snapshot of the nodes or the fixtures of a test, without a server.
+// It understands the query syntax of the Chef server:
+//
+//   - field:value terms, where the value may hold * and ? wildcards, be quoted, or
+//     escape special characters with a backslash. A value without a field matches any field.
+//   - ranges, field:[from TO to] including the bounds and field:{from TO to} excluding
+//     them, with * for an open bound. Values are compared as strings, as the server
+//     does, so cpu_total:[2 TO 8] matches "32" but not "16".
+//   - AND, OR and NOT, also written &&, || and ! or as a leading -, and parentheses.
+//     Terms next to each other are joined with OR.
+//   - field:(a OR b) applying a field to the terms of a group.
+//   - *:* matching everything.
+//
+// Objects are matched on the fields the server indexes, see SearchFields.
+type LocalQuery struct {
+	statement string
+	q         localMatcher
+}
+
+// ParseQuery parses a search statement for matching objects in memory
+func ParseQuery(statement string) (*LocalQuery, error) {
+	p := &localParser{tokens: tokenizeQuery(statement)}
+	if len(p.tokens) == 0 {
+		return nil, errors.New("statement is empty")
+	}
+	q, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return &LocalQuery{statement: statement, q: q}, nil
+}
+
+// String returns the statement of the query
+func (q *LocalQuery) String() string {
+	return q.statement
+}
+
+// Match reports whether v matches the query. v is a Node, Role, Environment, ApiClient,
+// data bag item or any other value encoding to a json object.
+func (q *LocalQuery) Match(v interface{}) (bool, error) {
+	fields, err := SearchFields(v)
+	if err != nil {
+		return false, err
+	}
+	return q.MatchFields(fields), nil
+}
+
+// MatchFields reports whether an object with the search fields returned by SearchFields matches the query
+func (q *LocalQuery) MatchFields(fields map[string][]string) bool {
+	return q.q.match(fields)
+}
+
+// SearchLocal returns the items matching statement, in their order
+func SearchLocal[T any](items []T, statement string) ([]T, error) {
+	q, err := ParseQuery(statement)
+	if err != nil {
+		return nil, err
+	}
+	var matched []T
+	for _, item := range items {
+		ok, err := q.Match(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, item)
+		}
+	}
+	return matched, nil
+}
+
+// SearchDocument returns the object the server indexes for v. The attributes of a node
+// are merged into its top level, with automatic over override over normal over default
+// attributes, and the recipes and roles of its run list are added as the recipe and role
+// fields. A data bag item is indexed as its raw data and the name of its bag, in
+// data_bag. Other objects are indexed as they encode to json. The result shares no maps with v.
+func SearchDocument(v interface{}) (map[string]interface{}, error) {
+	obj, err := searchObject(v)
+	if err != nil {
+		return nil, err
+	}
+	_, isNode := v.(Node)
+	if n, ok := v.(*Node); ok && n != nil {
+		isNode = true
+	}
+
+	switch {
+	case isNode || obj["chef_type"] == "node" || obj["json_class"] == "Chef::Node":
+		return nodeDocument(obj), nil
+	case obj["chef_type"] == "data_bag_item":
+		// a data bag item as returned by a search of the bag
+		if raw, ok := obj["raw_data"].(map[string]interface{}); ok {
+			doc := map[string]interface{}{}
+			deepMerge(doc, raw)
+			if bag, ok := obj["data_bag"]; ok {
+				doc["data_bag"] = bag
+			}
+			return doc, nil
+		}
+	}
+	doc := map[string]interface{}{}
+	deepMerge(doc, obj)
+	return doc, nil
+}
+
+// SearchFields returns the fields the server indexes for v, see SearchDocument. Nested
+// keys are joined with underscores and every trailing part of a nested key is a field
+// too, {"kernel": {"machine": "x86_64"}} has the fields kernel_machine and machine. Each
+// element of an array is a value of its field.
+func SearchFields(v interface{}) (map[string][]string, error) {
+	doc, err := SearchDocument(v)
+	if err != nil {
+		return nil, err
+	}
+	return flattenFields(doc), nil
+}
+
+// searchObject returns v as a json object. Maps are used as they are, other values
+// are encoded to json and decoded with their numbers kept as written.
+func searchObject(v interface{}) (map[string]interface{}, error) {
+	if obj, ok := v.(map[string]interface{}); ok {
+		return obj, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("search objects are json objects: %w", err)
+	}
+	if obj == nil {
+		obj = map[string]interface{}{}
+	}
+	return obj, nil
+}
+
+// nodeDocument merges the attributes of a node into its top level fields and indexes
+// its run list as recipe and role fields
+func nodeDocument(node map[string]interface{}) map[string]interface{} {
+	doc := map[string]interface{}{}
+	for _, precedence := range []string{"default", "normal", "override", "automatic"} {
+		if attrs, ok := node[precedence].(map[string]interface{}); ok {
+			deepMerge(doc, attrs)
+		}
+	}
+	for k, v := range node {
+		switch k {
+		case "default", "normal", "override", "automatic":
+		default:
+			if m, ok := v.(map[string]interface{}); ok {
+				copied := map[string]interface{}{}
+				deepMerge(copied, m)
+				v = copied
+			}
+			doc[k] = v
+		}
+	}
+	var recipes, roles []interface{}
+	if runList, ok := node["run_list"].([]interface{}); ok {
+		for _, item := range runList {
+			entry, _ := item.(string)
+			switch {
+			case strings.HasPrefix(entry, "recipe[") && strings.HasSuffix(entry, "]"):
+				recipes = append(recipes, entry[len("recipe["):len(entry)-1])
+			case strings.HasPrefix(entry, "role[") && strings.HasSuffix(entry, "]"):
+				roles = append(roles, entry[len("role["):len(entry)-1])
+			}
+		}
+	}
+	if recipes != nil {
+		doc["recipe"] = recipes
+	}
+	if roles != nil {
+		doc["role"] = roles
+	}
+	return doc
+}
+
+// deepMerge merges src into dst, the values of src take precedence. The maps of src are copied.
+func deepMerge(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if srcMap, ok := v.(map[string]interface{}); ok {
+			if dstMap, ok := dst[k].(map[string]interface{}); ok {
+				deepMerge(dstMap, srcMap)
+				continue
+			}
+			copied := map[string]interface{}{}
+			deepMerge(copied, srcMap)
+			dst[k] = copied
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// flattenFields returns the search fields of a document
+func flattenFields(doc map[string]interface{}) map[string][]string {
+	fields := map[string][]string{}
+	var walk func(path []string, v interface{})
+	walk = func(path []string, v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, child := range t {
+				walk(append(path[:len(path):len(path)], k), child)
+			}
+		case []interface{}:
+			for _, child := range t {
+				walk(path, child)
+			}
+		default:
+			value := searchValue(t)
+			for i := range path {
+				key := strings.Join(path[i:], "_")
+				fields[key] = append(fields[key], value)
+			}
+		}
+	}
+	walk(nil, doc)
+	return fields
+}
+
+// searchValue formats a json value as a search value
+func searchValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// localMatcher is a parsed part of a LocalQuery
+type localMatcher interface {
+	match(fields map[string][]string) bool
+}
+
+type (
+	matchAll  struct{}
+	matchNot  struct{ q localMatcher }
+	matchAnd  struct{ l, r localMatcher }
+	matchOr   struct{ l, r localMatcher }
+	matchTerm struct {
+		// field is empty for a term matching any field
+		field string
+		value *regexp.Regexp
+	}
+	matchRange struct {
+		field            string
+		from, to         string
+		withFrom, withTo bool
+	}
+)
+
+func (matchAll) match(map[string][]string) bool     { return true }
+func (q matchNot) match(f map[string][]string) bool { return !q.q.match(f) }
+func (q matchAnd) match(f map[string][]string) bool { return q.l.match(f) && q.r.match(f) }
+func (q matchOr) match(f map[string][]string) bool  { return q.l.match(f) || q.r.match(f) }
+
+func (q matchTerm) match(fields map[string][]string) bool {
+	return matchValues(fields, q.field, q.value.MatchString)
+}
+
+func (q matchRange) match(fields map[string][]string) bool {
+	return matchValues(fields, q.field, q.contains)
+}
+
+// contains reports whether v is within the range
+func (q matchRange) contains(v string) bool {
+	if q.from != "*" {
+		c := compareSearchValues(v, q.from)
+		if c < 0 || (c == 0 && !q.withFrom) {
+			return false
+		}
+	}
+	if q.to != "*" {
+		c := compareSearchValues(v, q.to)
+		if c > 0 || (c == 0 && !q.withTo) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchValues reports whether a value of field, or of any field when field is empty, matches
+func matchValues(fields map[string][]string, field string, match func(string) bool) bool {
+	if field != "" {
+		for _, v := range fields[field] {
+			if match(v) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, values := range fields {
+		for _, v := range values {
+			if match(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareSearchValues compares two values the way the Chef server compares its indexed
+// fields: as strings, so numbers compare digit by digit and "10" sorts before "9"
+func compareSearchValues(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+// localParser parses the tokens of a statement. Lower precedence first: OR, AND, then NOT,
+// groups and terms.
+type localParser struct {
+	tokens []string
+	pos    int
+	// field applies to the terms without a field inside field:( ... )
+	field string
+}
+
+func (p *localParser) peek(offset int) string {
+	if p.pos+offset < len(p.tokens) {
+		return p.tokens[p.pos+offset]
+	}
+	return ""
+}
+
+func (p *localParser) or() (localMatcher, error) {
+	q, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch tok := p.peek(0); tok {
+		case "", ")", "AND", "&&":
+			return q, nil
+		case "OR", "||":
+			p.pos++
+		}
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		q = matchOr{q, right}
+	}
+}
+
+func (p *localParser) and() (localMatcher, error) {
+	q, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek(0) == "AND" || p.peek(0) == "&&" {
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		q = matchAnd{q, right}
+	}
+	return q, nil
+}
+
+func (p *localParser) unary() (localMatcher, error) {
+	tok := p.peek(0)
+	switch {
+	case tok == "":
+		return nil, errors.New("unexpected end of statement")
+	case tok == "NOT" || tok == "!" || tok == "-":
+		p.pos++
+		q, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return matchNot{q}, nil
+	case tok == "(":
+		p.pos++
+		q, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek(0) != ")" {
+			return nil, errors.New("missing )")
+		}
+		p.pos++
+		return q, nil
+	case tok == ")" || tok == "AND" || tok == "OR" || tok == "&&" || tok == "||":
+		return nil, fmt.Errorf("unexpected %q", tok)
+	case strings.HasSuffix(tok, ":") && !strings.HasSuffix(tok, `\:`) && p.peek(1) == "(":
+		// field:( ... ) applies the field to the terms of the group
+		p.pos++
+		outer := p.field
+		p.field = unescapeQuery(tok[:len(tok)-1])
+		q, err := p.unary()
+		p.field = outer
+		return q, err
+	}
+	p.pos++
+	return parseLocalTerm(tok, p.field)
+}
+
+// tokenizeQuery splits a statement into parentheses, operators and terms. A leading - or !
+// of a term is split off as an operator. Escapes, quotes and ranges are kept in the terms.
+func tokenizeQuery(q string) []string {
+	var tokens []string
+	for i := 0; i < len(q); {
+		switch c := q[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case (c == '-' || c == '!') && i+1 < len(q) && q[i+1] != ' ':
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			start := i
+			quoted, inRange := false, false
+			for ; i < len(q); i++ {
+				c := q[i]
+				if c == '\\' {
+					i++
+					continue
+				}
+				switch {
+				case c == '"' && !inRange:
+					quoted = !quoted
+				case (c == '[' || c == '{') && !quoted:
+					inRange = true
+				case (c == ']' || c == '}') && !quoted:
+					inRange = false
+				}
+				if !quoted && !inRange && (c == ' ' || c == '\t' || c == '\n' || c == '(' || c == ')') {
+					break
+				}
+			}
+			if i > len(q) {
+				i = len(q)
+			}
+			tokens = append(tokens, q[start:i])
+		}
+	}
+	return tokens
+}
+
+// parseLocalTerm parses field:value, or a bare value for the default field, or for any
+// field when there is none
+func parseLocalTerm(term, defaultField string) (localMatcher, error) {
+	if term == "*:*" {
+		return matchAll{}, nil
+	}
+	field, value := defaultField, term
+	for i := 0; i < len(term); i++ {
+		if term[i] == '\\' {
+			i++
+			continue
+		}
+		if term[i] == ':' {
+			field, value = unescapeQuery(term[:i]), term[i+1:]
+			break
+		}
+	}
+	if value == "" {
+		return nil, fmt.Errorf("missing value in %q", term)
+	}
+	if field == "*" {
+		field = ""
+	}
+
+	if r, ok, err := parseRange(field, value); ok || err != nil {
+		return r, err
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	quoted := false
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '\\' && i+1 < len(value):
+			i++
+			pattern.WriteString(regexp.QuoteMeta(value[i : i+1]))
+		case c == '"':
+			quoted = !quoted
+		case c == '*' && !quoted:
+			pattern.WriteString(".*")
+		case c == '?' && !quoted:
+			pattern.WriteString(".")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	pattern.WriteString("$")
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, err
+	}
+	return matchTerm{field: field, value: re}, nil
+}
+
+// parseRange parses [from TO to] and {from TO to}, reporting whether value is a range
+func parseRange(field, value string) (localMatcher, bool, error) {
+	if len(value) < 2 || (value[0] != '[' && value[0] != '{') {
+		return nil, false, nil
+	}
+	last := value[len(value)-1]
+	if last != ']' && last != '}' {
+		return nil, true, fmt.Errorf("unterminated range %q", value)
+	}
+	bounds := strings.Fields(value[1 : len(value)-1])
+	if len(bounds) != 3 || bounds[1] != "TO" {
+		return nil, true, fmt.Errorf("invalid range %q", value)
+	}
+	return matchRange{
+		field:    field,
+		from:     unescapeQuery(bounds[0]),
+		to:       unescapeQuery(bounds[2]),
+		withFrom: value[0] == '[',
+		withTo:   last == ']',
+	}, true, nil
+}
+
+// unescapeQuery removes the backslashes escaping characters
+func unescapeQuery(s string) string {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		out.WriteByte(s[i])
+	}
+	return out.String()
+}
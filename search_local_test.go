@@ -0,0 +1,148 @@
+package chef
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLocalNodes() []Node {
+	web := NewNode("web1")
+	web.Environment = "prod"
+	web.RunList = []string{"role[web]", "recipe[nginx::default]"}
+	web.AutomaticAttributes = map[string]interface{}{
+		"ipaddress": "10.0.0.1",
+		"platform":  "ubuntu",
+		"kernel":    map[string]interface{}{"machine": "x86_64"},
+		"cpu":       map[string]interface{}{"total": 8},
+	}
+	web.NormalAttributes = map[string]interface{}{"tags": []interface{}{"frontend", "public"}}
+
+	db := NewNode("db1")
+	db.Environment = "prod"
+	db.RunList = []string{"role[db]"}
+	db.DefaultAttributes = map[string]interface{}{"platform": "centos"}
+	db.AutomaticAttributes = map[string]interface{}{
+		"platform": "rhel",
+		"cpu":      map[string]interface{}{"total": 32},
+		"os_info":  "mac os x",
+	}
+
+	test := NewNode("test-web2")
+	test.Environment = "dev"
+	test.RunList = []string{"role[web]"}
+	test.AutomaticAttributes = map[string]interface{}{"cpu": map[string]interface{}{"total": 2}}
+	return []Node{web, db, test}
+}
+
+func localNames(t *testing.T, statement string) []string {
+	matched, err := SearchLocal(testLocalNodes(), statement)
+	assert.Nil(t, err, statement)
+	names := []string{}
+	for _, node := range matched {
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+func TestSearchLocalNodes(t *testing.T) {
+	cases := map[string][]string{
+		"*:*":                                {"web1", "db1", "test-web2"},
+		"name:web1":                          {"web1"},
+		"name:*web*":                         {"web1", "test-web2"},
+		"name:web?":                          {"web1"},
+		"chef_environment:prod AND role:web": {"web1"},
+		"role:web OR role:db":                {"web1", "db1", "test-web2"},
+		"role:web NOT name:test*":            {"web1", "db1", "test-web2"},
+		"role:web AND NOT name:test*":        {"web1"},
+		"role:web AND -name:test*":           {"web1"},
+		"recipe:nginx\\:\\:default":          {"web1"},
+		"kernel_machine:x86_64":              {"web1"},
+		"machine:x86_64":                     {"web1"},
+		"tags:public":                        {"web1"},
+		"platform:rhel":                      {"db1"},
+		"platform:centos":                    {},
+		// values compare as strings, as on the server: "32" sorts between "2" and "8"
+		"cpu_total:[2 TO 8]":                {"web1", "db1", "test-web2"},
+		"cpu_total:{2 TO 8}":                {"db1"},
+		"cpu_total:[8 TO 32]":               {},
+		"cpu_total:[10 TO *]":               {"web1", "db1", "test-web2"},
+		"cpu_total:[4 TO *]":                {"web1"},
+		"cpu_total:{* TO 8}":                {"db1", "test-web2"},
+		"role:(web OR db) AND cpu_total:32": {"db1"},
+		`os_info:"mac os x"`:                {"db1"},
+		"os_info:mac\\ os\\ x":              {"db1"},
+		"ipaddress:10.0.0.*":                {"web1"},
+		"10.0.0.1":                          {"web1"},
+	}
+	for statement, want := range cases {
+		assert.Equal(t, want, localNames(t, statement), statement)
+	}
+}
+
+func TestSearchLocalQueryBuilder(t *testing.T) {
+	q := And(Term("chef_environment", "prod"), Range(Attr("cpu", "total"), "30", "*", true))
+	assert.Equal(t, []string{"web1", "db1"}, localNames(t, q.String()))
+
+	q = And(Wildcard("name", "test-*"), Not(Term("role", "db")))
+	assert.Equal(t, []string{"test-web2"}, localNames(t, q.String()))
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, statement := range []string{"", "name:", "(name:web", "name:web AND", "AND name:web", "cpu:[1 TO", "cpu:[1 2]"} {
+		_, err := ParseQuery(statement)
+		assert.NotNil(t, err, statement)
+	}
+}
+
+func TestSearchLocalTypes(t *testing.T) {
+	role := Role{Name: "web", Description: "web servers", RunList: RunList{"recipe[nginx]"},
+		DefaultAttributes: map[string]interface{}{"nginx": map[string]interface{}{"port": 8080}}}
+	env := Environment{Name: "prod", CookbookVersions: map[string]string{"nginx": "= 1.2.0"}}
+	client := ApiClient{Name: "builder", Validator: true}
+
+	for statement, v := range map[string]interface{}{
+		"name:web AND nginx_port:8080":    role,
+		"description:web*":                &role,
+		"cookbook_versions_nginx:=*":      env,
+		"name:builder AND validator:true": client,
+	} {
+		q, err := ParseQuery(statement)
+		assert.Nil(t, err)
+		ok, err := q.Match(v)
+		assert.Nil(t, err)
+		assert.True(t, ok, statement)
+	}
+
+	// a data bag item alone or as returned by a search of the bag
+	item := map[string]interface{}{"id": "alice", "groups": []interface{}{"admin", "dev"}}
+	row := map[string]interface{}{"chef_type": "data_bag_item", "data_bag": "users", "raw_data": item}
+	q, err := ParseQuery("id:alice AND groups:admin")
+	assert.Nil(t, err)
+	for _, v := range []interface{}{item, row} {
+		ok, err := q.Match(v)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+	}
+	fields, err := SearchFields(row)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"users"}, fields["data_bag"])
+
+	_, err = q.Match([]string{"not", "an", "object"})
+	assert.NotNil(t, err)
+}
+
+func TestSearchDocumentNode(t *testing.T) {
+	node := testLocalNodes()[1]
+	doc, err := SearchDocument(node)
+	assert.Nil(t, err)
+	// automatic attributes take precedence
+	assert.Equal(t, "rhel", doc["platform"])
+	assert.Equal(t, []interface{}{"db"}, doc["role"])
+	assert.Nil(t, doc["automatic"])
+
+	// the node is left as it was
+	assert.Equal(t, "centos", node.DefaultAttributes["platform"])
+	doc["cpu"].(map[string]interface{})["total"] = 1
+	assert.Equal(t, 32, node.AutomaticAttributes["cpu"].(map[string]interface{})["total"])
+}
@@ -0,0 +1,160 @@
+package chef
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+)
+
+// partialField is a struct field filled from a partial search
+type partialField struct {
+	index []int
+	// key names the field in the search request and the returned rows
+	key  string
+	path []string
+}
+
+// partialFields returns the fields of struct type t tagged with the attribute path to
+// search for, as in `chef:"automatic.ipaddress"`. Fields of embedded structs are included.
+func partialFields(t reflect.Type) ([]partialField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("partial search needs a struct, not %s", t)
+	}
+	var fields []partialField
+	for _, f := range reflect.VisibleFields(t) {
+		tag, ok := f.Tag.Lookup("chef")
+		if !ok || tag == "-" || !f.IsExported() || behindUnexportedPointer(t, f.Index) {
+			continue
+		}
+		path := strings.Split(tag, ".")
+		for _, part := range path {
+			if part == "" {
+				return nil, fmt.Errorf("field %s: invalid attribute path %q", f.Name, tag)
+			}
+		}
+		fields = append(fields, partialField{index: f.Index, key: tag, path: path})
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s has no fields with a chef tag", t)
+	}
+	return fields, nil
+}
+
+// PartialSearchParams returns the params of a partial search for the fields of the struct
+// v tagged with attribute paths, for use with PartialExec and DoPartial:
+//
+//	type host struct {
+//		Name string   `chef:"name"`
+//		IP   string   `chef:"automatic.ipaddress"`
+//		Tags []string `chef:"normal.tags"`
+//	}
+//
+// gives {"name": ["name"], "automatic.ipaddress": ["automatic", "ipaddress"], "normal.tags": ["normal", "tags"]}
+func PartialSearchParams(v interface{}) (map[string]interface{}, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil, fmt.Errorf("partial search needs a struct, not nil")
+	}
+	fields, err := partialFields(t)
+	if err != nil {
+		return nil, err
+	}
+	return partialParams(fields), nil
+}
+
+// behindUnexportedPointer reports whether the field at index is promoted through an
+// embedded pointer to an unexported struct, which can't be allocated. encoding/json
+// skips these fields too.
+func behindUnexportedPointer(t reflect.Type, index []int) bool {
+	for _, x := range index[:len(index)-1] {
+		f := t.Field(x)
+		t = f.Type
+		if t.Kind() == reflect.Ptr {
+			if !f.IsExported() {
+				return true
+			}
+			t = t.Elem()
+		}
+	}
+	return false
+}
+
+func partialParams(fields []partialField) map[string]interface{} {
+	params := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		params[f.key] = f.path
+	}
+	return params
+}
+
+// PartialSearchTyped runs a partial search of index idx for statement, returning the
+// attributes named by the chef tags of the struct T and decoding each row into a T.
+// See PartialSearchParams for the tags. Attributes missing from a row leave the zero
+// value in their field. Rows that don't decode are reported in the Errors of the result.
+func PartialSearchTyped[T any](client *Client, idx, statement string) (TypedSearchResult[T], error) {
+	return PartialSearchTypedWithContext[T](context.Background(), client, idx, statement)
+}
+
+// PartialSearchTypedWithContext is PartialSearchTyped with a context for cancellation and deadlines.
+func PartialSearchTypedWithContext[T any](ctx context.Context, client *Client, idx, statement string) (TypedSearchResult[T], error) {
+	fields, err := partialFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return TypedSearchResult[T]{}, err
+	}
+	it := client.Search.IteratorWithContext(ctx, idx, statement, &SearchOptions{Partial: partialParams(fields)})
+	return collectRows(it, partialRows[T](it, fields))
+}
+
+// PartialRows returns the rows of it, a partial search with the params of PartialSearchParams
+// for T, decoded into T as a sequence for range loops. Errors are as for TypedRows.
+func PartialRows[T any](it *SearchIterator) iter.Seq2[T, error] {
+	fields, err := partialFields(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return func(yield func(T, error) bool) {
+			it.Close()
+			var zero T
+			yield(zero, err)
+		}
+	}
+	return partialRows[T](it, fields)
+}
+
+func partialRows[T any](it *SearchIterator, fields []partialField) iter.Seq2[T, error] {
+	return decodeRows(it, func(data json.RawMessage, item *T) error {
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		v := reflect.ValueOf(item).Elem()
+		for _, f := range fields {
+			raw, ok := values[f.key]
+			if !ok || string(raw) == "null" {
+				continue
+			}
+			if err := json.Unmarshal(raw, fieldByIndex(v, f.index).Addr().Interface()); err != nil {
+				return fmt.Errorf("%s: %w", f.key, err)
+			}
+		}
+		return nil
+	})
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex, allocating the nil embedded structs on the way
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,110 @@
+package chef
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type partialHost struct {
+	Name    string   `chef:"name"`
+	IP      string   `chef:"automatic.ipaddress"`
+	Tags    []string `chef:"normal.tags"`
+	Cores   int      `chef:"automatic.cpu.total"`
+	Ignored string
+}
+
+func TestPartialSearchParams(t *testing.T) {
+	params, err := PartialSearchParams(&partialHost{})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":                []string{"name"},
+		"automatic.ipaddress": []string{"automatic", "ipaddress"},
+		"normal.tags":         []string{"normal", "tags"},
+		"automatic.cpu.total": []string{"automatic", "cpu", "total"},
+	}, params)
+
+	_, err = PartialSearchParams(struct{ Name string }{})
+	assert.NotNil(t, err, "no tagged fields")
+	_, err = PartialSearchParams(struct {
+		Name string `chef:"normal..name"`
+	}{})
+	assert.NotNil(t, err, "empty path element")
+	_, err = PartialSearchParams("name")
+	assert.NotNil(t, err, "not a struct")
+}
+
+func TestPartialSearchTyped(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		body, _ := io.ReadAll(r.Body)
+		var params map[string][]string
+		assert.Nil(t, json.Unmarshal(body, &params))
+		assert.Equal(t, []string{"automatic", "ipaddress"}, params["automatic.ipaddress"])
+		fmt.Fprintf(w, `{"total": 3, "start": 0, "rows": [
+			{"url": "https://chef/nodes/web1", "data": {"name": "web1", "automatic.ipaddress": "10.0.0.1", "normal.tags": ["web"], "automatic.cpu.total": 4}},
+			{"url": "https://chef/nodes/web2", "data": {"name": "web2", "automatic.ipaddress": null}},
+			{"url": "https://chef/nodes/web3", "data": {"name": "web3", "automatic.cpu.total": "four"}}
+		]}`)
+	})
+
+	res, err := PartialSearchTyped[partialHost](client, "node", "role:web")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, []partialHost{
+		{Name: "web1", IP: "10.0.0.1", Tags: []string{"web"}, Cores: 4},
+		{Name: "web2"},
+	}, res.Rows)
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, 2, res.Errors[0].Index)
+		assert.Equal(t, "https://chef/nodes/web3", res.Errors[0].Url)
+		assert.Contains(t, res.Errors[0].Error(), "automatic.cpu.total")
+	}
+}
+
+func TestPartialSearchEmbedded(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"total": 1, "start": 0, "rows": [
+			{"url": "https://chef/nodes/db1", "data": {"name": "db1", "automatic.platform": "ubuntu"}}
+		]}`)
+	})
+
+	type Platform struct {
+		OS string `chef:"automatic.platform"`
+	}
+	type host struct {
+		Name string `chef:"name"`
+		*Platform
+	}
+	params, err := PartialSearchParams(host{})
+	assert.Nil(t, err)
+	var hosts []host
+	for h, err := range PartialRows[host](client.Search.Iterator("node", "name:db1", &SearchOptions{Partial: params})) {
+		assert.Nil(t, err)
+		hosts = append(hosts, h)
+	}
+	if assert.Len(t, hosts, 1) {
+		assert.Equal(t, "db1", hosts[0].Name)
+		assert.Equal(t, "ubuntu", hosts[0].OS)
+	}
+
+	type hidden struct {
+		Platform string `chef:"automatic.platform"`
+	}
+	params, err = PartialSearchParams(struct {
+		Name string `chef:"name"`
+		*hidden
+	}{})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"name": []string{"name"}}, params, "fields behind unexported pointers are skipped")
+}
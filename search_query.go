@@ -0,0 +1,145 @@
+package chef
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Query is a search statement built from terms, phrases, wildcards and ranges joined
+// with And, Or and Not. Values are escaped so they match literally:
+//
+//	q := chef.And(
+//		chef.Term("chef_environment", "prod"),
+//		chef.Or(chef.Term(chef.Attr("os"), "linux"), chef.Phrase("platform", "mac os x")),
+//		chef.Not(chef.Wildcard("name", "test-*")),
+//	)
+//	res, err := q.SearchQuery("node").Do(client)
+//
+// renders chef_environment:prod AND (os:linux OR platform:"mac os x") AND NOT name:test\-*
+type Query struct {
+	text string
+	// compound is set for statements that need parentheses inside another statement
+	compound bool
+}
+
+// luceneSpecial are the characters with a meaning in the Lucene query syntax
+const luceneSpecial = `+-&|!(){}[]^"~*?:\/`
+
+// EscapeQuery escapes the Lucene special characters and white space in s so it matches literally
+func EscapeQuery(s string) string {
+	return escapeQuery(s, "")
+}
+
+// escapeQuery escapes s, leaving the characters in keep as they are
+func escapeQuery(s, keep string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if (strings.ContainsRune(luceneSpecial, r) || unicode.IsSpace(r)) && !strings.ContainsRune(keep, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Attr returns the search field of a nested attribute. The server flattens nested
+// attributes by joining the keys with underscores, Attr("kernel", "machine") is kernel_machine.
+func Attr(path ...string) string {
+	return strings.Join(path, "_")
+}
+
+// Raw is a statement used as written, without escaping
+func Raw(statement string) Query {
+	return Query{text: statement, compound: true}
+}
+
+// All matches every item of an index
+func All() Query {
+	return Query{text: "*:*"}
+}
+
+// Term matches items whose field has the value
+func Term(field, value string) Query {
+	return Query{text: EscapeQuery(field) + ":" + EscapeQuery(value)}
+}
+
+// Phrase matches items whose field contains the words of phrase in order
+func Phrase(field, phrase string) Query {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(phrase)
+	return Query{text: EscapeQuery(field) + `:"` + escaped + `"`}
+}
+
+// Wildcard matches items whose field matches pattern, where * matches any characters
+// and ? a single character. The other special characters are escaped.
+func Wildcard(field, pattern string) Query {
+	return Query{text: EscapeQuery(field) + ":" + escapeQuery(pattern, "*?")}
+}
+
+// Range matches items whose field is between from and to, including the bounds when
+// inclusive is set. A bound of "*" leaves the range open on that side.
+func Range(field, from, to string, inclusive bool) Query {
+	open, end := "{", "}"
+	if inclusive {
+		open, end = "[", "]"
+	}
+	return Query{text: EscapeQuery(field) + ":" + open + rangeBound(from) + " TO " + rangeBound(to) + end}
+}
+
+func rangeBound(s string) string {
+	if s == "*" {
+		return s
+	}
+	return EscapeQuery(s)
+}
+
+// And matches items matched by every query. Empty queries are left out.
+func And(queries ...Query) Query {
+	return join(" AND ", queries)
+}
+
+// Or matches items matched by any of the queries. Empty queries are left out.
+func Or(queries ...Query) Query {
+	return join(" OR ", queries)
+}
+
+// Not matches items that q doesn't match
+func Not(q Query) Query {
+	if q.text == "" {
+		return q
+	}
+	return Query{text: "NOT " + q.group()}
+}
+
+func join(op string, queries []Query) Query {
+	var parts []string
+	var last Query
+	for _, q := range queries {
+		if q.text != "" {
+			parts = append(parts, q.group())
+			last = q
+		}
+	}
+	if len(parts) == 1 {
+		return last
+	}
+	return Query{text: strings.Join(parts, op), compound: len(parts) > 1}
+}
+
+// group returns the statement, in parentheses when it is compound
+func (q Query) group() string {
+	if q.compound {
+		return "(" + q.text + ")"
+	}
+	return q.text
+}
+
+// String returns the statement in the Lucene syntax
+func (q Query) String() string {
+	return q.text
+}
+
+// SearchQuery returns a search of the index for q. Its Rows and SortBy are left empty
+// so the SearchSettings of the client running it apply.
+func (q Query) SearchQuery(idx string) SearchQuery {
+	return SearchQuery{Index: idx, Query: q.text}
+}
@@ -0,0 +1,65 @@
+package chef
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeQuery(t *testing.T) {
+	assert.Equal(t, `web01`, EscapeQuery("web01"))
+	assert.Equal(t, `\/dev\/sda1`, EscapeQuery("/dev/sda1"))
+	assert.Equal(t, `a\&\&b\|\|c`, EscapeQuery("a&&b||c"))
+	assert.Equal(t, `\+\-\!\(\)\{\}\[\]\^\"\~\*\?\:\\`, EscapeQuery(`+-!(){}[]^"~*?:\`))
+	assert.Equal(t, `mac\ os\ x`, EscapeQuery("mac os x"))
+}
+
+func TestQueryTerms(t *testing.T) {
+	assert.Equal(t, `name:web01`, Term("name", "web01").String())
+	assert.Equal(t, `kernel_machine:x86_64`, Term(Attr("kernel", "machine"), "x86_64").String())
+	assert.Equal(t, `ec2\-id:i\-123`, Term("ec2-id", "i-123").String())
+	assert.Equal(t, `platform:"mac os \"x\""`, Phrase("platform", `mac os "x"`).String())
+	assert.Equal(t, `name:web\-*.example.co?`, Wildcard("name", "web-*.example.co?").String())
+	assert.Equal(t, `uptime_seconds:[100 TO *]`, Range("uptime_seconds", "100", "*", true).String())
+	assert.Equal(t, `name:{a TO m}`, Range("name", "a", "m", false).String())
+	assert.Equal(t, `*:*`, All().String())
+}
+
+func TestQueryGrouping(t *testing.T) {
+	q := And(
+		Term("chef_environment", "prod"),
+		Or(Term("os", "linux"), Phrase("platform", "mac os x")),
+		Not(Wildcard("name", "test-*")),
+	)
+	assert.Equal(t, `chef_environment:prod AND (os:linux OR platform:"mac os x") AND NOT name:test\-*`, q.String())
+
+	assert.Equal(t, `NOT (a:1 OR b:2)`, Not(Or(Term("a", "1"), Term("b", "2"))).String())
+	assert.Equal(t, `(role:web OR role:db) AND (x:1)`, And(Raw("role:web OR role:db"), Raw("x:1")).String())
+
+	// empty queries are left out
+	assert.Equal(t, `a:1`, And(Query{}, Term("a", "1"), Not(Query{})).String())
+	assert.Equal(t, ``, Or().String())
+}
+
+func TestSearchQueryString(t *testing.T) {
+	q := SearchQuery{Index: "node", Query: `name:a\&b\ c\+d`, SortBy: "X_CHEF_id_CHEF_X asc", Rows: 1000}
+	assert.Equal(t, `node?q=name%3Aa%5C%26b%5C+c%5C%2Bd&rows=1000&sort=X_CHEF_id_CHEF_X+asc&start=0`, q.String())
+}
+
+func TestQuerySearch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var got string
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query().Get("q")
+		fmt.Fprintf(w, `{"total": 0, "start": 0, "rows": []}`)
+	})
+
+	q := And(Term("name", "a&b c+d"), Term(Attr("filesystem", "mount"), "/var"))
+	_, err := q.SearchQuery("node").Do(client)
+	assert.Nil(t, err)
+	assert.Equal(t, `name:a\&b\ c\+d AND filesystem_mount:\/var`, got)
+}
@@ -1,11 +1,17 @@
 package chef
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -174,3 +180,268 @@ func TestSearch_PartialExecMultipleCalls(t *testing.T) {
 	assert.Equal(t, "node12", lastNode.Name)
 
 }
+
+func TestSearch_ExecWithContextCancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	mux.HandleFunc("/search/nodes", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// stop the search after the first page is served
+		cancel()
+		fmt.Fprintf(w, `{"total": 5000, "start": %s, "rows": []}`, r.URL.Query().Get("start"))
+	})
+
+	_, err := client.Search.ExecWithContext(ctx, "nodes", "name:latte")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls, "no pages requested after cancel")
+}
+
+func TestSearch_ClientSettings(t *testing.T) {
+	setup()
+	defer teardown()
+	var calls int32
+	serveNodes(25, &calls)
+
+	other, err := NewClient(&Config{
+		Name:                  userid,
+		Key:                   privateKeyPKCS1,
+		BaseURL:               server.URL,
+		AuthenticationVersion: "1.0",
+		SearchSettings:        &SearchSettings{PageSize: 10, SortBy: "name asc", MaxRows: 15},
+	})
+	assert.Nil(t, err)
+
+	query, err := other.Search.NewQuery("node", "name:*")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, query.Rows)
+	assert.Equal(t, "name asc", query.SortBy)
+
+	// the settings of one client don't change another
+	client.Search.PageSize(5)
+	query, err = client.Search.NewQuery("node", "name:*")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, query.Rows)
+	assert.Equal(t, "X_CHEF_id_CHEF_X asc", query.SortBy)
+	query, err = other.Search.NewQuery("node", "name:*")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, query.Rows)
+
+	// searches stop at MaxRows while Total counts every row
+	res, err := other.Search.Exec("node", "name:*")
+	assert.Nil(t, err)
+	assert.Equal(t, 25, res.Total)
+	assert.Len(t, res.Rows, 15)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	var names []string
+	for row, err := range other.Search.Iterator("node", "name:*", nil).All() {
+		assert.Nil(t, err)
+		names = append(names, string(row.Data))
+	}
+	assert.Len(t, names, 15)
+
+	// a query from the builder takes the page size of the client running it
+	res, err = Term("name", "node1").SearchQuery("node").Do(client)
+	assert.Nil(t, err)
+	assert.Len(t, res.Rows, 5)
+}
+
+func TestSearch_PageSizeRace(t *testing.T) {
+	setup()
+	defer teardown()
+	var calls int32
+	serveNodes(30, &calls)
+
+	// searches running while the page size changes keep their own page size
+	var wg sync.WaitGroup
+	for i := 1; i <= 4; i++ {
+		wg.Add(2)
+		go func(size int) {
+			defer wg.Done()
+			client.Search.PageSize(size)
+		}(i * 3)
+		go func() {
+			defer wg.Done()
+			res, err := client.Search.Exec("node", "name:*")
+			assert.Nil(t, err)
+			assert.Len(t, res.Rows, 30)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSearch_TotalChanged(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		// a node is added after the first page
+		total := 3
+		if r.URL.Query().Get("start") != "0" {
+			total = 4
+		}
+		fmt.Fprintf(w, `{"total": %d, "start": 0, "rows": [{"name": "node0"}, {"name": "node1"}]}`, total)
+	})
+	client.Search.PageSize(2)
+
+	res, err := client.Search.Exec("node", "name:*")
+	assert.ErrorIs(t, err, ErrSearchChanged)
+	assert.Equal(t, 3, res.Total)
+	assert.Len(t, res.Rows, 2)
+
+	_, err = client.Search.PartialExecJSON("node", "name:*", map[string]interface{}{"name": []string{"name"}})
+	assert.ErrorIs(t, err, ErrSearchChanged)
+
+	it := client.Search.Iterator("node", "name:*", nil)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	assert.Equal(t, 2, count)
+	assert.ErrorIs(t, it.Err(), ErrSearchChanged)
+}
+
+// searchClient returns a client of the test server with the search settings
+func searchClient(t *testing.T, settings *SearchSettings) *Client {
+	c, err := NewClient(&Config{
+		Name:                  userid,
+		Key:                   privateKeyPKCS1,
+		BaseURL:               server.URL,
+		AuthenticationVersion: "1.0",
+		RetryPolicy:           &RetryPolicy{MaxAttempts: 1, MinBackoff: time.Millisecond},
+		SearchSettings:        settings,
+	})
+	assert.Nil(t, err)
+	return c
+}
+
+func TestSearch_Concurrency(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var inFlight, most int32
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&most)
+			if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+				break
+			}
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		// later pages answer first, the rows must still come back in order
+		time.Sleep(time.Duration(100-start) * time.Millisecond / 10)
+		var rows []string
+		for i := start; i < start+10 && i < 95; i++ {
+			rows = append(rows, fmt.Sprintf(`{"name": "node%d"}`, i))
+		}
+		fmt.Fprintf(w, `{"total": 95, "start": %d, "rows": [%s]}`, start, strings.Join(rows, ","))
+	})
+
+	c := searchClient(t, &SearchSettings{PageSize: 10, Concurrency: 4})
+	res, err := c.Search.Exec("node", "name:*")
+	assert.Nil(t, err)
+	assert.Equal(t, 95, res.Total)
+	if assert.Len(t, res.Rows, 95) {
+		for i, row := range res.Rows {
+			assert.Equal(t, fmt.Sprintf("node%d", i), row.(map[string]interface{})["name"])
+		}
+	}
+	assert.LessOrEqual(t, atomic.LoadInt32(&most), int32(4), "pages in flight")
+	assert.Greater(t, atomic.LoadInt32(&most), int32(1), "pages in flight")
+}
+
+func TestSearch_PageRetries(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls, failures int32
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		start := r.URL.Query().Get("start")
+		// the third page fails once
+		if start == "20" && atomic.AddInt32(&failures, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, `{"error": ["try again"]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"total": 35, "start": %s, "rows": [{"url": "nodes/n%s", "data": {"name": "n%s"}}]}`, start, start, start)
+	})
+	params := map[string]interface{}{"name": []string{"name"}}
+
+	// without retries the search fails
+	c := searchClient(t, &SearchSettings{PageSize: 10})
+	_, err := c.Search.PartialExecJSON("node", "name:*", params)
+	cerr, _ := ChefError(err)
+	if assert.NotNil(t, cerr) {
+		assert.Equal(t, http.StatusServiceUnavailable, cerr.StatusCode())
+	}
+
+	// with retries only the failed page is requested again
+	atomic.StoreInt32(&calls, 0)
+	atomic.StoreInt32(&failures, 0)
+	c = searchClient(t, &SearchSettings{PageSize: 10, Concurrency: 2, PageRetries: 2})
+	res, err := c.Search.PartialExecJSON("node", "name:*", params)
+	assert.Nil(t, err)
+	if assert.Len(t, res.Rows, 4) {
+		assert.Equal(t, "nodes/n20", res.Rows[2].Url)
+	}
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+
+	// errors that aren't transient are not retried
+	mux.HandleFunc("/search/role", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	atomic.StoreInt32(&calls, 0)
+	_, err = c.Search.Exec("role", "name:*")
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestSearch_PageRetriesReplaceRetryPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, `{"error": ["try again"]}`)
+	})
+
+	for _, tc := range []struct {
+		pageRetries int
+		want        int32
+	}{
+		{0, 3}, // the RetryPolicy alone
+		{1, 2}, // the page retries alone, not 3*2
+	} {
+		c := searchClient(t, &SearchSettings{PageRetries: tc.pageRetries})
+		c.Retry = &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}
+		atomic.StoreInt32(&calls, 0)
+		_, err := c.Search.Exec("node", "name:*")
+		assert.NotNil(t, err)
+		assert.Equal(t, tc.want, atomic.LoadInt32(&calls), "page retries %d", tc.pageRetries)
+	}
+}
+
+func TestSearch_PageSizeWithoutClient(t *testing.T) {
+	defer fallbackSearchPageSize.Store(0)
+
+	// services not made by NewClient keep the page size for every such client
+	assert.NotPanics(t, func() { SearchService{}.PageSize(7) })
+	assert.Equal(t, 7, (&Client{}).searchSettings().PageSize)
+	literal := &Client{}
+	SearchService{client: literal}.PageSize(9)
+	assert.Equal(t, 9, literal.searchSettings().PageSize)
+
+	c := searchClient(t, nil)
+	assert.Equal(t, defaultSearchPageSize, c.searchSettings().PageSize, "clients from NewClient have their own")
+}
@@ -0,0 +1,105 @@
+package chef
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+)
+
+// TypedSearchResult holds the rows of a search decoded into T
+type TypedSearchResult[T any] struct {
+	Total int
+	// Rows are the rows that were decoded, in the order of the search
+	Rows []T
+	// Errors are the rows that couldn't be decoded
+	Errors []*SearchRowError
+}
+
+// SearchRowError reports a search row that couldn't be decoded
+type SearchRowError struct {
+	// Index is the position of the row in the search results
+	Index int
+	// Url is the url of the row, set by partial searches
+	Url  string
+	Data json.RawMessage
+	Err  error
+}
+
+func (e *SearchRowError) Error() string {
+	return fmt.Sprintf("search row %d: %v", e.Index, e.Err)
+}
+
+func (e *SearchRowError) Unwrap() error {
+	return e.Err
+}
+
+// SearchTyped runs the search of index idx for statement and decodes each row into T,
+// for example Node for the node index, Role, Environment or ApiClient. Data bag items
+// are decoded from their raw data, so T is the type of the items. Rows that don't
+// decode are reported in the Errors of the result, the error is for the search itself.
+func SearchTyped[T any](client *Client, idx, statement string) (TypedSearchResult[T], error) {
+	return SearchTypedWithContext[T](context.Background(), client, idx, statement)
+}
+
+// SearchTypedWithContext is SearchTyped with a context for cancellation and deadlines.
+func SearchTypedWithContext[T any](ctx context.Context, client *Client, idx, statement string) (TypedSearchResult[T], error) {
+	it := client.Search.IteratorWithContext(ctx, idx, statement, nil)
+	return collectRows(it, TypedRows[T](it))
+}
+
+// TypedRows returns the rows of it decoded into T as a sequence for range loops. A row
+// that doesn't decode is yielded with a *SearchRowError and the sequence goes on, an
+// error of the search ends it.
+func TypedRows[T any](it *SearchIterator) iter.Seq2[T, error] {
+	return decodeRows(it, func(data json.RawMessage, item *T) error {
+		return decodeSearchRow(data, item)
+	})
+}
+
+// decodeRows returns the rows of it decoded with decode
+func decodeRows[T any](it *SearchIterator, decode func(json.RawMessage, *T) error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		index := 0
+		for row, err := range it.All() {
+			var item T
+			if err == nil {
+				if decodeErr := decode(row.Data, &item); decodeErr != nil {
+					err = &SearchRowError{Index: index, Url: row.Url, Data: row.Data, Err: decodeErr}
+				}
+				index++
+			}
+			if !yield(item, err) {
+				return
+			}
+		}
+	}
+}
+
+// collectRows gathers the rows of a sequence from decodeRows into a result
+func collectRows[T any](it *SearchIterator, rows iter.Seq2[T, error]) (res TypedSearchResult[T], err error) {
+	for item, rowErr := range rows {
+		if rowErr == nil {
+			res.Rows = append(res.Rows, item)
+		} else if decodeErr, ok := rowErr.(*SearchRowError); ok {
+			res.Errors = append(res.Errors, decodeErr)
+		} else {
+			err = rowErr
+		}
+	}
+	res.Total = it.Total()
+	return
+}
+
+// decodeSearchRow unmarshals a row into v. Data bag items are wrapped by the server
+// with their name and bag, v receives the raw_data of the item.
+func decodeSearchRow(data json.RawMessage, v interface{}) error {
+	var item struct {
+		ChefType string          `json:"chef_type"`
+		RawData  json.RawMessage `json:"raw_data"`
+	}
+	if json.Unmarshal(data, &item) == nil && item.ChefType == "data_bag_item" && item.RawData != nil {
+		data = item.RawData
+	}
+	return json.Unmarshal(data, v)
+}
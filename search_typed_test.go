@@ -0,0 +1,75 @@
+package chef
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTyped(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/search/node", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"total": 3, "start": 0, "rows": [
+			{"name": "web1", "chef_environment": "prod", "chef_type": "node", "run_list": ["recipe[nginx]"]},
+			{"name": 42, "chef_type": "node"},
+			{"name": "web2", "chef_environment": "prod", "chef_type": "node"}
+		]}`)
+	})
+
+	res, err := SearchTyped[Node](client, "node", "chef_environment:prod")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, res.Total)
+	if assert.Len(t, res.Rows, 2) {
+		assert.Equal(t, "web1", res.Rows[0].Name)
+		assert.Equal(t, []string{"recipe[nginx]"}, res.Rows[0].RunList)
+		assert.Equal(t, "web2", res.Rows[1].Name)
+	}
+	if assert.Len(t, res.Errors, 1) {
+		assert.Equal(t, 1, res.Errors[0].Index)
+		assert.Contains(t, res.Errors[0].Error(), "search row 1")
+		assert.JSONEq(t, `{"name": 42, "chef_type": "node"}`, string(res.Errors[0].Data))
+	}
+}
+
+func TestSearchTypedDataBag(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/search/users", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"total": 1, "start": 0, "rows": [{
+			"name": "data_bag_item_users_alice",
+			"data_bag": "users",
+			"chef_type": "data_bag_item",
+			"json_class": "Chef::DataBagItem",
+			"raw_data": {"id": "alice", "shell": "/bin/zsh"}
+		}]}`)
+	})
+
+	type user struct {
+		ID    string `json:"id"`
+		Shell string `json:"shell"`
+	}
+	res, err := SearchTyped[user](client, "users", "id:alice")
+	assert.Nil(t, err)
+	assert.Empty(t, res.Errors)
+	assert.Equal(t, []user{{ID: "alice", Shell: "/bin/zsh"}}, res.Rows)
+}
+
+func TestSearchTypedError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/search/role", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"error": ["invalid search query"]}`)
+	})
+
+	res, err := SearchTyped[Role](client, "role", "name:(")
+	assert.NotNil(t, err)
+	assert.Empty(t, res.Rows)
+	assert.Empty(t, res.Errors)
+}
@@ -0,0 +1,74 @@
+package chef
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// Signer produces the signature of the canonical request content built by
+// AuthConfig.SignatureContent. AuthConfig uses its Signer, when set, in place of PrivateKey.
+type Signer interface {
+	// Sign returns the raw signature bytes of content for the authentication protocol version
+	Sign(version AuthVersion, content string) ([]byte, error)
+}
+
+// NewSigner adapts a crypto.Signer holding an RSA key to the Chef signing protocols.
+// The private key never has to be loaded into this process, which allows keys held
+// by an HSM or an external key agent.
+//
+// Protocol 1.3 signs the SHA256 digest of the content with PKCS #1 v1.5.
+// Protocol 1.0 is an unhashed RSA private key encryption of the content. It is
+// requested by calling Sign with crypto.Hash(0), which *rsa.PrivateKey supports
+// but some key agents refuse.
+func NewSigner(s crypto.Signer) (Signer, error) {
+	if s == nil {
+		return nil, errors.New("signer is nil")
+	}
+	if _, ok := s.Public().(*rsa.PublicKey); !ok {
+		return nil, fmt.Errorf("chef requests must be signed with an RSA key, not %T", s.Public())
+	}
+	return cryptoSigner{signer: s}, nil
+}
+
+// cryptoSigner implements Signer with a crypto.Signer
+type cryptoSigner struct {
+	signer crypto.Signer
+}
+
+// Sign implements Signer
+func (c cryptoSigner) Sign(version AuthVersion, content string) ([]byte, error) {
+	if version == AuthVersion13 {
+		hashed := sha256.Sum256([]byte(content))
+		return c.signer.Sign(rand.Reader, hashed[:], crypto.SHA256)
+	}
+	return c.signer.Sign(rand.Reader, []byte(content), crypto.Hash(0))
+}
+
+// rsaKeySigner implements Signer with an in memory RSA private key. This is the
+// signer used when AuthConfig.Signer is not set.
+type rsaKeySigner struct {
+	key *rsa.PrivateKey
+}
+
+// Sign implements Signer
+func (r rsaKeySigner) Sign(version AuthVersion, content string) ([]byte, error) {
+	if r.key == nil {
+		return nil, errors.New("no private key or signer configured")
+	}
+	if version == AuthVersion13 {
+		return GenerateDigestSignature(r.key, content)
+	}
+	return GenerateSignature(r.key, content)
+}
+
+// signer returns the Signer used to sign requests
+func (ac AuthConfig) signer() Signer {
+	if ac.Signer != nil {
+		return ac.Signer
+	}
+	return rsaKeySigner{key: ac.PrivateKey}
+}
@@ -0,0 +1,123 @@
+package chef
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/ctdk/goiardi/chefcrypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// softwareSigner stands in for an external key agent. Callers only see the
+// crypto.Signer methods, never the key itself.
+type softwareSigner struct {
+	key   *rsa.PrivateKey
+	calls int
+}
+
+func (s *softwareSigner) Public() crypto.PublicKey { return &s.key.PublicKey }
+
+func (s *softwareSigner) Sign(r io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	s.calls++
+	return s.key.Sign(r, digest, opts)
+}
+
+func newSoftwareSigner(t *testing.T) *softwareSigner {
+	pk, err := PrivateKeyFromString([]byte(privateKeyPKCS1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &softwareSigner{key: pk}
+}
+
+func signedRequest(t *testing.T, c *Client) *http.Request {
+	var captured *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = r
+	}))
+	defer ts.Close()
+	c.BaseURL.Host = ts.Listener.Addr().String()
+
+	req, err := c.NewRequest("GET", "nodes", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Do(req, nil)
+	assert.Nil(t, err)
+	return captured
+}
+
+func TestSigner_Version13(t *testing.T) {
+	soft := newSoftwareSigner(t)
+	c, err := NewClient(&Config{
+		Name:                  userid,
+		Signer:                soft,
+		BaseURL:               "http://localhost/",
+		AuthenticationVersion: AuthVersion13,
+	})
+	if !assert.Nil(t, err, "client with a crypto.Signer") {
+		return
+	}
+	assert.Nil(t, c.Auth.PrivateKey, "no key material in the client")
+
+	r := signedRequest(t, c)
+	assert.Equal(t, 1, soft.calls, "external signer used")
+	assert.Equal(t, "version=1.3", r.Header.Get("X-Ops-Sign"))
+
+	vals := map[string]string{}
+	for _, h := range []string{"X-Ops-Content-Hash", "X-Ops-Sign", "X-Ops-Timestamp", "X-Ops-UserId", "X-Ops-Server-API-Version"} {
+		vals[h] = r.Header.Get(h)
+	}
+	vals["Method"] = r.Method
+	vals["Path"] = r.URL.Path
+	content := c.Auth.SignatureContent(vals)
+
+	signed, err := assembleSignedHeader(r)
+	assert.Nil(t, err)
+	sig, err := base64.StdEncoding.DecodeString(signed)
+	assert.Nil(t, err)
+	hashed := sha256.Sum256([]byte(content))
+	assert.Nil(t, rsa.VerifyPKCS1v15(&soft.key.PublicKey, crypto.SHA256, hashed[:], sig), "1.3 signature verifies")
+}
+
+func TestSigner_Version10Adapter(t *testing.T) {
+	soft := newSoftwareSigner(t)
+	c, err := NewClient(&Config{
+		Name:    userid,
+		Signer:  soft,
+		BaseURL: "http://localhost/",
+	})
+	if !assert.Nil(t, err, "client with a crypto.Signer") {
+		return
+	}
+
+	r := signedRequest(t, c)
+	assert.Equal(t, 1, soft.calls, "external signer used")
+
+	signed, err := assembleSignedHeader(r)
+	assert.Nil(t, err)
+	decrypted, err := HeaderDecrypt(publicKeyPKCS1, signed)
+	assert.Nil(t, err, "1.0 signature decrypts with the public key")
+	want := "Method:GET\nHashed Path:" + HashStr("/nodes") +
+		"\nX-Ops-Content-Hash:" + r.Header.Get("X-Ops-Content-Hash") +
+		"\nX-Ops-Timestamp:" + r.Header.Get("X-Ops-Timestamp") +
+		"\nX-Ops-UserId:" + userid
+	assert.Equal(t, want, string(decrypted))
+}
+
+func TestNewSigner_NonRSA(t *testing.T) {
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, err := NewSigner(ec)
+	assert.NotNil(t, err, "EC keys are rejected")
+	_, err = NewSigner(nil)
+	assert.NotNil(t, err, "nil signer rejected")
+}
@@ -0,0 +1,195 @@
+package chef
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LoadTrustedCerts adds every PEM encoded certificate found in the files of dir to pool,
+// the way knife uses its trusted_certs directory. A nil pool starts from the system roots.
+// Files that hold no certificates are skipped.
+func LoadTrustedCerts(dir string, pool *x509.CertPool) (*x509.CertPool, error) {
+	if pool == nil {
+		system, err := x509.SystemCertPool()
+		if err != nil {
+			system = x509.NewCertPool()
+		}
+		pool = system
+	} else {
+		pool = pool.Clone()
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		pool.AppendCertsFromPEM(data)
+	}
+	return pool, nil
+}
+
+// rootCAs returns the root pool for cfg, RootCAs plus any certificates in TrustedCertsDir.
+// A TrustedCertsDir that doesn't exist is ignored, as knife does.
+func (cfg *Config) rootCAs() (*x509.CertPool, error) {
+	if cfg.TrustedCertsDir == "" {
+		return cfg.RootCAs, nil
+	}
+	pool, err := LoadTrustedCerts(cfg.TrustedCertsDir, cfg.RootCAs)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg.RootCAs, nil
+	}
+	return pool, err
+}
+
+// serverAddr returns the host name and the host:port address of a Chef server URL
+func serverAddr(serverURL string) (host, addr string, err error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Hostname() == "" {
+		return "", "", fmt.Errorf("no host in %q", serverURL)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	return u.Hostname(), net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// peerCertificates connects to the server and returns the certificate chain it presents,
+// without verifying it
+func peerCertificates(ctx context.Context, serverURL string) (string, []*x509.Certificate, error) {
+	host, addr, err := serverAddr(serverURL)
+	if err != nil {
+		return "", nil, err
+	}
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 30 * time.Second},
+		Config:    &tls.Config{ServerName: host, InsecureSkipVerify: true},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return host, nil, err
+	}
+	defer conn.Close()
+	return host, conn.(*tls.Conn).ConnectionState().PeerCertificates, nil
+}
+
+// unsafeFileChars are replaced in the certificate file names written by FetchServerCerts
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// FetchServerCerts connects to the Chef server and writes each certificate of the chain
+// it presents into dir, like knife ssl fetch. Files are named after the certificate
+// common name, with a leading "*" written as "wildcard". The certificates are not
+// verified, check them before trusting them. The paths of the written files are returned.
+func FetchServerCerts(ctx context.Context, serverURL, dir string) ([]string, error) {
+	host, certs, err := peerCertificates(ctx, serverURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, cert := range certs {
+		name := cert.Subject.CommonName
+		if name == "" {
+			name = host
+		}
+		name = strings.Replace(name, "*", "wildcard", 1)
+		path := filepath.Join(dir, unsafeFileChars.ReplaceAllString(name, "_")+".crt")
+		data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// SSLProblem classifies why a server certificate failed verification
+type SSLProblem string
+
+const (
+	SSLHostnameMismatch   SSLProblem = "hostname mismatch"
+	SSLUnknownAuthority   SSLProblem = "unknown authority"
+	SSLExpired            SSLProblem = "expired or not yet valid"
+	SSLInvalidCertificate SSLProblem = "invalid certificate"
+)
+
+// SSLCheckError reports a server certificate that doesn't verify
+type SSLCheckError struct {
+	Host    string
+	Problem SSLProblem
+	// Chain is the certificate chain the server presented
+	Chain []*x509.Certificate
+	// Err is the verification error from crypto/x509
+	Err error
+}
+
+func (e *SSLCheckError) Error() string {
+	return fmt.Sprintf("ssl check of %s failed, %s: %v", e.Host, e.Problem, e.Err)
+}
+
+func (e *SSLCheckError) Unwrap() error {
+	return e.Err
+}
+
+// CheckServerCert connects to the Chef server and verifies the certificate chain it
+// presents against roots and the server host name, like knife ssl check. A nil roots
+// uses the system roots. Verification failures are returned as an *SSLCheckError.
+func CheckServerCert(ctx context.Context, serverURL string, roots *x509.CertPool) error {
+	host, certs, err := peerCertificates(ctx, serverURL)
+	if err != nil {
+		return err
+	}
+	if len(certs) == 0 {
+		return &SSLCheckError{Host: host, Problem: SSLInvalidCertificate, Err: errors.New("no certificate presented")}
+	}
+
+	opts := x509.VerifyOptions{DNSName: host, Roots: roots, Intermediates: x509.NewCertPool()}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err = certs[0].Verify(opts)
+	if err == nil {
+		return nil
+	}
+
+	checkErr := &SSLCheckError{Host: host, Problem: SSLInvalidCertificate, Chain: certs, Err: err}
+	var (
+		hostErr      x509.HostnameError
+		authorityErr x509.UnknownAuthorityError
+		invalidErr   x509.CertificateInvalidError
+	)
+	switch {
+	case errors.As(err, &hostErr):
+		checkErr.Problem = SSLHostnameMismatch
+	case errors.As(err, &authorityErr):
+		checkErr.Problem = SSLUnknownAuthority
+	case errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired:
+		checkErr.Problem = SSLExpired
+	}
+	return checkErr
+}
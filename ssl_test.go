@@ -0,0 +1,91 @@
+package chef
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTLSChefServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/nodes/node1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"node1"}`)
+	})
+	return httptest.NewTLSServer(mux)
+}
+
+func TestFetchServerCerts(t *testing.T) {
+	ts := newTLSChefServer()
+	defer ts.Close()
+	dir := filepath.Join(t.TempDir(), "trusted_certs")
+
+	paths, err := FetchServerCerts(context.Background(), ts.URL, dir)
+	assert.Nil(t, err)
+	if assert.Len(t, paths, 1) {
+		data, err := os.ReadFile(paths[0])
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(data), "-----BEGIN CERTIFICATE-----"))
+	}
+
+	pool, err := LoadTrustedCerts(dir, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, CheckServerCert(context.Background(), ts.URL, pool), "fetched certificate is trusted")
+}
+
+func TestCheckServerCert(t *testing.T) {
+	ts := newTLSChefServer()
+	defer ts.Close()
+
+	err := CheckServerCert(context.Background(), ts.URL, nil)
+	var checkErr *SSLCheckError
+	if assert.ErrorAs(t, err, &checkErr) {
+		assert.Equal(t, SSLUnknownAuthority, checkErr.Problem)
+		assert.Equal(t, "127.0.0.1", checkErr.Host)
+		assert.Len(t, checkErr.Chain, 1)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	localhost := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+	err = CheckServerCert(context.Background(), localhost, pool)
+	if assert.ErrorAs(t, err, &checkErr) {
+		assert.Equal(t, SSLHostnameMismatch, checkErr.Problem)
+	}
+
+	err = CheckServerCert(context.Background(), "https://127.0.0.1:1", pool)
+	assert.NotNil(t, err)
+	assert.False(t, errors.As(err, &checkErr), "connection errors are returned as is")
+}
+
+func TestNewClient_TrustedCertsDir(t *testing.T) {
+	ts := newTLSChefServer()
+	defer ts.Close()
+	dir := t.TempDir()
+
+	cfg := &Config{Name: userid, Key: privateKeyPKCS1, BaseURL: ts.URL + "/", TrustedCertsDir: dir}
+	c, err := NewClient(cfg)
+	assert.Nil(t, err)
+	_, err = c.Nodes.Get("node1")
+	assert.NotNil(t, err, "server certificate isn't trusted yet")
+
+	_, err = FetchServerCerts(context.Background(), ts.URL, dir)
+	assert.Nil(t, err)
+	c, err = NewClient(cfg)
+	assert.Nil(t, err)
+	node, err := c.Nodes.Get("node1")
+	assert.Nil(t, err)
+	assert.Equal(t, "node1", node.Name)
+
+	cfg.TrustedCertsDir = filepath.Join(dir, "missing")
+	_, err = NewClient(cfg)
+	assert.Nil(t, err, "a missing directory is ignored")
+}
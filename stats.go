@@ -1,5 +1,7 @@
 package chef
 
+import "context"
+
 type StatsService struct {
 	client *Client
 }
@@ -15,7 +17,12 @@ type Stats []map[string]interface{}
 // This module only implements the json option. using a struct for the parse
 // out data will force JSON output.
 func (e *StatsService) Get(user string, password string) (data Stats, err error) {
+	return e.GetWithContext(context.Background(), user, password)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *StatsService) GetWithContext(ctx context.Context, user string, password string) (data Stats, err error) {
 	format := "json"
-	err = e.client.basicRequestDecoder("GET", "_stats?format="+format, nil, &data, user, password)
+	err = e.client.Global().basicRequestDecoderWithContext(ctx, "GET", "_stats?format="+format, nil, &data, user, password)
 	return
 }
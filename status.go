@@ -1,5 +1,7 @@
 package chef
 
+import "context"
+
 type StatusService struct {
 	client *Client
 }
@@ -15,6 +17,11 @@ type Status struct {
 //
 // https://docs.chef.io/api_chef_server/#_status
 func (e *StatusService) Get() (data Status, err error) {
-	err = e.client.magicRequestDecoder("GET", "_status", nil, &data)
+	return e.GetWithContext(context.Background())
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *StatusService) GetWithContext(ctx context.Context) (data Status, err error) {
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "GET", "_status", nil, &data)
 	return
 }
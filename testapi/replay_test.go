@@ -0,0 +1,84 @@
+package testapi
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-chef/chef/cheftest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// record rewrites the cassettes in testdata/cassettes, recording the scenarios against
+// an in-memory cheftest server: go test ./testapi -run TestReplay -record
+var record = flag.Bool("record", false, "record the cassettes against a cheftest server")
+
+// replayed are the scenarios with a cassette in testdata/cassettes
+var replayed = map[string]func(){
+	"databag":     Databag,
+	"environment": Environment,
+	"node":        Node,
+	"role":        Role,
+}
+
+// TestReplay runs the scenarios without a chef server, answering their requests from
+// the cassettes. A request the cassette has no interaction for fails the test.
+func TestReplay(t *testing.T) {
+	for name, scenario := range replayed {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join("testdata", "cassettes", name+".json")
+			url := "https://chef.example.com/organizations/test"
+			mode := cheftest.Replay
+			if *record {
+				srv := cheftest.NewServer()
+				srv.SkipSignatures = true
+				srv.CreateOrganization("test")
+				ts := httptest.NewServer(srv)
+				defer ts.Close()
+				url = ts.URL + "/organizations/test"
+				mode = cheftest.Record
+			}
+			c, err := cheftest.NewCassette(path, mode)
+			require.Nil(t, err)
+
+			output := runScenario(t, c, url, scenario)
+			assert.NotContains(t, output, cheftest.ErrNoInteraction.Error())
+			assert.NotEmpty(t, c.Interactions())
+		})
+	}
+}
+
+// runScenario runs scenario as the testcase command would with the cassette c and
+// returns what it wrote to stdout and stderr
+func runScenario(t *testing.T, c *cheftest.Cassette, url string, scenario func()) string {
+	keyfile := filepath.Join(t.TempDir(), "pivotal.pem")
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	err = os.WriteFile(keyfile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600)
+	require.Nil(t, err)
+
+	args, stdout, stderr, cas := os.Args, os.Stdout, os.Stderr, cassette
+	defer func() {
+		os.Args, os.Stdout, os.Stderr, cassette = args, stdout, stderr, cas
+	}()
+	cassette = func() *cheftest.Cassette { return c }
+	os.Args = []string{"testcase", "scenario", "pivotal", keyfile, url + "/", "false"}
+
+	out, err := os.Create(filepath.Join(t.TempDir(), "output"))
+	require.Nil(t, err)
+	defer out.Close()
+	os.Stdout, os.Stderr = out, out
+
+	scenario()
+
+	data, err := os.ReadFile(out.Name())
+	require.Nil(t, err)
+	return string(data)
+}
@@ -2,13 +2,19 @@
 package testapi
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"strconv"
+	"sync"
 
 	chef "github.com/go-chef/chef"
+	"github.com/go-chef/chef/cheftest"
 )
 
 // client exercise the chef server api
@@ -33,16 +39,25 @@ func Client(cfg *chef.Config) *chef.Client {
 // buildClient creates a connection to a chef server using the chef api.
 // goiardi uses port 4545 by default, chef-zero uses 8889, chef-server uses 443
 func buildClient(cfg *chef.Config, user string, keyfile string, baseurl string, skipssl bool, version string) *chef.Client {
-	key := clientKey(keyfile)
 	if cfg == nil {
 		cfg = &chef.Config{}
 	}
+	cassette := cassette()
+	if cassette != nil && cassette.Mode() == cheftest.Replay {
+		// replayed requests need neither the server certificates nor the real key
+		cfg.Key = replayKey(keyfile)
+	} else {
+		cfg.Key = clientKey(keyfile)
+		cfg.RootCAs = chefCerts()
+	}
+	if cassette != nil {
+		cfg.RoundTripper = cassette.RoundTripper
+	}
 	cfg.Name = user
-	cfg.Key = string(key)
 	cfg.BaseURL = baseurl
 	cfg.SkipSSL = skipssl
-	cfg.RootCAs = chefCerts()
 	cfg.AuthenticationVersion = version
+	cfg.Logger = logger()
 
 	client, err := chef.NewClient(
 		cfg,
@@ -55,6 +70,56 @@ func buildClient(cfg *chef.Config, user string, keyfile string, baseurl string,
 	return client
 }
 
+// logger returns a logger writing to stderr when CHEF_LOG_LEVEL is set.
+// Use CHEF_LOG_LEVEL=debug to see requests and responses, CHEF_LOG_LEVEL=trace to add the bodies.
+func logger() *slog.Logger {
+	var level slog.Level
+	switch os.Getenv("CHEF_LOG_LEVEL") {
+	case "":
+		return nil
+	case "trace":
+		level = chef.LevelTrace
+	default:
+		if err := level.UnmarshalText([]byte(os.Getenv("CHEF_LOG_LEVEL"))); err != nil {
+			level = slog.LevelDebug
+		}
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+}
+
+// cassette returns the cassette named by CHEF_CASSETTE, or nil when it isn't set. All
+// clients share it. CHEF_CASSETTE_MODE=record records the requests to the file, by
+// default they are replayed from it.
+var cassette = sync.OnceValue(func() *cheftest.Cassette {
+	path := os.Getenv("CHEF_CASSETTE")
+	if path == "" {
+		return nil
+	}
+	mode := cheftest.Replay
+	if os.Getenv("CHEF_CASSETTE_MODE") == "record" {
+		mode = cheftest.Record
+	}
+	c, err := cheftest.NewCassette(path, mode)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Issue opening the cassette:", err)
+		os.Exit(1)
+	}
+	return c
+})
+
+// replayKey returns the key in keyfile, or a new key when the file doesn't exist
+func replayKey(keyfile string) string {
+	if key, err := os.ReadFile(keyfile); err == nil {
+		return string(key)
+	}
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Issue generating a key:", err)
+		os.Exit(1)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
 // clientKey reads the pem file containing the credentials needed to use the chef client.
 func clientKey(filepath string) string {
 	key, err := os.ReadFile(filepath)
@@ -75,6 +140,10 @@ func chefCerts() *x509.CertPool {
 	}
 	// Read in the cert file
 	certs, err := os.ReadFile(localCertFile)
+	if os.IsNotExist(err) {
+		// not on the test image, for example when recording against a cheftest server
+		return certPool
+	}
 	if err != nil {
 		log.Fatalf("Failed to append %q to RootCAs: %v", localCertFile, err)
 	}
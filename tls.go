@@ -0,0 +1,143 @@
+package chef
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSConfig returns the tls.Config NewClient uses for the Chef server connection. Use
+// it to configure a transport of your own with the same settings.
+func (cfg *Config) TLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if err := cfg.applyTLS(tlsConfig); err != nil {
+		return nil, err
+	}
+	return tlsConfig, nil
+}
+
+// applyTLS sets the TLS settings made in cfg on tlsConfig
+func (cfg *Config) applyTLS(tlsConfig *tls.Config) error {
+	rootCAs, err := cfg.rootCAs()
+	if err != nil {
+		return err
+	}
+	if cfg.SkipSSL {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if rootCAs != nil {
+		tlsConfig.RootCAs = rootCAs
+	}
+	if len(cfg.ClientCertificates) > 0 {
+		tlsConfig.Certificates = cfg.ClientCertificates
+	}
+	if cfg.GetClientCertificate != nil {
+		tlsConfig.GetClientCertificate = cfg.GetClientCertificate
+	}
+	if cfg.MinTLSVersion != 0 {
+		tlsConfig.MinVersion = cfg.MinTLSVersion
+	}
+	if len(cfg.CipherSuites) > 0 {
+		tlsConfig.CipherSuites = cfg.CipherSuites
+	}
+	if cfg.TLSServerName != "" {
+		tlsConfig.ServerName = cfg.TLSServerName
+	}
+	return nil
+}
+
+// hasTLS reports if any TLS setting is made in cfg. A TrustedCertsDir that doesn't
+// exist, like the default one of a knife config, is no setting as rootCAs ignores it.
+func (cfg *Config) hasTLS() bool {
+	return cfg.SkipSSL || cfg.RootCAs != nil || cfg.hasTrustedCertsDir() || len(cfg.ClientCertificates) > 0 ||
+		cfg.GetClientCertificate != nil || cfg.MinTLSVersion != 0 || len(cfg.CipherSuites) > 0 || cfg.TLSServerName != ""
+}
+
+// hasTrustedCertsDir reports if TrustedCertsDir is set to a path that exists
+func (cfg *Config) hasTrustedCertsDir() bool {
+	if cfg.TrustedCertsDir == "" {
+		return false
+	}
+	_, err := os.Stat(cfg.TrustedCertsDir)
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+// withTLS returns client, or a copy of it with the TLS settings of cfg applied to its
+// transport. Settings can't be applied to transports other than *http.Transport, so
+// making them with such a client is an error.
+func (cfg *Config) withTLS(client *http.Client) (*http.Client, error) {
+	if !cfg.hasTLS() {
+		return client, nil
+	}
+	var tr *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		tr = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		tr = t.Clone()
+	default:
+		return nil, fmt.Errorf("NewClient: cannot apply TLS settings to a Client with transport %T, configure it with Config.TLSConfig instead", t)
+	}
+	if tr.TLSClientConfig == nil {
+		tr.TLSClientConfig = &tls.Config{}
+	}
+	if err := cfg.applyTLS(tr.TLSClientConfig); err != nil {
+		return nil, err
+	}
+	copied := *client
+	copied.Transport = tr
+	return &copied, nil
+}
+
+// CertificateReloader returns a Config.GetClientCertificate function serving the key pair
+// in certFile and keyFile. The files are loaded again when either one changes, so a
+// rotated certificate is used from the next TLS handshake on.
+func CertificateReloader(certFile, keyFile string) (func(*tls.CertificateRequestInfo) (*tls.Certificate, error), error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := r.get(); err != nil {
+		return nil, err
+	}
+	return func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return r.get()
+	}, nil
+}
+
+// certReloader caches a key pair until its files change
+type certReloader struct {
+	certFile, keyFile string
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	modTimes [2]time.Time
+}
+
+func (r *certReloader) get() (*tls.Certificate, error) {
+	var modTimes [2]time.Time
+	for i, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cert != nil && modTimes == r.modTimes {
+		return r.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.cert != nil {
+			// keep the previous certificate while a rotation is half written
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	r.cert, r.modTimes = &cert, modTimes
+	return r.cert, nil
+}
@@ -0,0 +1,187 @@
+package chef
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCert creates a certificate signed by parent, self signed when parent is nil
+func newTestCert(t *testing.T, cn string, serial int64, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+	signerCert, signerKey := tmpl, interface{}(key)
+	if parent != nil {
+		signerCert, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signerCert, &key.PublicKey, signerKey)
+	assert.Nil(t, err)
+	leaf, _ := x509.ParseCertificate(der)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// writeKeyPair writes cert as PEM files into dir
+func writeKeyPair(t *testing.T, dir string, cert tls.Certificate) (certFile, keyFile string) {
+	certFile, keyFile = filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return
+}
+
+// newMTLSServer starts a server requiring client certificates signed by ca
+func newMTLSServer(ca tls.Certificate) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/nodes/node1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"name":"%s"}`, r.TLS.PeerCertificates[0].Subject.CommonName)
+	})
+	ts := httptest.NewUnstartedServer(mux)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	ts.StartTLS()
+	return ts
+}
+
+func TestNewClient_ClientCertificates(t *testing.T) {
+	ca := newTestCert(t, "test ca", 1, nil)
+	clientCert := newTestCert(t, "client-one", 2, &ca)
+	ts := newMTLSServer(ca)
+	defer ts.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+
+	cfg := &Config{Name: userid, Key: privateKeyPKCS1, BaseURL: ts.URL + "/", RootCAs: roots}
+	c, err := NewClient(cfg)
+	assert.Nil(t, err)
+	_, err = c.Nodes.Get("node1")
+	assert.NotNil(t, err, "server requires a client certificate")
+
+	cfg.ClientCertificates = []tls.Certificate{clientCert}
+	c, err = NewClient(cfg)
+	assert.Nil(t, err)
+	node, err := c.Nodes.Get("node1")
+	assert.Nil(t, err)
+	assert.Equal(t, "client-one", node.Name)
+
+	// a custom client without a transport gets the same settings
+	custom := &http.Client{Timeout: time.Minute}
+	cfg.Client = custom
+	c, err = NewClient(cfg)
+	assert.Nil(t, err)
+	node, err = c.Nodes.Get("node1")
+	assert.Nil(t, err)
+	assert.Equal(t, "client-one", node.Name)
+	assert.Nil(t, custom.Transport, "the given client isn't modified")
+	assert.Equal(t, time.Minute, c.Client.Timeout)
+}
+
+func TestCertificateReloader(t *testing.T) {
+	ca := newTestCert(t, "test ca", 1, nil)
+	dir := t.TempDir()
+	certFile, keyFile := writeKeyPair(t, dir, newTestCert(t, "client-one", 2, &ca))
+
+	_, err := CertificateReloader(filepath.Join(dir, "missing.crt"), keyFile)
+	assert.NotNil(t, err)
+
+	get, err := CertificateReloader(certFile, keyFile)
+	assert.Nil(t, err)
+
+	ts := newMTLSServer(ca)
+	defer ts.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+	c, err := NewClient(&Config{Name: userid, Key: privateKeyPKCS1, BaseURL: ts.URL + "/", RootCAs: roots, GetClientCertificate: get})
+	assert.Nil(t, err)
+	node, err := c.Nodes.Get("node1")
+	assert.Nil(t, err)
+	assert.Equal(t, "client-one", node.Name)
+
+	// rotate the certificate
+	writeKeyPair(t, dir, newTestCert(t, "client-two", 3, &ca))
+	later := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(certFile, later, later))
+	cert, err := get(nil)
+	assert.Nil(t, err)
+	leaf, _ := x509.ParseCertificate(cert.Certificate[0])
+	assert.Equal(t, "client-two", leaf.Subject.CommonName)
+
+	c.Client.CloseIdleConnections()
+	node, err = c.Nodes.Get("node1")
+	assert.Nil(t, err)
+	assert.Equal(t, "client-two", node.Name, "new connections use the rotated certificate")
+}
+
+func TestConfig_TLSConfig(t *testing.T) {
+	cfg := &Config{
+		MinTLSVersion: tls.VersionTLS12,
+		CipherSuites:  []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+		TLSServerName: "chef.internal",
+		SkipSSL:       true,
+	}
+	tlsConfig, err := cfg.TLSConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, tlsConfig.CipherSuites)
+	assert.Equal(t, "chef.internal", tlsConfig.ServerName)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+
+	cfg.Name, cfg.Key, cfg.BaseURL = userid, privateKeyPKCS1, "https://chef.example.com/"
+	c, err := NewClient(cfg)
+	assert.Nil(t, err)
+	tr := c.Client.Transport.(*http.Transport)
+	assert.Equal(t, "chef.internal", tr.TLSClientConfig.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS12), tr.TLSClientConfig.MinVersion)
+}
+
+func TestNewClient_TLSErrors(t *testing.T) {
+	// settings that can't be applied to the transport of a custom client
+	cfg := &Config{Name: userid, Key: privateKeyPKCS1, BaseURL: "https://chef.example.com/", TLSServerName: "chef.internal",
+		Client: &http.Client{Transport: newTestRt(http.DefaultTransport)}}
+	_, err := NewClient(cfg)
+	assert.ErrorContains(t, err, "cannot apply TLS settings")
+
+	cfg.TLSServerName = ""
+	_, err = NewClient(cfg)
+	assert.Nil(t, err, "no TLS settings to apply")
+
+	// the default trusted_certs directory of a knife config often doesn't exist
+	cfg.TrustedCertsDir = filepath.Join(t.TempDir(), "trusted_certs")
+	_, err = NewClient(cfg)
+	assert.Nil(t, err, "a missing trusted_certs directory is no TLS setting")
+	assert.Nil(t, os.Mkdir(cfg.TrustedCertsDir, 0o700))
+	_, err = NewClient(cfg)
+	assert.ErrorContains(t, err, "cannot apply TLS settings", "certificates that would be ignored")
+
+	// a trusted_certs path that can't be read as a directory
+	cfg.Client = &http.Client{}
+	cfg.TrustedCertsDir = t.TempDir() + "/certs.pem"
+	assert.Nil(t, os.WriteFile(cfg.TrustedCertsDir, nil, 0o600))
+	_, err = NewClient(cfg)
+	assert.NotNil(t, err)
+}
@@ -1,5 +1,7 @@
 package chef
 
+import "context"
+
 type UniverseService struct {
 	client *Client
 }
@@ -23,8 +25,13 @@ type UniverseVersion struct {
 //
 // https://docs.chef.io/api_chef_server.html#universe
 func (e *UniverseService) Get() (universe Universe, err error) {
+	return e.GetWithContext(context.Background())
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *UniverseService) GetWithContext(ctx context.Context) (universe Universe, err error) {
 	var data map[string]interface{}
-	err = e.client.magicRequestDecoder("GET", "universe", nil, &data)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", "universe", nil, &data)
 	unpackUniverse(&universe, &data)
 	return
 }
@@ -1,6 +1,7 @@
 package chef
 
 import (
+	"context"
 	"errors"
 	"strconv"
 )
@@ -22,8 +23,13 @@ type UpdatedSince struct {
 // This end point has long since been deprecated and is no longer available
 // Calls will always return 404 not found errors
 func (e UpdatedSinceService) Get(sequenceId int64) (updated []UpdatedSince, err error) {
+	return e.GetWithContext(context.Background(), sequenceId)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e UpdatedSinceService) GetWithContext(ctx context.Context, sequenceId int64) (updated []UpdatedSince, err error) {
 	url := "updated_since?seq=" + strconv.FormatInt(sequenceId, 10)
-	err = e.client.magicRequestDecoder("GET", url, nil, &updated)
+	err = e.client.magicRequestDecoderWithContext(ctx, "GET", url, nil, &updated)
 	if err != nil {
 		err = errors.New("Update_since is a deprecated endpoint and always returns 404.")
 	}
@@ -1,6 +1,7 @@
 package chef
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -39,11 +40,16 @@ type UserVerboseResult struct {
 // /users GET
 // Chef API docs: https://docs.chef.io/api_chef_server.html#users
 func (e *UserService) List(filters ...string) (userlist map[string]string, err error) {
+	return e.ListWithContext(context.Background(), filters...)
+}
+
+// ListWithContext is List with a context for cancellation and deadlines.
+func (e *UserService) ListWithContext(ctx context.Context, filters ...string) (userlist map[string]string, err error) {
 	url := "users"
 	if len(filters) > 0 {
 		url += "?" + strings.Join(filters, "&")
 	}
-	err = e.client.magicRequestDecoder("GET", url, nil, &userlist)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "GET", url, nil, &userlist)
 	return
 }
 
@@ -51,12 +57,17 @@ func (e *UserService) List(filters ...string) (userlist map[string]string, err e
 // /users GET
 // Chef API docs: https://docs.chef.io/api_chef_server.html#users
 func (e *UserService) VerboseList(filters ...string) (userlist map[string]UserVerboseResult, err error) {
+	return e.VerboseListWithContext(context.Background(), filters...)
+}
+
+// VerboseListWithContext is VerboseList with a context for cancellation and deadlines.
+func (e *UserService) VerboseListWithContext(ctx context.Context, filters ...string) (userlist map[string]UserVerboseResult, err error) {
 	url := "users"
 	filters = append(filters, "verbose=true")
 	if len(filters) > 0 {
 		url += "?" + strings.Join(filters, "&")
 	}
-	err = e.client.magicRequestDecoder("GET", url, nil, &userlist)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "GET", url, nil, &userlist)
 	return
 }
 
@@ -71,12 +82,42 @@ func (e *UserService) VerboseList(filters ...string) (userlist map[string]UserVe
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#users
 func (e *UserService) Create(user User) (data UserResult, err error) {
+	return e.CreateWithContext(context.Background(), user)
+}
+
+// CreateWithContext is Create with a context for cancellation and deadlines.
+func (e *UserService) CreateWithContext(ctx context.Context, user User) (data UserResult, err error) {
+	if e.client.APIVersion() == 0 {
+		return e.createV0(ctx, user)
+	}
 	body, err := JSONReader(user)
 	if err != nil {
 		return
 	}
 
-	err = e.client.magicRequestDecoder("POST", "users", body, &data)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "POST", "users", body, &data)
+	return
+}
+
+// createV0 creates a user with Server API version 0. That version doesn't know
+// create_key, it generates a key unless a public_key is given and returns the
+// private key at the top level of the result.
+func (e *UserService) createV0(ctx context.Context, user User) (data UserResult, err error) {
+	user.CreateKey = false
+	body, err := JSONReader(user)
+	if err != nil {
+		return
+	}
+
+	var result struct {
+		Uri        string `json:"uri"`
+		PrivateKey string `json:"private_key"`
+	}
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "POST", "users", body, &result)
+	data.Uri = result.Uri
+	if result.PrivateKey != "" {
+		data.ChefKey = ChefKey{Name: "default", PrivateKey: result.PrivateKey, ExpirationDate: "infinity"}
+	}
 	return
 }
 
@@ -90,7 +131,12 @@ func (e *UserService) Create(user User) (data UserResult, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#users-name
 func (e *UserService) Delete(name string) (err error) {
-	err = e.client.magicRequestDecoder("DELETE", "users/"+name, nil, nil)
+	return e.DeleteWithContext(context.Background(), name)
+}
+
+// DeleteWithContext is Delete with a context for cancellation and deadlines.
+func (e *UserService) DeleteWithContext(ctx context.Context, name string) (err error) {
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "DELETE", "users/"+name, nil, nil)
 	return
 }
 
@@ -103,8 +149,13 @@ func (e *UserService) Delete(name string) (err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#users-name
 func (e *UserService) Get(name string) (user User, err error) {
+	return e.GetWithContext(context.Background(), name)
+}
+
+// GetWithContext is Get with a context for cancellation and deadlines.
+func (e *UserService) GetWithContext(ctx context.Context, name string) (user User, err error) {
 	url := fmt.Sprintf("users/%s", name)
-	err = e.client.magicRequestDecoder("GET", url, nil, &user)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "GET", url, nil, &user)
 	return
 }
 
@@ -118,9 +169,14 @@ func (e *UserService) Get(name string) (user User, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#users-name
 func (e *UserService) Update(name string, user User) (userUpdate UserResult, err error) {
+	return e.UpdateWithContext(context.Background(), name, user)
+}
+
+// UpdateWithContext is Update with a context for cancellation and deadlines.
+func (e *UserService) UpdateWithContext(ctx context.Context, name string, user User) (userUpdate UserResult, err error) {
 	url := fmt.Sprintf("users/%s", name)
 	body, err := JSONReader(user)
-	err = e.client.magicRequestDecoder("PUT", url, body, &userUpdate)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "PUT", url, body, &userUpdate)
 	return
 }
 
@@ -133,8 +189,13 @@ func (e *UserService) Update(name string, user User) (userUpdate UserResult, err
 //
 // Chef API docs: https://docs.chef.io/api_chef_server/#usersuserkeys
 func (e *UserService) ListKeys(name string) (userkeys []KeyItem, err error) {
+	return e.ListKeysWithContext(context.Background(), name)
+}
+
+// ListKeysWithContext is ListKeys with a context for cancellation and deadlines.
+func (e *UserService) ListKeysWithContext(ctx context.Context, name string) (userkeys []KeyItem, err error) {
 	url := fmt.Sprintf("users/%s/keys", name)
-	err = e.client.magicRequestDecoder("GET", url, nil, &userkeys)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "GET", url, nil, &userkeys)
 	return
 }
 
@@ -148,9 +209,14 @@ func (e *UserService) ListKeys(name string) (userkeys []KeyItem, err error) {
 //
 // Chef API docs: https://docs.chef.io/api_chef_server.html#users-name
 func (e *UserService) AddKey(name string, keyadd AccessKey) (key KeyItem, err error) {
+	return e.AddKeyWithContext(context.Background(), name, keyadd)
+}
+
+// AddKeyWithContext is AddKey with a context for cancellation and deadlines.
+func (e *UserService) AddKeyWithContext(ctx context.Context, name string, keyadd AccessKey) (key KeyItem, err error) {
 	url := fmt.Sprintf("users/%s/keys", name)
 	body, err := JSONReader(keyadd)
-	err = e.client.magicRequestDecoder("POST", url, body, &key)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "POST", url, body, &key)
 	return
 }
 
@@ -163,8 +229,13 @@ func (e *UserService) AddKey(name string, keyadd AccessKey) (key KeyItem, err er
 //
 // Chef API docs: https://docs.chef.io/api_chef_server/#usersuserkeys
 func (e *UserService) DeleteKey(name string, keyname string) (key AccessKey, err error) {
+	return e.DeleteKeyWithContext(context.Background(), name, keyname)
+}
+
+// DeleteKeyWithContext is DeleteKey with a context for cancellation and deadlines.
+func (e *UserService) DeleteKeyWithContext(ctx context.Context, name string, keyname string) (key AccessKey, err error) {
 	url := fmt.Sprintf("users/%s/keys/%s", name, keyname)
-	err = e.client.magicRequestDecoder("DELETE", url, nil, &key)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "DELETE", url, nil, &key)
 	return
 }
 
@@ -177,8 +248,13 @@ func (e *UserService) DeleteKey(name string, keyname string) (key AccessKey, err
 //
 // Chef API docs: https://docs.chef.io/api_chef_server/#usersuserkeys
 func (e *UserService) GetKey(name string, keyname string) (key AccessKey, err error) {
+	return e.GetKeyWithContext(context.Background(), name, keyname)
+}
+
+// GetKeyWithContext is GetKey with a context for cancellation and deadlines.
+func (e *UserService) GetKeyWithContext(ctx context.Context, name string, keyname string) (key AccessKey, err error) {
 	url := fmt.Sprintf("users/%s/keys/%s", name, keyname)
-	err = e.client.magicRequestDecoder("GET", url, nil, &key)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "GET", url, nil, &key)
 	return
 }
 
@@ -191,8 +267,13 @@ func (e *UserService) GetKey(name string, keyname string) (key AccessKey, err er
 //
 // Chef API docs: https://docs.chef.io/api_chef_server/#usersuserkeys
 func (e *UserService) UpdateKey(username string, keyname string, keyUp AccessKey) (userkey AccessKey, err error) {
+	return e.UpdateKeyWithContext(context.Background(), username, keyname, keyUp)
+}
+
+// UpdateKeyWithContext is UpdateKey with a context for cancellation and deadlines.
+func (e *UserService) UpdateKeyWithContext(ctx context.Context, username string, keyname string, keyUp AccessKey) (userkey AccessKey, err error) {
 	url := fmt.Sprintf("users/%s/keys/%s", username, keyname)
 	body, err := JSONReader(keyUp)
-	err = e.client.magicRequestDecoder("PUT", url, body, &userkey)
+	err = e.client.Global().magicRequestDecoderWithContext(ctx, "PUT", url, body, &userkey)
 	return
 }
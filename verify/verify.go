@@ -0,0 +1,324 @@
+// Package verify checks Chef request signatures on the server side. It is the
+// inverse of chef.AuthConfig.SignRequest and accepts authentication protocol
+// versions 1.0, 1.1 and 1.3.
+//
+// A Verifier can be called directly for each request or wrapped around an
+// http.Handler with Middleware:
+//
+//	v := verify.New(func(ctx context.Context, userID string) (*rsa.PublicKey, error) {
+//		return keys[userID], nil
+//	})
+//	http.ListenAndServe(":8080", v.Middleware(handler))
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	chef "github.com/go-chef/chef"
+)
+
+// DefaultMaxSkew is the clock skew the Chef server allows between the request timestamp and its own clock
+const DefaultMaxSkew = 15 * time.Minute
+
+// DefaultMaxBodyBytes is the largest request body read to check the content hash
+const DefaultMaxBodyBytes = 8 << 20
+
+// Errors returned by Verify. They are wrapped with details of the failure.
+var (
+	ErrMissingHeader      = errors.New("missing authentication header")
+	ErrUnsupportedVersion = errors.New("unsupported authentication protocol version")
+	ErrClockSkew          = errors.New("request timestamp outside of the allowed clock skew")
+	ErrContentHash        = errors.New("content hash does not match the request body")
+	ErrBodyTooLarge       = errors.New("request body too large")
+	ErrUnknownUser        = errors.New("no public key for user")
+	ErrBadSignature       = errors.New("invalid request signature")
+)
+
+// KeyLookup returns the public key of the named Chef user or client
+type KeyLookup func(ctx context.Context, userID string) (*rsa.PublicKey, error)
+
+// Verifier validates signed Chef requests
+type Verifier struct {
+	// Lookup finds the public key for the X-Ops-UserId of the request
+	Lookup KeyLookup
+
+	// MaxSkew is the largest allowed difference between X-Ops-Timestamp and Now. Defaults to DefaultMaxSkew.
+	MaxSkew time.Duration
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	// MaxBodyBytes limits the request body read before the signature is checked. Defaults to DefaultMaxBodyBytes.
+	MaxBodyBytes int64
+
+	// Logger receives the full reason of the requests Middleware rejects. Logging is off when nil.
+	Logger *slog.Logger
+}
+
+// New returns a Verifier using lookup to find public keys
+func New(lookup KeyLookup) *Verifier {
+	return &Verifier{Lookup: lookup}
+}
+
+var (
+	versionRe   = regexp.MustCompile(`version=(\d+\.\d+)`)
+	algorithmRe = regexp.MustCompile(`algorithm=(\w+)`)
+	authRe      = regexp.MustCompile(`^X-Ops-Authorization-(\d+)$`)
+)
+
+// Verify checks the signature of r and returns the authenticated user id.
+// The request body is read to check the content hash and replaced so later
+// handlers can read it again.
+func (v *Verifier) Verify(r *http.Request) (userID string, err error) {
+	userID = r.Header.Get("X-Ops-UserId")
+	timestamp := r.Header.Get("X-Ops-Timestamp")
+	contentHash := r.Header.Get("X-Ops-Content-Hash")
+	sign := r.Header.Get("X-Ops-Sign")
+	for name, val := range map[string]string{"X-Ops-UserId": userID, "X-Ops-Timestamp": timestamp, "X-Ops-Content-Hash": contentHash, "X-Ops-Sign": sign} {
+		if val == "" {
+			return "", fmt.Errorf("%w: %s", ErrMissingHeader, name)
+		}
+	}
+
+	version, err := signVersion(sign)
+	if err != nil {
+		return "", err
+	}
+
+	if err = v.checkTimestamp(timestamp); err != nil {
+		return "", err
+	}
+
+	if err = v.checkContentHash(r, version, contentHash); err != nil {
+		return "", err
+	}
+
+	sig, err := signature(r.Header)
+	if err != nil {
+		return "", err
+	}
+
+	if v.Lookup == nil {
+		return "", fmt.Errorf("%w %s", ErrUnknownUser, userID)
+	}
+	key, err := v.Lookup(r.Context(), userID)
+	if err != nil {
+		return "", fmt.Errorf("%w %s: %v", ErrUnknownUser, userID, err)
+	}
+	if key == nil {
+		return "", fmt.Errorf("%w %s", ErrUnknownUser, userID)
+	}
+
+	// signatures made by clients that drop the leading zero bytes are accepted, as OpenSSL does
+	if k := key.Size(); len(sig) < k {
+		sig = append(make([]byte, k-len(sig)), sig...)
+	}
+
+	content := signatureContent(r, version)
+	if version == chef.AuthVersion13 {
+		hashed := sha256.Sum256([]byte(content))
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], sig)
+	} else {
+		// protocols 1.0 and 1.1 are a raw private key encryption of the content
+		err = rsa.VerifyPKCS1v15(key, crypto.Hash(0), []byte(content), sig)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w for %s", ErrBadSignature, userID)
+	}
+	return userID, nil
+}
+
+// signVersion extracts the protocol version from the X-Ops-Sign header
+func signVersion(sign string) (chef.AuthVersion, error) {
+	match := versionRe.FindStringSubmatch(sign)
+	if match == nil {
+		return "", fmt.Errorf("%w: malformed X-Ops-Sign %q", ErrUnsupportedVersion, sign)
+	}
+	version := match[1]
+	algorithm := "sha1"
+	if alg := algorithmRe.FindStringSubmatch(sign); alg != nil {
+		algorithm = alg[1]
+	}
+	switch {
+	case (version == chef.AuthVersion10 || version == chef.AuthVersion11) && algorithm == "sha1":
+	case version == chef.AuthVersion13 && (algorithm == "sha256" || !strings.Contains(sign, "algorithm=")):
+	default:
+		return "", fmt.Errorf("%w: %s with algorithm %s", ErrUnsupportedVersion, version, algorithm)
+	}
+	return version, nil
+}
+
+// checkTimestamp verifies the X-Ops-Timestamp is within MaxSkew of now
+func (v *Verifier) checkTimestamp(timestamp string) error {
+	ts, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return fmt.Errorf("%w: unparseable timestamp %q", ErrClockSkew, timestamp)
+	}
+	now := time.Now
+	if v.Now != nil {
+		now = v.Now
+	}
+	skew := v.MaxSkew
+	if skew <= 0 {
+		skew = DefaultMaxSkew
+	}
+	diff := now().Sub(ts)
+	if diff < -skew || diff > skew {
+		return fmt.Errorf("%w: %s differs by %v", ErrClockSkew, timestamp, diff.Round(time.Second))
+	}
+	return nil
+}
+
+// checkContentHash compares the body hash with the X-Ops-Content-Hash header.
+// The body is hashed as it is read, up to MaxBodyBytes.
+func (v *Verifier) checkContentHash(r *http.Request, version chef.AuthVersion, contentHash string) error {
+	limit := v.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	if r.ContentLength > limit {
+		return fmt.Errorf("%w: %d bytes", ErrBodyTooLarge, r.ContentLength)
+	}
+
+	var h hash.Hash
+	if version == chef.AuthVersion13 {
+		h = sha256.New()
+	} else {
+		h = sha1.New()
+	}
+	if r.Body != nil {
+		var body bytes.Buffer
+		n, err := io.Copy(io.MultiWriter(h, &body), io.LimitReader(r.Body, limit+1))
+		_ = r.Body.Close()
+		var tooLarge *http.MaxBytesError
+		if n > limit || errors.As(err, &tooLarge) {
+			return fmt.Errorf("%w: more than %d bytes", ErrBodyTooLarge, limit)
+		}
+		if err != nil {
+			return err
+		}
+		r.Body = io.NopCloser(&body)
+	}
+
+	if base64.StdEncoding.EncodeToString(h.Sum(nil)) != contentHash {
+		return ErrContentHash
+	}
+	return nil
+}
+
+// signature reassembles and decodes the X-Ops-Authorization-N headers
+func signature(header http.Header) ([]byte, error) {
+	parts := map[int]string{}
+	for key := range header {
+		if match := authRe.FindStringSubmatch(http.CanonicalHeaderKey(key)); match != nil {
+			i, _ := strconv.Atoi(match[1])
+			parts[i] = header.Get(key)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("%w: X-Ops-Authorization-1", ErrMissingHeader)
+	}
+
+	var encoded strings.Builder
+	for i := 1; i <= len(parts); i++ {
+		part, ok := parts[i]
+		if !ok {
+			return nil, fmt.Errorf("%w: X-Ops-Authorization-%d", ErrMissingHeader, i)
+		}
+		encoded.WriteString(part)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(encoded.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+	return sig, nil
+}
+
+// signatureContent rebuilds the canonical string the client signed
+func signatureContent(r *http.Request, version chef.AuthVersion) string {
+	endpoint := r.URL.Path
+	if endpoint != "" {
+		endpoint = path.Clean(endpoint)
+	}
+	vals := map[string]string{
+		"Method":                   r.Method,
+		"Hashed Path":              chef.HashStr(endpoint),
+		"Path":                     endpoint,
+		"X-Ops-Content-Hash":       r.Header.Get("X-Ops-Content-Hash"),
+		"X-Ops-Sign":               r.Header.Get("X-Ops-Sign"),
+		"X-Ops-Timestamp":          r.Header.Get("X-Ops-Timestamp"),
+		"X-Ops-UserId":             r.Header.Get("X-Ops-UserId"),
+		"X-Ops-Server-API-Version": r.Header.Get("X-Ops-Server-API-Version"),
+	}
+	return chef.AuthConfig{AuthenticationVersion: version}.SignatureContent(vals)
+}
+
+type contextKey struct{}
+
+// UserID returns the user id authenticated by Middleware
+func UserID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
+
+// Middleware verifies every request before passing it to next. Requests that
+// fail verification get a 401 response with a Chef style json error body, or
+// a 413 when the body is larger than MaxBodyBytes. The body only names the kind
+// of failure, the details, which may include the error of Lookup, go to Logger.
+// The authenticated user id is available to next through UserID.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := v.Verify(r)
+		if err != nil {
+			if v.Logger != nil {
+				v.Logger.LogAttrs(r.Context(), slog.LevelWarn, "chef request verification failed",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("user", r.Header.Get("X-Ops-UserId")),
+					slog.String("error", err.Error()),
+				)
+			}
+			status := http.StatusUnauthorized
+			if errors.Is(err, ErrBodyTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_ = json.NewEncoder(w).Encode(map[string][]string{"error": {publicError(err).Error()}})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, userID)))
+	})
+}
+
+// failures are the errors Middleware reports to clients, without their details
+var failures = []error{ErrMissingHeader, ErrUnsupportedVersion, ErrClockSkew, ErrContentHash, ErrBodyTooLarge, ErrUnknownUser, ErrBadSignature}
+
+// publicError returns the sentinel error err wraps, safe to send to an unauthenticated client
+func publicError(err error) error {
+	for _, failure := range failures {
+		if errors.Is(err, failure) {
+			return failure
+		}
+	}
+	return errors.New("authentication failed")
+}
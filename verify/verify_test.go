@@ -0,0 +1,241 @@
+package verify
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	chef "github.com/go-chef/chef"
+	"github.com/stretchr/testify/assert"
+)
+
+var testKey *rsa.PrivateKey
+
+func init() {
+	var err error
+	testKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func testKeyPEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(testKey)}))
+}
+
+func lookup(ctx context.Context, userID string) (*rsa.PublicKey, error) {
+	if userID == "tester" {
+		return &testKey.PublicKey, nil
+	}
+	return nil, errors.New("no such user")
+}
+
+func newServer(v *Verifier, seen *string) *httptest.Server {
+	return httptest.NewServer(v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen, _ = UserID(r.Context())
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"received": %q}`, string(body))
+	})))
+}
+
+func newClient(t *testing.T, url, version string) *chef.Client {
+	c, err := chef.NewClient(&chef.Config{
+		Name:                  "tester",
+		Key:                   testKeyPEM(),
+		BaseURL:               url + "/organizations/clownco/",
+		AuthenticationVersion: version,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestVerify_Versions(t *testing.T) {
+	for _, version := range []string{chef.AuthVersion10, chef.AuthVersion11, chef.AuthVersion13} {
+		var seen string
+		ts := newServer(New(lookup), &seen)
+		c := newClient(t, ts.URL, version)
+
+		_, err := c.Nodes.Post(chef.NewNode("node1"))
+		assert.Nil(t, err, "signed POST accepted with protocol %s", version)
+		assert.Equal(t, "tester", seen, "user id passed to the handler for protocol %s", version)
+
+		_, err = c.Nodes.Get("node1")
+		assert.Nil(t, err, "signed GET accepted with protocol %s", version)
+		ts.Close()
+	}
+}
+
+func signedRequest(t *testing.T, version, body string) *http.Request {
+	c := newClient(t, "http://chef.example.com", version)
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := c.NewRequest("PUT", "nodes/node1", reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestVerify_Failures(t *testing.T) {
+	v := New(lookup)
+
+	req := signedRequest(t, chef.AuthVersion13, `{"name":"node1"}`)
+	user, err := v.Verify(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "tester", user)
+
+	req = signedRequest(t, chef.AuthVersion13, `{"name":"node1"}`)
+	req.Body = io.NopCloser(strings.NewReader(`{"name":"node2"}`))
+	_, err = v.Verify(req)
+	assert.ErrorIs(t, err, ErrContentHash, "tampered body")
+
+	req = signedRequest(t, chef.AuthVersion10, "")
+	req.URL.Path = "/organizations/clownco/nodes/node2"
+	_, err = v.Verify(req)
+	assert.ErrorIs(t, err, ErrBadSignature, "tampered path")
+
+	req = signedRequest(t, chef.AuthVersion11, "")
+	req.Header.Del("X-Ops-Authorization-2")
+	_, err = v.Verify(req)
+	assert.ErrorIs(t, err, ErrMissingHeader, "gap in the authorization headers")
+
+	req = signedRequest(t, chef.AuthVersion10, "")
+	req.Header.Set("X-Ops-UserId", "mallory")
+	_, err = v.Verify(req)
+	assert.ErrorIs(t, err, ErrUnknownUser, "unknown user")
+
+	req = signedRequest(t, chef.AuthVersion10, "")
+	req.Header.Set("X-Ops-Sign", "algorithm=sha1;version=2.0")
+	_, err = v.Verify(req)
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	req = signedRequest(t, chef.AuthVersion10, "")
+	req.Header.Del("X-Ops-Timestamp")
+	_, err = v.Verify(req)
+	assert.ErrorIs(t, err, ErrMissingHeader)
+
+	skewed := New(lookup)
+	skewed.Now = func() time.Time { return time.Now().Add(time.Hour) }
+	_, err = skewed.Verify(signedRequest(t, chef.AuthVersion13, ""))
+	assert.ErrorIs(t, err, ErrClockSkew, "old timestamp")
+	skewed.MaxSkew = 2 * time.Hour
+	_, err = skewed.Verify(signedRequest(t, chef.AuthVersion13, ""))
+	assert.Nil(t, err, "wider skew window")
+}
+
+func TestMiddleware_Rejects(t *testing.T) {
+	var seen string
+	ts := newServer(New(lookup), &seen)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/organizations/clownco/nodes")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	body, _ := io.ReadAll(res.Body)
+	assert.Contains(t, string(body), `"error":["missing authentication header`)
+	assert.Equal(t, "", seen, "handler not called")
+
+	c, _ := chef.NewClient(&chef.Config{Name: "mallory", Key: testKeyPEM(), BaseURL: ts.URL + "/"})
+	_, err = c.Nodes.List()
+	assert.ErrorIs(t, err, chef.ErrUnauthorized)
+}
+
+func TestVerify_LeadingZeroSignature(t *testing.T) {
+	v := New(lookup)
+	// about one signature in 256 starts with a zero byte
+	for i := 0; i < 5000; i++ {
+		req := signedRequest(t, chef.AuthVersion10, fmt.Sprintf(`{"name":"node%d"}`, i))
+		sig, err := signature(req.Header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig[0] != 0 {
+			continue
+		}
+		assert.Len(t, sig, testKey.Size(), "signature keeps its leading zero")
+		_, err = v.Verify(req)
+		assert.Nil(t, err)
+
+		// clients that strip the leading zero are accepted too
+		for key := range req.Header {
+			if authRe.MatchString(key) {
+				req.Header.Del(key)
+			}
+		}
+		for j, part := range chef.Base64BlockEncode(sig[1:], 60) {
+			req.Header.Set(fmt.Sprintf("X-Ops-Authorization-%d", j+1), part)
+		}
+		req.Body = io.NopCloser(strings.NewReader(fmt.Sprintf(`{"name":"node%d"}`, i)))
+		_, err = v.Verify(req)
+		assert.Nil(t, err, "signature without its leading zero")
+		return
+	}
+	t.Fatal("no signature with a leading zero byte")
+}
+
+func TestMiddleware_BodyLimit(t *testing.T) {
+	var seen string
+	v := New(lookup)
+	v.MaxBodyBytes = 64
+	ts := newServer(v, &seen)
+	defer ts.Close()
+
+	req, _ := http.NewRequest("POST", ts.URL+"/organizations/clownco/nodes", strings.NewReader(strings.Repeat("x", 65)))
+	req.Header.Set("X-Ops-UserId", "mallory")
+	req.Header.Set("X-Ops-Timestamp", time.Now().UTC().Format(time.RFC3339))
+	req.Header.Set("X-Ops-Content-Hash", chef.HashStr(""))
+	req.Header.Set("X-Ops-Sign", "algorithm=sha1;version=1.0")
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+	assert.Equal(t, "", seen, "handler not called")
+
+	// bodies of unknown length are cut off at the limit too
+	req = signedRequest(t, chef.AuthVersion13, strings.Repeat("x", 65))
+	req.ContentLength = -1
+	_, err = v.Verify(req)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+
+	req = signedRequest(t, chef.AuthVersion13, strings.Repeat("x", 64))
+	_, err = v.Verify(req)
+	assert.Nil(t, err)
+	body, _ := io.ReadAll(req.Body)
+	assert.Len(t, body, 64, "body is readable after the check")
+}
+
+func TestMiddleware_HidesLookupErrors(t *testing.T) {
+	var logs strings.Builder
+	v := New(func(ctx context.Context, userID string) (*rsa.PublicKey, error) {
+		return nil, errors.New("ldap: dial tcp 10.0.0.5:636: connection refused")
+	})
+	v.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+	var seen string
+	ts := newServer(v, &seen)
+	defer ts.Close()
+
+	c := newClient(t, ts.URL, chef.AuthVersion13)
+	req, err := c.NewRequest("GET", "nodes/node1", nil)
+	assert.Nil(t, err)
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	body, _ := io.ReadAll(res.Body)
+	assert.JSONEq(t, `{"error": ["no public key for user"]}`, string(body))
+	assert.Contains(t, logs.String(), "10.0.0.5:636", "details are logged")
+}